@@ -1,14 +1,15 @@
 package main
 
 import (
+	"context"
 	"log"
 	"os"
+	"time"
 
-	"github.com/gofiber/fiber/v2"
-	"github.com/gofiber/fiber/v2/middleware/cors"
-	"github.com/gofiber/fiber/v2/middleware/logger"
-	"github.com/gofiber/fiber/v2/middleware/recover"
-
+	"rental-property-mgmt/internal/app"
+	"rental-property-mgmt/internal/middleware"
+	"rental-property-mgmt/internal/repository"
+	"rental-property-mgmt/internal/services"
 	"rental-property-mgmt/pkg/database"
 )
 
@@ -17,59 +18,47 @@ func main() {
 	if os.Getenv("ENV") == "" {
 		os.Setenv("ENV", "development")
 	}
+	if err := middleware.CheckJWTSecret(); err != nil {
+		log.Fatal("Refusing to start: ", err)
+	}
 
 	// Connect to database
-	if err := database.Connect(); err != nil {
+	db, err := database.Connect()
+	if err != nil {
 		log.Fatal("Failed to connect to database:", err)
 	}
-	defer database.Close()
+	defer database.Close(db)
 
-	// Run migrations
-	if err := database.AutoMigrate(); err != nil {
-		log.Fatal("Failed to run migrations:", err)
+	// `migrate up|down [steps]|status` manages the schema and exits
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := runMigrate(db, os.Args[2:]); err != nil {
+			log.Fatal("Migration failed: ", err)
+		}
+		return
 	}
 
-	// Initialize Fiber app
-	app := fiber.New(fiber.Config{
-		ErrorHandler: func(c *fiber.Ctx, err error) error {
-			code := fiber.StatusInternalServerError
-			if e, ok := err.(*fiber.Error); ok {
-				code = e.Code
-			}
-
-			return c.Status(code).JSON(fiber.Map{
-				"error": err.Error(),
-			})
-		},
-	})
-
-	// Middleware
-	app.Use(recover.New())
-	app.Use(logger.New())
-	app.Use(cors.New(cors.Config{
-		AllowOrigins: getEnv("CORS_ORIGINS", "http://localhost:5173"),
-		AllowHeaders: "Origin, Content-Type, Accept, Authorization",
-		AllowMethods: "GET, POST, PUT, DELETE, OPTIONS",
-	}))
-
-	// API routes
-	api := app.Group("/api/v1")
+	// Apply pending migrations unless DB_AUTO_MIGRATE=false; a schema newer
+	// than this build is always refused
+	if err := database.EnsureSchema(db, getEnv("DB_AUTO_MIGRATE", "true") != "false"); err != nil {
+		log.Fatal("Database schema not ready: ", err)
+	}
 
-	// Health check
-	api.Get("/health", func(c *fiber.Ctx) error {
-		return c.JSON(fiber.Map{
-			"status":  "ok",
-			"message": "Rental Property Management API is running",
-		})
-	})
+	store := repository.NewGormStore(db)
+	server := app.New(store)
 
-	// TODO: Add route handlers here
-	// This is where we'll add authentication, property, and other route handlers
+	// Refresh provider valuations on a schedule, e.g. VALUATION_REFRESH_INTERVAL=24h
+	if interval := os.Getenv("VALUATION_REFRESH_INTERVAL"); interval != "" {
+		every, err := time.ParseDuration(interval)
+		if err != nil || every <= 0 {
+			log.Fatal("Invalid VALUATION_REFRESH_INTERVAL:", interval)
+		}
+		go services.NewValuationService(store).RunRefresh(context.Background(), every)
+	}
 
 	// Start server
 	port := getEnv("PORT", "8080")
 	log.Printf("Server starting on port %s", port)
-	log.Fatal(app.Listen(":" + port))
+	log.Fatal(server.Listen(":" + port))
 }
 
 func getEnv(key, defaultValue string) string {
@@ -0,0 +1,118 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	"rental-property-mgmt/internal/middleware"
+	"rental-property-mgmt/internal/models"
+	"rental-property-mgmt/internal/services"
+)
+
+// AssumptionProfileHandler serves market assumption profile endpoints
+type AssumptionProfileHandler struct {
+	profiles *services.AssumptionProfileService
+}
+
+// NewAssumptionProfileHandler creates a new assumption profile handler
+func NewAssumptionProfileHandler(profiles *services.AssumptionProfileService) *AssumptionProfileHandler {
+	return &AssumptionProfileHandler{profiles: profiles}
+}
+
+type assumptionProfileRequest struct {
+	Name             string   `json:"name" validate:"required,max=100"`
+	Scope            string   `json:"scope" validate:"omitempty,oneof=user team"`
+	ZipCode          *string  `json:"zip_code" validate:"omitempty,len=5,numeric"`
+	Metro            *string  `json:"metro" validate:"omitempty,max=100"`
+	VacancyRate      *float64 `json:"vacancy_rate" validate:"omitempty,gte=0,lte=1"`
+	MaintenancePct   *float64 `json:"maintenance_pct" validate:"omitempty,gte=0,lte=1"`
+	ManagementPct    *float64 `json:"management_pct" validate:"omitempty,gte=0,lte=1"`
+	TaxRatePct       *float64 `json:"tax_rate_pct" validate:"omitempty,gte=0,lte=100"`
+	InsurancePer1000 *float64 `json:"insurance_per_1000" validate:"omitempty,gte=0"`
+	RentGrowthPct    *float64 `json:"rent_growth_pct"`
+	AppreciationPct  *float64 `json:"appreciation_pct"`
+}
+
+func (r *assumptionProfileRequest) toModel() *models.AssumptionProfile {
+	return &models.AssumptionProfile{
+		Name:             r.Name,
+		Scope:            r.Scope,
+		ZipCode:          r.ZipCode,
+		Metro:            r.Metro,
+		VacancyRate:      r.VacancyRate,
+		MaintenancePct:   r.MaintenancePct,
+		ManagementPct:    r.ManagementPct,
+		TaxRatePct:       r.TaxRatePct,
+		InsurancePer1000: r.InsurancePer1000,
+		RentGrowthPct:    r.RentGrowthPct,
+		AppreciationPct:  r.AppreciationPct,
+	}
+}
+
+// List handles GET /assumption-profiles
+func (h *AssumptionProfileHandler) List(c *fiber.Ctx) error {
+	profiles, err := h.profiles.List(middleware.CurrentUserID(c))
+	if err != nil {
+		return err
+	}
+	return c.JSON(profiles)
+}
+
+// Get handles GET /assumption-profiles/:id
+func (h *AssumptionProfileHandler) Get(c *fiber.Ctx) error {
+	id, err := parseUUIDParam(c, "id")
+	if err != nil {
+		return err
+	}
+
+	profile, err := h.profiles.Get(middleware.CurrentUserID(c), id)
+	if err != nil {
+		return serviceError(err)
+	}
+	return c.JSON(profile)
+}
+
+// Create handles POST /assumption-profiles
+func (h *AssumptionProfileHandler) Create(c *fiber.Ctx) error {
+	var req assumptionProfileRequest
+	if err := parseBody(c, &req); err != nil {
+		return err
+	}
+
+	profile := req.toModel()
+	if err := h.profiles.Create(middleware.CurrentUserID(c), profile); err != nil {
+		return serviceError(err)
+	}
+	return c.Status(fiber.StatusCreated).JSON(profile)
+}
+
+// Update handles PUT /assumption-profiles/:id
+func (h *AssumptionProfileHandler) Update(c *fiber.Ctx) error {
+	id, err := parseUUIDParam(c, "id")
+	if err != nil {
+		return err
+	}
+
+	var req assumptionProfileRequest
+	if err := parseBody(c, &req); err != nil {
+		return err
+	}
+
+	profile, err := h.profiles.Update(middleware.CurrentUserID(c), id, req.toModel())
+	if err != nil {
+		return serviceError(err)
+	}
+	return c.JSON(profile)
+}
+
+// Delete handles DELETE /assumption-profiles/:id
+func (h *AssumptionProfileHandler) Delete(c *fiber.Ctx) error {
+	id, err := parseUUIDParam(c, "id")
+	if err != nil {
+		return err
+	}
+
+	if err := h.profiles.Delete(middleware.CurrentUserID(c), id); err != nil {
+		return serviceError(err)
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	"rental-property-mgmt/internal/middleware"
+	"rental-property-mgmt/internal/services"
+)
+
+// AuthHandler serves registration, login and logout
+type AuthHandler struct {
+	users *services.UserService
+}
+
+// NewAuthHandler creates a new auth handler
+func NewAuthHandler(users *services.UserService) *AuthHandler {
+	return &AuthHandler{users: users}
+}
+
+type registerRequest struct {
+	Email     string `json:"email" validate:"required,email"`
+	Password  string `json:"password" validate:"required,min=8"`
+	FirstName string `json:"first_name" validate:"required,max=50"`
+	LastName  string `json:"last_name" validate:"required,max=50"`
+}
+
+type loginRequest struct {
+	Email    string `json:"email" validate:"required,email"`
+	Password string `json:"password" validate:"required"`
+}
+
+// Register handles POST /auth/register
+func (h *AuthHandler) Register(c *fiber.Ctx) error {
+	var req registerRequest
+	if err := parseBody(c, &req); err != nil {
+		return err
+	}
+
+	user, err := h.users.Register(req.Email, req.Password, req.FirstName, req.LastName)
+	if err != nil {
+		return serviceError(err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(user.PublicUser())
+}
+
+// Login handles POST /auth/login
+func (h *AuthHandler) Login(c *fiber.Ctx) error {
+	var req loginRequest
+	if err := parseBody(c, &req); err != nil {
+		return err
+	}
+
+	user, err := h.users.Authenticate(req.Email, req.Password)
+	if err != nil {
+		return serviceError(err)
+	}
+
+	token, err := middleware.GenerateToken(user.ID, user.Email)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(fiber.Map{
+		"token": token,
+		"user":  user.PublicUser(),
+	})
+}
+
+// Logout handles POST /auth/logout. Tokens are stateless, so the client
+// simply discards its token.
+func (h *AuthHandler) Logout(c *fiber.Ctx) error {
+	return c.JSON(fiber.Map{"message": "Logged out"})
+}
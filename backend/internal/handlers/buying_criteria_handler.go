@@ -0,0 +1,206 @@
+package handlers
+
+import (
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+
+	"rental-property-mgmt/internal/middleware"
+	"rental-property-mgmt/internal/models"
+	"rental-property-mgmt/internal/services"
+)
+
+// BuyingCriteriaHandler serves buying-box criteria endpoints
+type BuyingCriteriaHandler struct {
+	criteria *services.BuyingCriteriaService
+}
+
+// NewBuyingCriteriaHandler creates a new buying criteria handler
+func NewBuyingCriteriaHandler(criteria *services.BuyingCriteriaService) *BuyingCriteriaHandler {
+	return &BuyingCriteriaHandler{criteria: criteria}
+}
+
+type buyingCriteriaRequest struct {
+	Name                    string                         `json:"name" validate:"required,max=100"`
+	MinCapRate              *float64                       `json:"min_cap_rate" validate:"omitempty,gte=0"`
+	MinCashOnCash           *float64                       `json:"min_cash_on_cash" validate:"omitempty,gte=0"`
+	MaxPurchasePrice        *float64                       `json:"max_purchase_price" validate:"omitempty,gt=0"`
+	MinRentToValue          *float64                       `json:"min_rent_to_value" validate:"omitempty,gte=0"`
+	MaxYearBuilt            *int                           `json:"max_year_built" validate:"omitempty,gte=1800"`
+	MinYearBuilt            *int                           `json:"min_year_built" validate:"omitempty,gte=1800"`
+	LocationPreferences     models.LocationPreferences     `json:"location_preferences"`
+	PropertyTypePreferences models.PropertyTypePreferences `json:"property_type_preferences"`
+	CustomRules             models.CustomRules             `json:"custom_rules" validate:"dive"`
+	ScoringRules            models.CriterionRules          `json:"scoring_rules"`
+	MissingDataPolicy       string                         `json:"missing_data_policy" validate:"omitempty,oneof=fail ignore flag"`
+	IsActive                *bool                          `json:"is_active"`
+}
+
+// validateRules checks the constraints the validator tags cannot express
+func (r *buyingCriteriaRequest) validateRules() error {
+	if r.MinYearBuilt != nil && r.MaxYearBuilt != nil && *r.MinYearBuilt > *r.MaxYearBuilt {
+		return fiber.NewError(fiber.StatusBadRequest, "min_year_built must not be after max_year_built")
+	}
+	if err := r.CustomRules.Validate(); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+	return nil
+}
+
+func (r *buyingCriteriaRequest) toModel() *models.BuyingBoxCriteria {
+	criteria := &models.BuyingBoxCriteria{
+		Name:                    r.Name,
+		MinCapRate:              r.MinCapRate,
+		MinCashOnCash:           r.MinCashOnCash,
+		MaxPurchasePrice:        r.MaxPurchasePrice,
+		MinRentToValue:          r.MinRentToValue,
+		MaxYearBuilt:            r.MaxYearBuilt,
+		MinYearBuilt:            r.MinYearBuilt,
+		LocationPreferences:     r.LocationPreferences,
+		PropertyTypePreferences: r.PropertyTypePreferences,
+		CustomRules:             r.CustomRules,
+		ScoringRules:            r.ScoringRules,
+		MissingDataPolicy:       r.MissingDataPolicy,
+		IsActive:                true,
+	}
+	if r.IsActive != nil && !*r.IsActive {
+		criteria.Deactivate()
+	}
+	return criteria
+}
+
+// parseCriteriaRequest decodes and validates a criteria body
+func parseCriteriaRequest(c *fiber.Ctx) (*buyingCriteriaRequest, error) {
+	var req buyingCriteriaRequest
+	if err := parseBody(c, &req); err != nil {
+		return nil, err
+	}
+	if err := req.validateRules(); err != nil {
+		return nil, err
+	}
+	return &req, nil
+}
+
+// List handles GET /buying-criteria
+func (h *BuyingCriteriaHandler) List(c *fiber.Ctx) error {
+	criteria, err := h.criteria.List(middleware.CurrentUserID(c))
+	if err != nil {
+		return err
+	}
+	return c.JSON(criteria)
+}
+
+// Get handles GET /buying-criteria/:id
+func (h *BuyingCriteriaHandler) Get(c *fiber.Ctx) error {
+	id, err := parseUUIDParam(c, "id")
+	if err != nil {
+		return err
+	}
+
+	criteria, err := h.criteria.Get(middleware.CurrentUserID(c), id)
+	if err != nil {
+		return serviceError(err)
+	}
+	return c.JSON(criteria)
+}
+
+// Create handles POST /buying-criteria
+func (h *BuyingCriteriaHandler) Create(c *fiber.Ctx) error {
+	req, err := parseCriteriaRequest(c)
+	if err != nil {
+		return err
+	}
+
+	criteria := req.toModel()
+	if err := h.criteria.Create(middleware.CurrentUserID(c), criteria); err != nil {
+		return serviceError(err)
+	}
+	return c.Status(fiber.StatusCreated).JSON(criteria)
+}
+
+// Update handles PUT /buying-criteria/:id
+func (h *BuyingCriteriaHandler) Update(c *fiber.Ctx) error {
+	id, err := parseUUIDParam(c, "id")
+	if err != nil {
+		return err
+	}
+
+	req, err := parseCriteriaRequest(c)
+	if err != nil {
+		return err
+	}
+
+	criteria, err := h.criteria.Update(middleware.CurrentUserID(c), id, req.toModel(), req.IsActive)
+	if err != nil {
+		return serviceError(err)
+	}
+	return c.JSON(criteria)
+}
+
+// Activate handles POST /buying-criteria/:id/activate
+func (h *BuyingCriteriaHandler) Activate(c *fiber.Ctx) error {
+	return h.setActive(c, true)
+}
+
+// Deactivate handles POST /buying-criteria/:id/deactivate
+func (h *BuyingCriteriaHandler) Deactivate(c *fiber.Ctx) error {
+	return h.setActive(c, false)
+}
+
+func (h *BuyingCriteriaHandler) setActive(c *fiber.Ctx, active bool) error {
+	id, err := parseUUIDParam(c, "id")
+	if err != nil {
+		return err
+	}
+
+	criteria, err := h.criteria.SetActive(middleware.CurrentUserID(c), id, active)
+	if err != nil {
+		return serviceError(err)
+	}
+	return c.JSON(criteria)
+}
+
+// Delete handles DELETE /buying-criteria/:id
+func (h *BuyingCriteriaHandler) Delete(c *fiber.Ctx) error {
+	id, err := parseUUIDParam(c, "id")
+	if err != nil {
+		return err
+	}
+
+	if err := h.criteria.Delete(middleware.CurrentUserID(c), id); err != nil {
+		return serviceError(err)
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// Versions handles GET /buying-criteria/:id/versions
+func (h *BuyingCriteriaHandler) Versions(c *fiber.Ctx) error {
+	id, err := parseUUIDParam(c, "id")
+	if err != nil {
+		return err
+	}
+
+	versions, err := h.criteria.Versions(middleware.CurrentUserID(c), id)
+	if err != nil {
+		return serviceError(err)
+	}
+	return c.JSON(versions)
+}
+
+// GetVersion handles GET /buying-criteria/:id/versions/:version
+func (h *BuyingCriteriaHandler) GetVersion(c *fiber.Ctx) error {
+	id, err := parseUUIDParam(c, "id")
+	if err != nil {
+		return err
+	}
+	version, err := strconv.Atoi(c.Params("version"))
+	if err != nil || version < 1 {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid version")
+	}
+
+	snapshot, err := h.criteria.GetVersion(middleware.CurrentUserID(c), id, version)
+	if err != nil {
+		return serviceError(err)
+	}
+	return c.JSON(snapshot)
+}
@@ -0,0 +1,172 @@
+package handlers
+
+import (
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	"rental-property-mgmt/internal/middleware"
+	"rental-property-mgmt/internal/services"
+)
+
+// Comment thread page size limits
+const (
+	defaultCommentLimit = 20
+	maxCommentLimit     = 100
+)
+
+// CommentHandler serves threaded property comments
+type CommentHandler struct {
+	comments *services.CommentService
+}
+
+// NewCommentHandler creates a new comment handler
+func NewCommentHandler(comments *services.CommentService) *CommentHandler {
+	return &CommentHandler{comments: comments}
+}
+
+type createCommentRequest struct {
+	Content  string     `json:"content" validate:"required,max=2000"`
+	ParentID *uuid.UUID `json:"parent_id"`
+}
+
+type updateCommentRequest struct {
+	Content string `json:"content" validate:"required,max=2000"`
+}
+
+// requireContent rejects whitespace-only comments
+func requireContent(content string) error {
+	if strings.TrimSpace(content) == "" {
+		return fiber.NewError(fiber.StatusBadRequest, "content must not be blank")
+	}
+	return nil
+}
+
+// depthParam reads the optional depth query parameter, capped at the configured maximum
+func (h *CommentHandler) depthParam(c *fiber.Ctx) (int, error) {
+	depth := c.QueryInt("depth", h.comments.MaxDepth())
+	if depth < 0 {
+		return 0, fiber.NewError(fiber.StatusBadRequest, "depth must be at least 0")
+	}
+	if depth > h.comments.MaxDepth() {
+		depth = h.comments.MaxDepth()
+	}
+	return depth, nil
+}
+
+// List handles GET /properties/:id/comments?limit=20&offset=0&depth=<n>
+func (h *CommentHandler) List(c *fiber.Ctx) error {
+	propertyID, err := parseUUIDParam(c, "id")
+	if err != nil {
+		return err
+	}
+
+	limit := c.QueryInt("limit", defaultCommentLimit)
+	offset := c.QueryInt("offset", 0)
+	if limit < 1 || limit > maxCommentLimit {
+		return fiber.NewError(fiber.StatusBadRequest, "limit must be between 1 and 100")
+	}
+	if offset < 0 {
+		return fiber.NewError(fiber.StatusBadRequest, "offset must be at least 0")
+	}
+	depth, err := h.depthParam(c)
+	if err != nil {
+		return err
+	}
+
+	threads, err := h.comments.ListThreads(propertyID, limit, offset, depth)
+	if err != nil {
+		return serviceError(err)
+	}
+	return c.JSON(threads)
+}
+
+// Create handles POST /properties/:id/comments
+func (h *CommentHandler) Create(c *fiber.Ctx) error {
+	propertyID, err := parseUUIDParam(c, "id")
+	if err != nil {
+		return err
+	}
+
+	var req createCommentRequest
+	if err := parseBody(c, &req); err != nil {
+		return err
+	}
+	if err := requireContent(req.Content); err != nil {
+		return err
+	}
+
+	comment, err := h.comments.Create(middleware.CurrentUserID(c), propertyID, req.Content, req.ParentID)
+	if err != nil {
+		return serviceError(err)
+	}
+	return c.Status(fiber.StatusCreated).JSON(comment)
+}
+
+// Get handles GET /comments/:id?depth=<n>
+func (h *CommentHandler) Get(c *fiber.Ctx) error {
+	id, err := parseUUIDParam(c, "id")
+	if err != nil {
+		return err
+	}
+	depth, err := h.depthParam(c)
+	if err != nil {
+		return err
+	}
+
+	comment, err := h.comments.Get(id, depth)
+	if err != nil {
+		return serviceError(err)
+	}
+	return c.JSON(comment)
+}
+
+// Update handles PUT /comments/:id
+func (h *CommentHandler) Update(c *fiber.Ctx) error {
+	id, err := parseUUIDParam(c, "id")
+	if err != nil {
+		return err
+	}
+
+	var req updateCommentRequest
+	if err := parseBody(c, &req); err != nil {
+		return err
+	}
+	if err := requireContent(req.Content); err != nil {
+		return err
+	}
+
+	comment, err := h.comments.Update(middleware.CurrentUserID(c), id, req.Content)
+	if err != nil {
+		return serviceError(err)
+	}
+	return c.JSON(comment)
+}
+
+// Delete handles DELETE /comments/:id
+func (h *CommentHandler) Delete(c *fiber.Ctx) error {
+	id, err := parseUUIDParam(c, "id")
+	if err != nil {
+		return err
+	}
+
+	if err := h.comments.Delete(middleware.CurrentUserID(c), id); err != nil {
+		return serviceError(err)
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// History handles GET /comments/:id/history
+func (h *CommentHandler) History(c *fiber.Ctx) error {
+	id, err := parseUUIDParam(c, "id")
+	if err != nil {
+		return err
+	}
+
+	history, err := h.comments.History(middleware.CurrentUserID(c), id)
+	if err != nil {
+		return serviceError(err)
+	}
+	return c.JSON(history)
+}
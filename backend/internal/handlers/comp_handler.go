@@ -0,0 +1,142 @@
+package handlers
+
+import (
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"rental-property-mgmt/internal/middleware"
+	"rental-property-mgmt/internal/models"
+	"rental-property-mgmt/internal/services"
+)
+
+// CompHandler serves comparable sales and the valuation derived from them
+type CompHandler struct {
+	comps *services.CompService
+}
+
+// NewCompHandler creates a new comp handler
+func NewCompHandler(comps *services.CompService) *CompHandler {
+	return &CompHandler{comps: comps}
+}
+
+type compRequest struct {
+	Address          string   `json:"address" validate:"required,max=255"`
+	SalePrice        float64  `json:"sale_price" validate:"required,gt=0"`
+	SaleDate         string   `json:"sale_date" validate:"required,datetime=2006-01-02"`
+	BuildingAreaSqft *int     `json:"building_area_sqft" validate:"omitempty,gte=1"`
+	Bedrooms         *int     `json:"bedrooms" validate:"omitempty,gte=0,lte=50"`
+	Bathrooms        *float64 `json:"bathrooms" validate:"omitempty,gte=0,lte=50"`
+	YearBuilt        *int     `json:"year_built" validate:"omitempty,gte=1800"`
+	DistanceMiles    *float64 `json:"distance_miles" validate:"omitempty,gte=0,lte=1000"`
+}
+
+func (r *compRequest) toModel() *models.Comp {
+	// Already validated as YYYY-MM-DD
+	saleDate, _ := time.Parse("2006-01-02", r.SaleDate)
+	return &models.Comp{
+		Address:          r.Address,
+		SalePrice:        r.SalePrice,
+		SaleDate:         saleDate,
+		BuildingAreaSqft: r.BuildingAreaSqft,
+		Bedrooms:         r.Bedrooms,
+		Bathrooms:        r.Bathrooms,
+		YearBuilt:        r.YearBuilt,
+		DistanceMiles:    r.DistanceMiles,
+	}
+}
+
+type compValuationRequest struct {
+	Adjustments models.CompAdjustments `json:"adjustments"`
+	Save        bool                   `json:"save"`
+}
+
+// List handles GET /properties/:id/comps
+func (h *CompHandler) List(c *fiber.Ctx) error {
+	propertyID, err := parseUUIDParam(c, "id")
+	if err != nil {
+		return err
+	}
+
+	comps, err := h.comps.List(propertyID)
+	if err != nil {
+		return serviceError(err)
+	}
+	return c.JSON(comps)
+}
+
+// Create handles POST /properties/:id/comps
+func (h *CompHandler) Create(c *fiber.Ctx) error {
+	propertyID, err := parseUUIDParam(c, "id")
+	if err != nil {
+		return err
+	}
+
+	var req compRequest
+	if err := parseBody(c, &req); err != nil {
+		return err
+	}
+
+	comp := req.toModel()
+	if err := h.comps.Create(middleware.CurrentUserID(c), propertyID, comp); err != nil {
+		return serviceError(err)
+	}
+	return c.Status(fiber.StatusCreated).JSON(comp)
+}
+
+// Value handles POST /properties/:id/comps/valuation. Adjustments left out of
+// the body keep their defaults; an empty body values with the defaults.
+func (h *CompHandler) Value(c *fiber.Ctx) error {
+	propertyID, err := parseUUIDParam(c, "id")
+	if err != nil {
+		return err
+	}
+
+	req := compValuationRequest{Adjustments: models.DefaultCompAdjustments()}
+	if len(c.Body()) > 0 {
+		if err := parseBody(c, &req); err != nil {
+			return err
+		}
+	}
+
+	valuation, err := h.comps.Value(middleware.CurrentUserID(c), propertyID, req.Adjustments, req.Save)
+	if err != nil {
+		return serviceError(err)
+	}
+	if req.Save {
+		return c.Status(fiber.StatusCreated).JSON(valuation)
+	}
+	return c.JSON(valuation)
+}
+
+// Update handles PUT /comps/:id
+func (h *CompHandler) Update(c *fiber.Ctx) error {
+	id, err := parseUUIDParam(c, "id")
+	if err != nil {
+		return err
+	}
+
+	var req compRequest
+	if err := parseBody(c, &req); err != nil {
+		return err
+	}
+
+	comp, err := h.comps.Update(middleware.CurrentUserID(c), id, req.toModel())
+	if err != nil {
+		return serviceError(err)
+	}
+	return c.JSON(comp)
+}
+
+// Delete handles DELETE /comps/:id
+func (h *CompHandler) Delete(c *fiber.Ctx) error {
+	id, err := parseUUIDParam(c, "id")
+	if err != nil {
+		return err
+	}
+
+	if err := h.comps.Delete(middleware.CurrentUserID(c), id); err != nil {
+		return serviceError(err)
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	"rental-property-mgmt/internal/middleware"
+	"rental-property-mgmt/internal/services"
+)
+
+// Ranking page size limits, matching the property listing
+const (
+	defaultRankingLimit = 20
+	maxRankingLimit     = 100
+)
+
+// ComparisonHandler serves buying-box comparison endpoints
+type ComparisonHandler struct {
+	comparisons *services.ComparisonService
+}
+
+// NewComparisonHandler creates a new comparison handler
+func NewComparisonHandler(comparisons *services.ComparisonService) *ComparisonHandler {
+	return &ComparisonHandler{comparisons: comparisons}
+}
+
+type compareRequest struct {
+	PropertyIDs []uuid.UUID `json:"property_ids" validate:"required,min=1,max=100"`
+	CriteriaID  uuid.UUID   `json:"criteria_id" validate:"required"`
+}
+
+// Compare handles POST /properties/compare
+func (h *ComparisonHandler) Compare(c *fiber.Ctx) error {
+	var req compareRequest
+	if err := parseBody(c, &req); err != nil {
+		return err
+	}
+
+	comparisons, err := h.comparisons.Compare(middleware.CurrentUserID(c), req.PropertyIDs, req.CriteriaID)
+	if err != nil {
+		return serviceError(err)
+	}
+	return c.JSON(comparisons)
+}
+
+// Rank handles GET /properties/rankings?criteria_id=<uuid>&limit=20&offset=0
+func (h *ComparisonHandler) Rank(c *fiber.Ctx) error {
+	opts := services.RankingOptions{
+		Limit:  c.QueryInt("limit", defaultRankingLimit),
+		Offset: c.QueryInt("offset", 0),
+	}
+	if opts.Limit < 1 || opts.Limit > maxRankingLimit {
+		return fiber.NewError(fiber.StatusBadRequest, "limit must be between 1 and 100")
+	}
+	if opts.Offset < 0 {
+		return fiber.NewError(fiber.StatusBadRequest, "offset must be at least 0")
+	}
+
+	if raw := c.Query("criteria_id"); raw != "" {
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, "invalid criteria_id")
+		}
+		opts.CriteriaID = &id
+	}
+
+	ranking, err := h.comparisons.RankPortfolio(middleware.CurrentUserID(c), opts)
+	if err != nil {
+		return serviceError(err)
+	}
+	return c.JSON(ranking)
+}
@@ -0,0 +1,104 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"reflect"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	"rental-property-mgmt/internal/services"
+)
+
+// validate checks request structs against their `validate` tags,
+// reporting fields by their JSON names
+var validate = newValidator()
+
+// newValidator configures a validator that names fields after their JSON tags
+func newValidator() *validator.Validate {
+	v := validator.New()
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+		if name == "-" {
+			return ""
+		}
+		return name
+	})
+	return v
+}
+
+// parseBody decodes the JSON request body into dst and validates it
+func parseBody(c *fiber.Ctx, dst interface{}) error {
+	if err := c.BodyParser(dst); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+	if err := validate.Struct(dst); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, validationMessage(err))
+	}
+	return nil
+}
+
+// validationMessage turns validator errors into a readable message
+func validationMessage(err error) string {
+	var validationErrors validator.ValidationErrors
+	if !errors.As(err, &validationErrors) {
+		return err.Error()
+	}
+
+	messages := make([]string, 0, len(validationErrors))
+	for _, fe := range validationErrors {
+		field := fe.Field()
+		switch fe.Tag() {
+		case "required":
+			messages = append(messages, fmt.Sprintf("%s is required", field))
+		case "email":
+			messages = append(messages, fmt.Sprintf("%s must be a valid email address", field))
+		case "min", "gte":
+			messages = append(messages, fmt.Sprintf("%s must be at least %s", field, fe.Param()))
+		case "gt":
+			messages = append(messages, fmt.Sprintf("%s must be greater than %s", field, fe.Param()))
+		case "max", "lte":
+			messages = append(messages, fmt.Sprintf("%s must be at most %s", field, fe.Param()))
+		case "lt":
+			messages = append(messages, fmt.Sprintf("%s must be less than %s", field, fe.Param()))
+		case "oneof":
+			messages = append(messages, fmt.Sprintf("%s must be one of: %s", field, fe.Param()))
+		default:
+			messages = append(messages, fmt.Sprintf("%s is invalid", field))
+		}
+	}
+	return strings.Join(messages, "; ")
+}
+
+// parseUUIDParam reads a UUID path parameter
+func parseUUIDParam(c *fiber.Ctx, name string) (uuid.UUID, error) {
+	id, err := uuid.Parse(c.Params(name))
+	if err != nil {
+		return uuid.Nil, fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("invalid %s", name))
+	}
+	return id, nil
+}
+
+// serviceError maps service-layer errors onto HTTP errors. Anything else is
+// logged and reported as a bare 500, keeping database details out of responses.
+func serviceError(err error) error {
+	switch {
+	case errors.Is(err, services.ErrNotFound):
+		return fiber.NewError(fiber.StatusNotFound, err.Error())
+	case errors.Is(err, services.ErrForbidden):
+		return fiber.NewError(fiber.StatusForbidden, err.Error())
+	case errors.Is(err, services.ErrInvalidInput):
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	case errors.Is(err, services.ErrConflict):
+		return fiber.NewError(fiber.StatusConflict, err.Error())
+	case errors.Is(err, services.ErrInvalidCredentials):
+		return fiber.NewError(fiber.StatusUnauthorized, err.Error())
+	default:
+		log.Printf("internal error: %v", err)
+		return fiber.ErrInternalServerError
+	}
+}
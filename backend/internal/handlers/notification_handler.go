@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	"rental-property-mgmt/internal/middleware"
+	"rental-property-mgmt/internal/services"
+)
+
+// Notification inbox page size limits
+const (
+	defaultNotificationLimit = 20
+	maxNotificationLimit     = 100
+)
+
+// NotificationHandler serves the in-app inbox of comment mentions and replies
+// and buying-box matches
+type NotificationHandler struct {
+	notifications *services.NotificationService
+}
+
+// NewNotificationHandler creates a new notification handler
+func NewNotificationHandler(notifications *services.NotificationService) *NotificationHandler {
+	return &NotificationHandler{notifications: notifications}
+}
+
+// List handles GET /notifications?unread=true&limit=20&offset=0
+func (h *NotificationHandler) List(c *fiber.Ctx) error {
+	limit := c.QueryInt("limit", defaultNotificationLimit)
+	offset := c.QueryInt("offset", 0)
+	if limit < 1 || limit > maxNotificationLimit {
+		return fiber.NewError(fiber.StatusBadRequest, "limit must be between 1 and 100")
+	}
+	if offset < 0 {
+		return fiber.NewError(fiber.StatusBadRequest, "offset must be at least 0")
+	}
+
+	inbox, err := h.notifications.List(middleware.CurrentUserID(c), c.QueryBool("unread", false), limit, offset)
+	if err != nil {
+		return serviceError(err)
+	}
+	return c.JSON(inbox)
+}
+
+// MarkRead handles POST /notifications/:id/read
+func (h *NotificationHandler) MarkRead(c *fiber.Ctx) error {
+	id, err := parseUUIDParam(c, "id")
+	if err != nil {
+		return err
+	}
+
+	if err := h.notifications.MarkRead(middleware.CurrentUserID(c), id); err != nil {
+		return serviceError(err)
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// MarkAllRead handles POST /notifications/read-all
+func (h *NotificationHandler) MarkAllRead(c *fiber.Ctx) error {
+	if err := h.notifications.MarkAllRead(middleware.CurrentUserID(c)); err != nil {
+		return serviceError(err)
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
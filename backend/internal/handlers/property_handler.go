@@ -0,0 +1,265 @@
+package handlers
+
+import (
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	"rental-property-mgmt/internal/middleware"
+	"rental-property-mgmt/internal/models"
+	"rental-property-mgmt/internal/services"
+)
+
+// PropertyHandler serves property endpoints
+type PropertyHandler struct {
+	properties *services.PropertyService
+}
+
+// NewPropertyHandler creates a new property handler
+func NewPropertyHandler(properties *services.PropertyService) *PropertyHandler {
+	return &PropertyHandler{properties: properties}
+}
+
+type createPropertyRequest struct {
+	Address              string       `json:"address" validate:"required,max=255"`
+	City                 *string      `json:"city" validate:"omitempty,max=100"`
+	State                *string      `json:"state" validate:"omitempty,len=2,alpha"`
+	ZipCode              *string      `json:"zip_code" validate:"omitempty,len=5,numeric"`
+	Latitude             *float64     `json:"latitude" validate:"omitempty,gte=-90,lte=90"`
+	Longitude            *float64     `json:"longitude" validate:"omitempty,gte=-180,lte=180"`
+	PropertyType         *string      `json:"property_type" validate:"omitempty,oneof=sfr duplex triplex fourplex multifamily condo townhouse"`
+	YearBuilt            *int         `json:"year_built" validate:"omitempty,gte=1800"`
+	LandAreaSqft         *int         `json:"land_area_sqft" validate:"omitempty,gte=1"`
+	BuildingAreaSqft     *int         `json:"building_area_sqft" validate:"omitempty,gte=1"`
+	Bedrooms             *int         `json:"bedrooms" validate:"omitempty,gte=0,lte=50"`
+	Bathrooms            *float64     `json:"bathrooms" validate:"omitempty,gte=0,lte=50"`
+	PurchasePrice        float64      `json:"purchase_price" validate:"required,gt=0"`
+	IntendedRent         *float64     `json:"intended_rent" validate:"omitempty,gte=0"`
+	OperatingExpenses    models.JSONB `json:"operating_expenses"`
+	FinancingTerms       models.JSONB `json:"financing_terms"`
+	OperatingAssumptions models.JSONB `json:"operating_assumptions"`
+	LocalContext         models.JSONB `json:"local_context"`
+	AssumptionProfileID  *uuid.UUID   `json:"assumption_profile_id"`
+}
+
+// Create handles POST /properties
+func (h *PropertyHandler) Create(c *fiber.Ctx) error {
+	var req createPropertyRequest
+	if err := parseBody(c, &req); err != nil {
+		return err
+	}
+
+	property := &models.Property{
+		Address:              req.Address,
+		City:                 req.City,
+		State:                req.State,
+		ZipCode:              req.ZipCode,
+		Latitude:             req.Latitude,
+		Longitude:            req.Longitude,
+		PropertyType:         req.PropertyType,
+		YearBuilt:            req.YearBuilt,
+		LandAreaSqft:         req.LandAreaSqft,
+		BuildingAreaSqft:     req.BuildingAreaSqft,
+		Bedrooms:             req.Bedrooms,
+		Bathrooms:            req.Bathrooms,
+		PurchasePrice:        req.PurchasePrice,
+		IntendedRent:         req.IntendedRent,
+		OperatingExpenses:    req.OperatingExpenses,
+		FinancingTerms:       req.FinancingTerms,
+		OperatingAssumptions: req.OperatingAssumptions,
+		LocalContext:         req.LocalContext,
+	}
+
+	if err := h.properties.Create(middleware.CurrentUserID(c), property, req.AssumptionProfileID); err != nil {
+		return serviceError(err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(property)
+}
+
+// Portfolio listing page size limits
+const (
+	defaultPropertyLimit = 20
+	maxPropertyLimit     = 100
+)
+
+// List handles GET /properties?sort=created_at&order=desc&decision=pursue&min_pursue=2&max_pass=0
+func (h *PropertyHandler) List(c *fiber.Ctx) error {
+	opts := services.PropertyListOptions{
+		Limit:      c.QueryInt("limit", defaultPropertyLimit),
+		Offset:     c.QueryInt("offset", 0),
+		Sort:       c.Query("sort", services.PropertySortCreatedAt),
+		Descending: true,
+	}
+	if opts.Limit < 1 || opts.Limit > maxPropertyLimit {
+		return fiber.NewError(fiber.StatusBadRequest, "limit must be between 1 and 100")
+	}
+	if opts.Offset < 0 {
+		return fiber.NewError(fiber.StatusBadRequest, "offset must be at least 0")
+	}
+	switch c.Query("order", "desc") {
+	case "asc":
+		opts.Descending = false
+	case "desc":
+	default:
+		return fiber.NewError(fiber.StatusBadRequest, "order must be asc or desc")
+	}
+
+	if decision := c.Query("decision"); decision != "" {
+		opts.Decision = &decision
+	}
+	var err error
+	if opts.MinPursue, err = voteCountQuery(c, "min_pursue"); err != nil {
+		return err
+	}
+	if opts.MaxPass, err = voteCountQuery(c, "max_pass"); err != nil {
+		return err
+	}
+
+	list, err := h.properties.List(middleware.CurrentUserID(c), opts)
+	if err != nil {
+		return serviceError(err)
+	}
+	return c.JSON(list)
+}
+
+// voteCountQuery reads an optional non-negative vote count query parameter
+func voteCountQuery(c *fiber.Ctx, name string) (*int, error) {
+	raw := c.Query(name)
+	if raw == "" {
+		return nil, nil
+	}
+	count, err := strconv.Atoi(raw)
+	if err != nil || count < 0 {
+		return nil, fiber.NewError(fiber.StatusBadRequest, name+" must be a non-negative integer")
+	}
+	return &count, nil
+}
+
+// Get handles GET /properties/:id
+func (h *PropertyHandler) Get(c *fiber.Ctx) error {
+	id, err := parseUUIDParam(c, "id")
+	if err != nil {
+		return err
+	}
+
+	property, err := h.properties.Get(id)
+	if err != nil {
+		return serviceError(err)
+	}
+
+	return c.JSON(property)
+}
+
+type updatePropertyRequest struct {
+	Address              *string      `json:"address" validate:"omitempty,min=1,max=255"`
+	City                 *string      `json:"city" validate:"omitempty,max=100"`
+	State                *string      `json:"state" validate:"omitempty,len=2,alpha"`
+	ZipCode              *string      `json:"zip_code" validate:"omitempty,len=5,numeric"`
+	Latitude             *float64     `json:"latitude" validate:"omitempty,gte=-90,lte=90"`
+	Longitude            *float64     `json:"longitude" validate:"omitempty,gte=-180,lte=180"`
+	PropertyType         *string      `json:"property_type" validate:"omitempty,oneof=sfr duplex triplex fourplex multifamily condo townhouse"`
+	YearBuilt            *int         `json:"year_built" validate:"omitempty,gte=1800"`
+	LandAreaSqft         *int         `json:"land_area_sqft" validate:"omitempty,gte=1"`
+	BuildingAreaSqft     *int         `json:"building_area_sqft" validate:"omitempty,gte=1"`
+	Bedrooms             *int         `json:"bedrooms" validate:"omitempty,gte=0,lte=50"`
+	Bathrooms            *float64     `json:"bathrooms" validate:"omitempty,gte=0,lte=50"`
+	PurchasePrice        *float64     `json:"purchase_price" validate:"omitempty,gt=0"`
+	IntendedRent         *float64     `json:"intended_rent" validate:"omitempty,gte=0"`
+	OperatingExpenses    models.JSONB `json:"operating_expenses"`
+	FinancingTerms       models.JSONB `json:"financing_terms"`
+	OperatingAssumptions models.JSONB `json:"operating_assumptions"`
+	LocalContext         models.JSONB `json:"local_context"`
+}
+
+// apply copies the fields present in the request onto the property
+func (req *updatePropertyRequest) apply(property *models.Property) {
+	if req.Address != nil {
+		property.Address = *req.Address
+	}
+	if req.City != nil {
+		property.City = req.City
+	}
+	if req.State != nil {
+		property.State = req.State
+	}
+	if req.ZipCode != nil {
+		property.ZipCode = req.ZipCode
+	}
+	if req.Latitude != nil {
+		property.Latitude = req.Latitude
+	}
+	if req.Longitude != nil {
+		property.Longitude = req.Longitude
+	}
+	if req.PropertyType != nil {
+		property.PropertyType = req.PropertyType
+	}
+	if req.YearBuilt != nil {
+		property.YearBuilt = req.YearBuilt
+	}
+	if req.LandAreaSqft != nil {
+		property.LandAreaSqft = req.LandAreaSqft
+	}
+	if req.BuildingAreaSqft != nil {
+		property.BuildingAreaSqft = req.BuildingAreaSqft
+	}
+	if req.Bedrooms != nil {
+		property.Bedrooms = req.Bedrooms
+	}
+	if req.Bathrooms != nil {
+		property.Bathrooms = req.Bathrooms
+	}
+	if req.PurchasePrice != nil {
+		property.PurchasePrice = *req.PurchasePrice
+	}
+	if req.IntendedRent != nil {
+		property.IntendedRent = req.IntendedRent
+	}
+	if req.OperatingExpenses != nil {
+		property.OperatingExpenses = req.OperatingExpenses
+	}
+	if req.FinancingTerms != nil {
+		property.FinancingTerms = req.FinancingTerms
+	}
+	if req.OperatingAssumptions != nil {
+		property.OperatingAssumptions = req.OperatingAssumptions
+	}
+	if req.LocalContext != nil {
+		property.LocalContext = req.LocalContext
+	}
+}
+
+// Update handles PUT /properties/:id. Only fields present in the body change.
+func (h *PropertyHandler) Update(c *fiber.Ctx) error {
+	id, err := parseUUIDParam(c, "id")
+	if err != nil {
+		return err
+	}
+
+	var req updatePropertyRequest
+	if err := parseBody(c, &req); err != nil {
+		return err
+	}
+
+	property, err := h.properties.Update(middleware.CurrentUserID(c), id, req.apply)
+	if err != nil {
+		return serviceError(err)
+	}
+	return c.JSON(property)
+}
+
+// RecalculateMetrics handles POST /properties/:id/metrics
+func (h *PropertyHandler) RecalculateMetrics(c *fiber.Ctx) error {
+	id, err := parseUUIDParam(c, "id")
+	if err != nil {
+		return err
+	}
+
+	metrics, err := h.properties.RecalculateMetrics(middleware.CurrentUserID(c), id)
+	if err != nil {
+		return serviceError(err)
+	}
+	return c.JSON(metrics)
+}
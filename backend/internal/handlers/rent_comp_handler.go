@@ -0,0 +1,140 @@
+package handlers
+
+import (
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"rental-property-mgmt/internal/middleware"
+	"rental-property-mgmt/internal/models"
+	"rental-property-mgmt/internal/services"
+)
+
+// RentCompHandler serves rental comps and the rent estimate derived from them
+type RentCompHandler struct {
+	rentComps *services.RentCompService
+}
+
+// NewRentCompHandler creates a new rent comp handler
+func NewRentCompHandler(rentComps *services.RentCompService) *RentCompHandler {
+	return &RentCompHandler{rentComps: rentComps}
+}
+
+type rentCompRequest struct {
+	Address          string   `json:"address" validate:"required,max=255"`
+	MonthlyRent      float64  `json:"monthly_rent" validate:"required,gt=0"`
+	ListingDate      string   `json:"listing_date" validate:"required,datetime=2006-01-02"`
+	BuildingAreaSqft *int     `json:"building_area_sqft" validate:"omitempty,gte=1"`
+	Bedrooms         *int     `json:"bedrooms" validate:"omitempty,gte=0,lte=50"`
+	Bathrooms        *float64 `json:"bathrooms" validate:"omitempty,gte=0,lte=50"`
+	DistanceMiles    *float64 `json:"distance_miles" validate:"omitempty,gte=0,lte=1000"`
+}
+
+func (r *rentCompRequest) toModel() *models.RentComp {
+	// Already validated as YYYY-MM-DD
+	listingDate, _ := time.Parse("2006-01-02", r.ListingDate)
+	return &models.RentComp{
+		Address:          r.Address,
+		MonthlyRent:      r.MonthlyRent,
+		ListingDate:      listingDate,
+		BuildingAreaSqft: r.BuildingAreaSqft,
+		Bedrooms:         r.Bedrooms,
+		Bathrooms:        r.Bathrooms,
+		DistanceMiles:    r.DistanceMiles,
+	}
+}
+
+type rentEstimateRequest struct {
+	AnnualRentGrowthPercent float64 `json:"annual_rent_growth_percent" validate:"gte=-50,lte=50"`
+	Save                    bool    `json:"save"`
+}
+
+// List handles GET /properties/:id/rent-comps
+func (h *RentCompHandler) List(c *fiber.Ctx) error {
+	propertyID, err := parseUUIDParam(c, "id")
+	if err != nil {
+		return err
+	}
+
+	comps, err := h.rentComps.List(propertyID)
+	if err != nil {
+		return serviceError(err)
+	}
+	return c.JSON(comps)
+}
+
+// Create handles POST /properties/:id/rent-comps
+func (h *RentCompHandler) Create(c *fiber.Ctx) error {
+	propertyID, err := parseUUIDParam(c, "id")
+	if err != nil {
+		return err
+	}
+
+	var req rentCompRequest
+	if err := parseBody(c, &req); err != nil {
+		return err
+	}
+
+	comp := req.toModel()
+	if err := h.rentComps.Create(middleware.CurrentUserID(c), propertyID, comp); err != nil {
+		return serviceError(err)
+	}
+	return c.Status(fiber.StatusCreated).JSON(comp)
+}
+
+// Estimate handles POST /properties/:id/rent-comps/estimate. An empty body
+// estimates with the default rent growth.
+func (h *RentCompHandler) Estimate(c *fiber.Ctx) error {
+	propertyID, err := parseUUIDParam(c, "id")
+	if err != nil {
+		return err
+	}
+
+	req := rentEstimateRequest{AnnualRentGrowthPercent: models.DefaultAnnualRentGrowthPercent}
+	if len(c.Body()) > 0 {
+		if err := parseBody(c, &req); err != nil {
+			return err
+		}
+	}
+
+	estimate, err := h.rentComps.Estimate(middleware.CurrentUserID(c), propertyID, req.AnnualRentGrowthPercent, req.Save)
+	if err != nil {
+		return serviceError(err)
+	}
+	if req.Save {
+		return c.Status(fiber.StatusCreated).JSON(estimate)
+	}
+	return c.JSON(estimate)
+}
+
+// Update handles PUT /rent-comps/:id
+func (h *RentCompHandler) Update(c *fiber.Ctx) error {
+	id, err := parseUUIDParam(c, "id")
+	if err != nil {
+		return err
+	}
+
+	var req rentCompRequest
+	if err := parseBody(c, &req); err != nil {
+		return err
+	}
+
+	comp, err := h.rentComps.Update(middleware.CurrentUserID(c), id, req.toModel())
+	if err != nil {
+		return serviceError(err)
+	}
+	return c.JSON(comp)
+}
+
+// Delete handles DELETE /rent-comps/:id
+func (h *RentCompHandler) Delete(c *fiber.Ctx) error {
+	id, err := parseUUIDParam(c, "id")
+	if err != nil {
+		return err
+	}
+
+	if err := h.rentComps.Delete(middleware.CurrentUserID(c), id); err != nil {
+		return serviceError(err)
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	"rental-property-mgmt/internal/middleware"
+	"rental-property-mgmt/internal/repository"
+	"rental-property-mgmt/internal/services"
+)
+
+// RegisterRoutes mounts all API handlers on the given router, backed by store
+func RegisterRoutes(api fiber.Router, store *repository.Store) {
+	requireAuth := middleware.RequireAuth()
+
+	// Authentication
+	auth := NewAuthHandler(services.NewUserService(store))
+	api.Post("/auth/register", auth.Register)
+	api.Post("/auth/login", auth.Login)
+	api.Post("/auth/logout", requireAuth, auth.Logout)
+
+	// Properties
+	properties := NewPropertyHandler(services.NewPropertyService(store))
+	api.Get("/properties", requireAuth, properties.List)
+	api.Post("/properties", requireAuth, properties.Create)
+
+	// Buying-box comparisons; registered before /properties/:id so the static paths win
+	comparisons := NewComparisonHandler(services.NewComparisonService(store))
+	api.Post("/properties/compare", requireAuth, comparisons.Compare)
+	api.Get("/properties/rankings", requireAuth, comparisons.Rank)
+
+	api.Get("/properties/:id", requireAuth, properties.Get)
+	api.Put("/properties/:id", requireAuth, properties.Update)
+
+	// Third-party valuations and metrics on market rent
+	valuations := NewValuationHandler(services.NewValuationService(store))
+	api.Get("/valuations/sources", requireAuth, valuations.Sources)
+	api.Post("/valuations/import", requireAuth, valuations.Import)
+	api.Get("/properties/:id/valuations", requireAuth, valuations.List)
+	api.Post("/properties/:id/valuations", requireAuth, valuations.Create)
+	api.Get("/properties/:id/valuations/summary", requireAuth, valuations.Summary)
+	api.Get("/properties/:id/valuations/history", requireAuth, valuations.History)
+	api.Get("/properties/:id/metrics", requireAuth, valuations.Metrics)
+	api.Post("/properties/:id/metrics", requireAuth, properties.RecalculateMetrics)
+
+	// Comparable sales and the adjusted valuation they support
+	comps := NewCompHandler(services.NewCompService(store))
+	api.Get("/properties/:id/comps", requireAuth, comps.List)
+	api.Post("/properties/:id/comps", requireAuth, comps.Create)
+	api.Post("/properties/:id/comps/valuation", requireAuth, comps.Value)
+	api.Put("/comps/:id", requireAuth, comps.Update)
+	api.Delete("/comps/:id", requireAuth, comps.Delete)
+
+	// Rental comps and the rent estimate they support
+	rentComps := NewRentCompHandler(services.NewRentCompService(store))
+	api.Get("/properties/:id/rent-comps", requireAuth, rentComps.List)
+	api.Post("/properties/:id/rent-comps", requireAuth, rentComps.Create)
+	api.Post("/properties/:id/rent-comps/estimate", requireAuth, rentComps.Estimate)
+	api.Put("/rent-comps/:id", requireAuth, rentComps.Update)
+	api.Delete("/rent-comps/:id", requireAuth, rentComps.Delete)
+
+	// Full-text search over properties and comments
+	search := NewSearchHandler(services.NewSearchService(store))
+	api.Get("/search", requireAuth, search.Search)
+
+	// Threaded comments
+	comments := NewCommentHandler(services.NewCommentService(store))
+	api.Get("/properties/:id/comments", requireAuth, comments.List)
+	api.Post("/properties/:id/comments", requireAuth, comments.Create)
+	api.Get("/comments/:id", requireAuth, comments.Get)
+	api.Put("/comments/:id", requireAuth, comments.Update)
+	api.Delete("/comments/:id", requireAuth, comments.Delete)
+	api.Get("/comments/:id/history", requireAuth, comments.History)
+
+	// Team sentiment: comment reactions and property decision votes
+	sentiment := NewSentimentHandler(services.NewSentimentService(store))
+	api.Post("/comments/:id/reactions", requireAuth, sentiment.React)
+	api.Delete("/comments/:id/reactions", requireAuth, sentiment.Unreact)
+	api.Get("/properties/:id/votes", requireAuth, sentiment.Votes)
+	api.Put("/properties/:id/vote", requireAuth, sentiment.Vote)
+	api.Delete("/properties/:id/vote", requireAuth, sentiment.Unvote)
+
+	// What-if scenarios
+	scenarios := NewScenarioHandler(services.NewScenarioService(store))
+	api.Get("/properties/:id/scenarios", requireAuth, scenarios.List)
+	api.Post("/properties/:id/scenarios", requireAuth, scenarios.Create)
+	api.Get("/properties/:id/scenarios/compare", requireAuth, scenarios.Compare)
+	api.Get("/scenarios/:id", requireAuth, scenarios.Get)
+	api.Put("/scenarios/:id", requireAuth, scenarios.Update)
+	api.Delete("/scenarios/:id", requireAuth, scenarios.Delete)
+
+	// Buying-box criteria
+	criteria := NewBuyingCriteriaHandler(services.NewBuyingCriteriaService(store))
+	api.Get("/buying-criteria", requireAuth, criteria.List)
+	api.Post("/buying-criteria", requireAuth, criteria.Create)
+	api.Get("/buying-criteria/:id", requireAuth, criteria.Get)
+	api.Put("/buying-criteria/:id", requireAuth, criteria.Update)
+	api.Delete("/buying-criteria/:id", requireAuth, criteria.Delete)
+	api.Post("/buying-criteria/:id/activate", requireAuth, criteria.Activate)
+	api.Post("/buying-criteria/:id/deactivate", requireAuth, criteria.Deactivate)
+	api.Get("/buying-criteria/:id/versions", requireAuth, criteria.Versions)
+	api.Get("/buying-criteria/:id/versions/:version", requireAuth, criteria.GetVersion)
+
+	// Notification inbox: comment mentions and replies, buying-box matches
+	notifications := NewNotificationHandler(services.NewNotificationService(store))
+	api.Get("/notifications", requireAuth, notifications.List)
+	api.Post("/notifications/read-all", requireAuth, notifications.MarkAllRead)
+	api.Post("/notifications/:id/read", requireAuth, notifications.MarkRead)
+
+	// Market assumption profiles
+	profiles := NewAssumptionProfileHandler(services.NewAssumptionProfileService(store))
+	api.Get("/assumption-profiles", requireAuth, profiles.List)
+	api.Post("/assumption-profiles", requireAuth, profiles.Create)
+	api.Get("/assumption-profiles/:id", requireAuth, profiles.Get)
+	api.Put("/assumption-profiles/:id", requireAuth, profiles.Update)
+	api.Delete("/assumption-profiles/:id", requireAuth, profiles.Delete)
+}
@@ -0,0 +1,148 @@
+package handlers
+
+import (
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	"rental-property-mgmt/internal/middleware"
+	"rental-property-mgmt/internal/models"
+	"rental-property-mgmt/internal/services"
+)
+
+// ScenarioHandler serves what-if scenario endpoints
+type ScenarioHandler struct {
+	scenarios *services.ScenarioService
+}
+
+// NewScenarioHandler creates a new scenario handler
+func NewScenarioHandler(scenarios *services.ScenarioService) *ScenarioHandler {
+	return &ScenarioHandler{scenarios: scenarios}
+}
+
+type scenarioRequest struct {
+	Name                 string       `json:"name" validate:"required,max=100"`
+	Description          string       `json:"description" validate:"max=2000"`
+	PurchasePrice        *float64     `json:"purchase_price" validate:"omitempty,gt=0"`
+	IntendedRent         *float64     `json:"intended_rent" validate:"omitempty,gte=0"`
+	OperatingExpenses    models.JSONB `json:"operating_expenses"`
+	FinancingTerms       models.JSONB `json:"financing_terms"`
+	OperatingAssumptions models.JSONB `json:"operating_assumptions"`
+}
+
+func (r *scenarioRequest) toModel() *models.Scenario {
+	return &models.Scenario{
+		Name:                 r.Name,
+		Description:          r.Description,
+		PurchasePrice:        r.PurchasePrice,
+		IntendedRent:         r.IntendedRent,
+		OperatingExpenses:    r.OperatingExpenses,
+		FinancingTerms:       r.FinancingTerms,
+		OperatingAssumptions: r.OperatingAssumptions,
+	}
+}
+
+// List handles GET /properties/:id/scenarios
+func (h *ScenarioHandler) List(c *fiber.Ctx) error {
+	propertyID, err := parseUUIDParam(c, "id")
+	if err != nil {
+		return err
+	}
+
+	scenarios, err := h.scenarios.List(propertyID)
+	if err != nil {
+		return serviceError(err)
+	}
+	return c.JSON(scenarios)
+}
+
+// Create handles POST /properties/:id/scenarios
+func (h *ScenarioHandler) Create(c *fiber.Ctx) error {
+	propertyID, err := parseUUIDParam(c, "id")
+	if err != nil {
+		return err
+	}
+
+	var req scenarioRequest
+	if err := parseBody(c, &req); err != nil {
+		return err
+	}
+
+	scenario := req.toModel()
+	if err := h.scenarios.Create(middleware.CurrentUserID(c), propertyID, scenario); err != nil {
+		return serviceError(err)
+	}
+	return c.Status(fiber.StatusCreated).JSON(scenario)
+}
+
+// Compare handles GET /properties/:id/scenarios/compare?ids=<uuid>,<uuid>
+func (h *ScenarioHandler) Compare(c *fiber.Ctx) error {
+	propertyID, err := parseUUIDParam(c, "id")
+	if err != nil {
+		return err
+	}
+
+	var scenarioIDs []uuid.UUID
+	if ids := c.Query("ids"); ids != "" {
+		for _, raw := range strings.Split(ids, ",") {
+			id, err := uuid.Parse(strings.TrimSpace(raw))
+			if err != nil {
+				return fiber.NewError(fiber.StatusBadRequest, "invalid scenario id: "+raw)
+			}
+			scenarioIDs = append(scenarioIDs, id)
+		}
+	}
+
+	comparison, err := h.scenarios.Compare(propertyID, scenarioIDs)
+	if err != nil {
+		return serviceError(err)
+	}
+	return c.JSON(comparison)
+}
+
+// Get handles GET /scenarios/:id
+func (h *ScenarioHandler) Get(c *fiber.Ctx) error {
+	id, err := parseUUIDParam(c, "id")
+	if err != nil {
+		return err
+	}
+
+	scenario, err := h.scenarios.Get(id)
+	if err != nil {
+		return serviceError(err)
+	}
+	return c.JSON(scenario)
+}
+
+// Update handles PUT /scenarios/:id
+func (h *ScenarioHandler) Update(c *fiber.Ctx) error {
+	id, err := parseUUIDParam(c, "id")
+	if err != nil {
+		return err
+	}
+
+	var req scenarioRequest
+	if err := parseBody(c, &req); err != nil {
+		return err
+	}
+
+	scenario, err := h.scenarios.Update(middleware.CurrentUserID(c), id, req.toModel())
+	if err != nil {
+		return serviceError(err)
+	}
+	return c.JSON(scenario)
+}
+
+// Delete handles DELETE /scenarios/:id
+func (h *ScenarioHandler) Delete(c *fiber.Ctx) error {
+	id, err := parseUUIDParam(c, "id")
+	if err != nil {
+		return err
+	}
+
+	if err := h.scenarios.Delete(middleware.CurrentUserID(c), id); err != nil {
+		return serviceError(err)
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
@@ -0,0 +1,50 @@
+package handlers
+
+import (
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+
+	"rental-property-mgmt/internal/middleware"
+	"rental-property-mgmt/internal/services"
+)
+
+// Search page size limits
+const (
+	defaultSearchLimit = 20
+	maxSearchLimit     = 100
+)
+
+// SearchHandler serves full-text search over properties and comments
+type SearchHandler struct {
+	search *services.SearchService
+}
+
+// NewSearchHandler creates a new search handler
+func NewSearchHandler(search *services.SearchService) *SearchHandler {
+	return &SearchHandler{search: search}
+}
+
+// Search handles GET /search?q=foundation+issue&type=comment&mine=true&limit=20&offset=0
+func (h *SearchHandler) Search(c *fiber.Ctx) error {
+	opts := services.SearchOptions{
+		OwnedOnly: c.QueryBool("mine", false),
+		Limit:     c.QueryInt("limit", defaultSearchLimit),
+		Offset:    c.QueryInt("offset", 0),
+	}
+	if opts.Limit < 1 || opts.Limit > maxSearchLimit {
+		return fiber.NewError(fiber.StatusBadRequest, "limit must be between 1 and 100")
+	}
+	if opts.Offset < 0 {
+		return fiber.NewError(fiber.StatusBadRequest, "offset must be at least 0")
+	}
+	if types := c.Query("type"); types != "" {
+		opts.Types = strings.Split(types, ",")
+	}
+
+	results, err := h.search.Search(middleware.CurrentUserID(c), c.Query("q"), opts)
+	if err != nil {
+		return serviceError(err)
+	}
+	return c.JSON(results)
+}
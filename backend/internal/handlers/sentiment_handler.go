@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	"rental-property-mgmt/internal/middleware"
+	"rental-property-mgmt/internal/services"
+)
+
+// SentimentHandler serves comment reactions and property decision votes
+type SentimentHandler struct {
+	sentiment *services.SentimentService
+}
+
+// NewSentimentHandler creates a new sentiment handler
+func NewSentimentHandler(sentiment *services.SentimentService) *SentimentHandler {
+	return &SentimentHandler{sentiment: sentiment}
+}
+
+type reactionRequest struct {
+	Emoji string `json:"emoji" validate:"required"`
+}
+
+type voteRequest struct {
+	Decision string `json:"decision" validate:"required,oneof=pass maybe pursue"`
+}
+
+// React handles POST /comments/:id/reactions
+func (h *SentimentHandler) React(c *fiber.Ctx) error {
+	id, err := parseUUIDParam(c, "id")
+	if err != nil {
+		return err
+	}
+
+	var req reactionRequest
+	if err := parseBody(c, &req); err != nil {
+		return err
+	}
+
+	reactions, err := h.sentiment.React(middleware.CurrentUserID(c), id, req.Emoji)
+	if err != nil {
+		return serviceError(err)
+	}
+	return c.JSON(fiber.Map{"reactions": reactions})
+}
+
+// Unreact handles DELETE /comments/:id/reactions?emoji=<emoji>
+func (h *SentimentHandler) Unreact(c *fiber.Ctx) error {
+	id, err := parseUUIDParam(c, "id")
+	if err != nil {
+		return err
+	}
+	emoji := c.Query("emoji")
+	if emoji == "" {
+		return fiber.NewError(fiber.StatusBadRequest, "emoji is required")
+	}
+
+	if err := h.sentiment.Unreact(middleware.CurrentUserID(c), id, emoji); err != nil {
+		return serviceError(err)
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// Votes handles GET /properties/:id/votes
+func (h *SentimentHandler) Votes(c *fiber.Ctx) error {
+	id, err := parseUUIDParam(c, "id")
+	if err != nil {
+		return err
+	}
+
+	votes, err := h.sentiment.Votes(middleware.CurrentUserID(c), id)
+	if err != nil {
+		return serviceError(err)
+	}
+	return c.JSON(votes)
+}
+
+// Vote handles PUT /properties/:id/vote
+func (h *SentimentHandler) Vote(c *fiber.Ctx) error {
+	id, err := parseUUIDParam(c, "id")
+	if err != nil {
+		return err
+	}
+
+	var req voteRequest
+	if err := parseBody(c, &req); err != nil {
+		return err
+	}
+
+	tally, err := h.sentiment.Vote(middleware.CurrentUserID(c), id, req.Decision)
+	if err != nil {
+		return serviceError(err)
+	}
+	return c.JSON(tally)
+}
+
+// Unvote handles DELETE /properties/:id/vote
+func (h *SentimentHandler) Unvote(c *fiber.Ctx) error {
+	id, err := parseUUIDParam(c, "id")
+	if err != nil {
+		return err
+	}
+
+	if err := h.sentiment.Unvote(middleware.CurrentUserID(c), id); err != nil {
+		return serviceError(err)
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
@@ -0,0 +1,219 @@
+package handlers
+
+import (
+	"bytes"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"rental-property-mgmt/internal/middleware"
+	"rental-property-mgmt/internal/models"
+	"rental-property-mgmt/internal/services"
+)
+
+// ValuationHandler serves third-party valuations and the metrics derived from them
+type ValuationHandler struct {
+	valuations *services.ValuationService
+}
+
+// NewValuationHandler creates a new valuation handler
+func NewValuationHandler(valuations *services.ValuationService) *ValuationHandler {
+	return &ValuationHandler{valuations: valuations}
+}
+
+type createValuationRequest struct {
+	Source        string  `json:"source" validate:"required,max=50"`
+	ValuationType string  `json:"valuation_type" validate:"required,oneof=market_value rental_estimate"`
+	Value         float64 `json:"value" validate:"required,gt=0"`
+	ValuationDate string  `json:"valuation_date" validate:"omitempty,datetime=2006-01-02"`
+}
+
+type importValuationsRequest struct {
+	AddressColumn string `json:"address_column" form:"address_column" query:"address_column" validate:"max=100"`
+	CityColumn    string `json:"city_column" form:"city_column" query:"city_column" validate:"max=100"`
+	StateColumn   string `json:"state_column" form:"state_column" query:"state_column" validate:"max=100"`
+	ZipColumn     string `json:"zip_column" form:"zip_column" query:"zip_column" validate:"max=100"`
+	ValueColumn   string `json:"value_column" form:"value_column" query:"value_column" validate:"max=100"`
+	DateColumn    string `json:"date_column" form:"date_column" query:"date_column" validate:"max=100"`
+	SourceColumn  string `json:"source_column" form:"source_column" query:"source_column" validate:"max=100"`
+	TypeColumn    string `json:"type_column" form:"type_column" query:"type_column" validate:"max=100"`
+	Source        string `json:"source" form:"source" query:"source" validate:"max=50"`
+	ValuationType string `json:"valuation_type" form:"valuation_type" query:"valuation_type" validate:"omitempty,oneof=market_value rental_estimate"`
+	DryRun        bool   `json:"dry_run" form:"dry_run" query:"dry_run"`
+}
+
+// List handles GET /properties/:id/valuations?type=rental_estimate
+func (h *ValuationHandler) List(c *fiber.Ctx) error {
+	id, err := parseUUIDParam(c, "id")
+	if err != nil {
+		return err
+	}
+	valuationType := c.Query("type")
+	if valuationType != "" && valuationType != models.ValuationTypeMarketValue && valuationType != models.ValuationTypeRentalEstimate {
+		return fiber.NewError(fiber.StatusBadRequest, "type must be market_value or rental_estimate")
+	}
+
+	valuations, err := h.valuations.List(id, valuationType)
+	if err != nil {
+		return serviceError(err)
+	}
+	return c.JSON(valuations)
+}
+
+// Create handles POST /properties/:id/valuations
+func (h *ValuationHandler) Create(c *fiber.Ctx) error {
+	id, err := parseUUIDParam(c, "id")
+	if err != nil {
+		return err
+	}
+
+	var req createValuationRequest
+	if err := parseBody(c, &req); err != nil {
+		return err
+	}
+
+	valuation := &models.PropertyValuation{
+		Source:        req.Source,
+		ValuationType: req.ValuationType,
+		Value:         req.Value,
+	}
+	if req.ValuationDate != "" {
+		// Already validated as YYYY-MM-DD
+		valuation.ValuationDate, _ = time.Parse("2006-01-02", req.ValuationDate)
+	}
+
+	if err := h.valuations.Create(middleware.CurrentUserID(c), id, valuation); err != nil {
+		return serviceError(err)
+	}
+	return c.Status(fiber.StatusCreated).JSON(valuation)
+}
+
+// Import handles POST /valuations/import. The CSV arrives either as the "file"
+// part of a multipart form, with the column mapping in the other form fields,
+// or as a text/csv body with the mapping in the query string.
+func (h *ValuationHandler) Import(c *fiber.Ctx) error {
+	var req importValuationsRequest
+	var csvData io.Reader
+
+	// c.Is("csv") depends on the host's MIME table, which may not know csv
+	if strings.HasPrefix(c.Get(fiber.HeaderContentType), "text/csv") {
+		if err := c.QueryParser(&req); err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, "invalid query parameters")
+		}
+		csvData = bytes.NewReader(c.Body())
+	} else {
+		if err := c.BodyParser(&req); err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+		}
+		header, err := c.FormFile("file")
+		if err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, "file is required")
+		}
+		file, err := header.Open()
+		if err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, "file could not be read")
+		}
+		defer file.Close()
+		csvData = file
+	}
+	if err := validate.Struct(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, validationMessage(err))
+	}
+
+	report, err := h.valuations.Import(middleware.CurrentUserID(c), csvData, services.ValuationImportMapping{
+		AddressColumn: req.AddressColumn,
+		CityColumn:    req.CityColumn,
+		StateColumn:   req.StateColumn,
+		ZipColumn:     req.ZipColumn,
+		ValueColumn:   req.ValueColumn,
+		DateColumn:    req.DateColumn,
+		SourceColumn:  req.SourceColumn,
+		TypeColumn:    req.TypeColumn,
+		Source:        req.Source,
+		ValuationType: req.ValuationType,
+	}, req.DryRun)
+	if err != nil {
+		return serviceError(err)
+	}
+	return c.JSON(report)
+}
+
+// Summary handles GET /properties/:id/valuations/summary
+func (h *ValuationHandler) Summary(c *fiber.Ctx) error {
+	id, err := parseUUIDParam(c, "id")
+	if err != nil {
+		return err
+	}
+
+	summary, err := h.valuations.Summary(id)
+	if err != nil {
+		return serviceError(err)
+	}
+	return c.JSON(summary)
+}
+
+// maxHistoryWindows caps the trailing windows asked for at once
+const maxHistoryWindows = 6
+
+// History handles GET /properties/:id/valuations/history?type=&sigma=2&windows=30,90,365
+func (h *ValuationHandler) History(c *fiber.Ctx) error {
+	id, err := parseUUIDParam(c, "id")
+	if err != nil {
+		return err
+	}
+	valuationType := c.Query("type")
+	if valuationType != "" && valuationType != models.ValuationTypeMarketValue && valuationType != models.ValuationTypeRentalEstimate {
+		return fiber.NewError(fiber.StatusBadRequest, "type must be market_value or rental_estimate")
+	}
+
+	sigma := models.DefaultOutlierSigma
+	if raw := c.Query("sigma"); raw != "" {
+		sigma, err = strconv.ParseFloat(raw, 64)
+		if err != nil || sigma <= 0 || sigma > 10 {
+			return fiber.NewError(fiber.StatusBadRequest, "sigma must be a number greater than 0 and at most 10")
+		}
+	}
+
+	windows := models.DefaultTrailingWindows
+	if raw := c.Query("windows"); raw != "" {
+		windows = nil
+		for _, part := range strings.Split(raw, ",") {
+			days, err := strconv.Atoi(strings.TrimSpace(part))
+			if err != nil || days < 1 || days > 3650 {
+				return fiber.NewError(fiber.StatusBadRequest, "windows must be comma-separated days between 1 and 3650")
+			}
+			windows = append(windows, days)
+		}
+		if len(windows) > maxHistoryWindows {
+			return fiber.NewError(fiber.StatusBadRequest, "at most 6 windows")
+		}
+	}
+
+	history, err := h.valuations.History(id, valuationType, sigma, windows)
+	if err != nil {
+		return serviceError(err)
+	}
+	return c.JSON(history)
+}
+
+// Metrics handles GET /properties/:id/metrics?rent=intended|market
+func (h *ValuationHandler) Metrics(c *fiber.Ctx) error {
+	id, err := parseUUIDParam(c, "id")
+	if err != nil {
+		return err
+	}
+
+	metrics, err := h.valuations.Metrics(id, c.Query("rent", models.RentBasisIntended))
+	if err != nil {
+		return serviceError(err)
+	}
+	return c.JSON(metrics)
+}
+
+// Sources handles GET /valuations/sources
+func (h *ValuationHandler) Sources(c *fiber.Ctx) error {
+	return c.JSON(fiber.Map{"sources": h.valuations.Sources()})
+}
@@ -0,0 +1,117 @@
+package middleware
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+// userIDKey is the fiber.Locals key holding the authenticated user's ID
+const userIDKey = "user_id"
+
+// devJWTSecret signs tokens in development when JWT_SECRET is not set
+const devJWTSecret = "dev-jwt-secret-key"
+
+// ErrNoJWTSecret is returned when JWT_SECRET is unset outside development
+var ErrNoJWTSecret = errors.New("JWT_SECRET must be set when ENV is not development")
+
+// Claims represents the JWT claims issued to authenticated users
+type Claims struct {
+	UserID string `json:"user_id"`
+	Email  string `json:"email"`
+	jwt.RegisteredClaims
+}
+
+// GenerateToken issues a signed JWT for the given user
+func GenerateToken(userID uuid.UUID, email string) (string, error) {
+	expiresIn, err := time.ParseDuration(getEnv("JWT_EXPIRES_IN", "24h"))
+	if err != nil {
+		return "", fmt.Errorf("invalid JWT_EXPIRES_IN: %w", err)
+	}
+
+	now := time.Now()
+	claims := Claims{
+		UserID: userID.String(),
+		Email:  email,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   userID.String(),
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(expiresIn)),
+		},
+	}
+
+	secret, err := jwtSecret()
+	if err != nil {
+		return "", err
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString(secret)
+}
+
+// RequireAuth validates the bearer token and stores the user ID in the request context
+func RequireAuth() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		header := c.Get(fiber.HeaderAuthorization)
+		tokenString, found := strings.CutPrefix(header, "Bearer ")
+		if !found || tokenString == "" {
+			return fiber.NewError(fiber.StatusUnauthorized, "missing or malformed authorization header")
+		}
+
+		claims := &Claims{}
+		token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+			return jwtSecret()
+		}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}))
+		if err != nil || !token.Valid {
+			return fiber.NewError(fiber.StatusUnauthorized, "invalid or expired token")
+		}
+
+		userID, err := uuid.Parse(claims.UserID)
+		if err != nil {
+			return fiber.NewError(fiber.StatusUnauthorized, "invalid token subject")
+		}
+
+		c.Locals(userIDKey, userID)
+		return c.Next()
+	}
+}
+
+// CurrentUserID returns the authenticated user's ID, or uuid.Nil if the request is anonymous
+func CurrentUserID(c *fiber.Ctx) uuid.UUID {
+	if userID, ok := c.Locals(userIDKey).(uuid.UUID); ok {
+		return userID
+	}
+	return uuid.Nil
+}
+
+// CheckJWTSecret reports whether tokens can be signed; the server refuses to
+// start without a JWT_SECRET unless ENV is development
+func CheckJWTSecret() error {
+	_, err := jwtSecret()
+	return err
+}
+
+// jwtSecret returns the HMAC key used to sign tokens. Only development falls
+// back to a well-known key.
+func jwtSecret() ([]byte, error) {
+	if secret := os.Getenv("JWT_SECRET"); secret != "" {
+		return []byte(secret), nil
+	}
+	if getEnv("ENV", "development") != "development" {
+		return nil, ErrNoJWTSecret
+	}
+	return []byte(devJWTSecret), nil
+}
+
+// getEnv gets an environment variable with a fallback default
+func getEnv(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
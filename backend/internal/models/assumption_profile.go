@@ -0,0 +1,126 @@
+package models
+
+import (
+	"math"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Assumption profile scopes
+const (
+	ProfileScopeUser = "user"
+	ProfileScopeTeam = "team"
+)
+
+// AssumptionProfile holds reusable market defaults keyed by ZIP code or metro area
+type AssumptionProfile struct {
+	ID               uuid.UUID `json:"id" gorm:"type:uuid;primary_key"`
+	UserID           uuid.UUID `json:"user_id" gorm:"type:uuid;not null;index"`
+	Name             string    `json:"name" gorm:"not null;size:100" validate:"required,max=100"`
+	Scope            string    `json:"scope" gorm:"not null;size:10;default:'user';check:scope IN ('user', 'team')" validate:"omitempty,oneof=user team"`
+	ZipCode          *string   `json:"zip_code" gorm:"size:10;index"`
+	Metro            *string   `json:"metro" gorm:"size:100;index"`
+	VacancyRate      *float64  `json:"vacancy_rate" gorm:"type:decimal(5,4)"`
+	MaintenancePct   *float64  `json:"maintenance_pct" gorm:"type:decimal(5,4)"`
+	ManagementPct    *float64  `json:"management_pct" gorm:"type:decimal(5,4)"`
+	TaxRatePct       *float64  `json:"tax_rate_pct" gorm:"type:decimal(5,3)"`
+	InsurancePer1000 *float64  `json:"insurance_per_1000" gorm:"type:decimal(8,2)"`
+	RentGrowthPct    *float64  `json:"rent_growth_pct" gorm:"type:decimal(5,2)"`
+	AppreciationPct  *float64  `json:"appreciation_pct" gorm:"type:decimal(5,2)"`
+	CreatedAt        time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt        time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+
+	// Relationships
+	User User `json:"user,omitempty" gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+}
+
+// BeforeCreate hook to generate UUID if not provided
+func (ap *AssumptionProfile) BeforeCreate(tx *gorm.DB) (err error) {
+	if ap.ID == uuid.Nil {
+		ap.ID = uuid.New()
+	}
+	if ap.Scope == "" {
+		ap.Scope = ProfileScopeUser
+	}
+	return
+}
+
+// TableName specifies the table name for GORM
+func (AssumptionProfile) TableName() string {
+	return "assumption_profiles"
+}
+
+// IsShared returns true if the profile is visible to the whole team
+func (ap *AssumptionProfile) IsShared() bool {
+	return ap.Scope == ProfileScopeTeam
+}
+
+// MatchRank ranks how specifically the profile applies to a location.
+// Higher is better; 0 means the profile does not apply. A profile with both
+// a ZIP code and a metro still applies metro-wide outside its ZIP code.
+func (ap *AssumptionProfile) MatchRank(zipCode, metro string) int {
+	hasZip := ap.ZipCode != nil && *ap.ZipCode != ""
+	hasMetro := ap.Metro != nil && *ap.Metro != ""
+	switch {
+	case hasZip && zipCode != "" && *ap.ZipCode == zipCode:
+		return 3
+	case hasMetro && metro != "" && strings.EqualFold(*ap.Metro, metro):
+		return 2
+	case hasZip || hasMetro:
+		return 0
+	default:
+		// Profiles without a location act as catch-all defaults
+		return 1
+	}
+}
+
+// ApplyTo fills operating assumptions and expenses the property does not
+// already define. It returns the defaulted values keyed by "<group>.<key>".
+func (ap *AssumptionProfile) ApplyTo(property *Property) JSONB {
+	defaulted := JSONB{}
+
+	if property.OperatingAssumptions == nil {
+		property.OperatingAssumptions = JSONB{}
+	}
+	if property.OperatingExpenses == nil {
+		property.OperatingExpenses = JSONB{}
+	}
+
+	fill := func(group string, values JSONB, key string, value *float64) {
+		if value == nil {
+			return
+		}
+		if _, ok := values[key]; ok {
+			return
+		}
+		values[key] = *value
+		defaulted[group+"."+key] = *value
+	}
+
+	fill("operating_assumptions", property.OperatingAssumptions, "vacancy_rate", ap.VacancyRate)
+	fill("operating_assumptions", property.OperatingAssumptions, "maintenance_pct", ap.MaintenancePct)
+	fill("operating_assumptions", property.OperatingAssumptions, "management_pct", ap.ManagementPct)
+	fill("operating_assumptions", property.OperatingAssumptions, "rent_growth_pct", ap.RentGrowthPct)
+	fill("operating_assumptions", property.OperatingAssumptions, "appreciation_pct", ap.AppreciationPct)
+
+	if property.PurchasePrice > 0 {
+		if ap.TaxRatePct != nil {
+			taxes := roundCents(property.PurchasePrice * *ap.TaxRatePct / 100)
+			fill("operating_expenses", property.OperatingExpenses, "property_taxes", &taxes)
+		}
+		if ap.InsurancePer1000 != nil {
+			insurance := roundCents(property.PurchasePrice / 1000 * *ap.InsurancePer1000)
+			fill("operating_expenses", property.OperatingExpenses, "insurance", &insurance)
+		}
+	}
+
+	return defaulted
+}
+
+// roundCents rounds a currency amount to two decimals
+func roundCents(value float64) float64 {
+	return math.Round(value*100) / 100
+}
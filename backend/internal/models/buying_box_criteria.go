@@ -1,28 +1,37 @@
 package models
 
 import (
+	"database/sql/driver"
+	"fmt"
+	"math"
+	"strconv"
 	"time"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
 )
 
 // BuyingBoxCriteria represents user-defined investment criteria for property evaluation
 type BuyingBoxCriteria struct {
-	ID                      uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
-	UserID                  uuid.UUID `json:"user_id" gorm:"type:uuid;not null;index"`
-	Name                    string    `json:"name" gorm:"not null;size:100" validate:"required,max=100"`
-	MinCapRate              *float64  `json:"min_cap_rate" gorm:"type:decimal(5,2)"`
-	MinCashOnCash           *float64  `json:"min_cash_on_cash" gorm:"type:decimal(5,2)"`
-	MaxPurchasePrice        *float64  `json:"max_purchase_price" gorm:"type:decimal(12,2)"`
-	MinRentToValue          *float64  `json:"min_rent_to_value" gorm:"type:decimal(5,2)"`
-	MaxYearBuilt            *int      `json:"max_year_built"`
-	MinYearBuilt            *int      `json:"min_year_built" gorm:"check:min_year_built IS NULL OR max_year_built IS NULL OR min_year_built <= max_year_built"`
-	LocationPreferences     JSONB     `json:"location_preferences" gorm:"type:jsonb;default:'{}'"`
-	PropertyTypePreferences JSONB     `json:"property_type_preferences" gorm:"type:jsonb;default:'{}'"`
-	IsActive                bool      `json:"is_active" gorm:"default:true;index"`
-	CreatedAt               time.Time `json:"created_at" gorm:"autoCreateTime"`
-	UpdatedAt               time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+	ID                      uuid.UUID               `json:"id" gorm:"type:uuid;primary_key"`
+	UserID                  uuid.UUID               `json:"user_id" gorm:"type:uuid;not null;index"`
+	Name                    string                  `json:"name" gorm:"not null;size:100" validate:"required,max=100"`
+	MinCapRate              *float64                `json:"min_cap_rate" gorm:"type:decimal(5,2)"`
+	MinCashOnCash           *float64                `json:"min_cash_on_cash" gorm:"type:decimal(5,2)"`
+	MaxPurchasePrice        *float64                `json:"max_purchase_price" gorm:"type:decimal(12,2)"`
+	MinRentToValue          *float64                `json:"min_rent_to_value" gorm:"type:decimal(5,2)"`
+	MaxYearBuilt            *int                    `json:"max_year_built"`
+	MinYearBuilt            *int                    `json:"min_year_built" gorm:"check:min_year_built IS NULL OR max_year_built IS NULL OR min_year_built <= max_year_built"`
+	LocationPreferences     LocationPreferences     `json:"location_preferences" gorm:"default:'{}'"`
+	PropertyTypePreferences PropertyTypePreferences `json:"property_type_preferences" gorm:"default:'{}'"`
+	CustomRules             CustomRules             `json:"custom_rules" gorm:"default:'[]'" validate:"dive"`
+	ScoringRules            CriterionRules          `json:"scoring_rules" gorm:"default:'{}'"`
+	MissingDataPolicy       string                  `json:"missing_data_policy" gorm:"size:10;default:'flag';check:missing_data_policy IN ('fail', 'ignore', 'flag')" validate:"omitempty,oneof=fail ignore flag"`
+	IsActive                bool                    `json:"is_active" gorm:"default:true;index"`
+	Version                 int                     `json:"version" gorm:"not null;default:1"`
+	CreatedAt               time.Time               `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt               time.Time               `json:"updated_at" gorm:"autoUpdateTime"`
 
 	// Relationships
 	User User `json:"user,omitempty" gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
@@ -36,113 +45,441 @@ func (bbc *BuyingBoxCriteria) BeforeCreate(tx *gorm.DB) (err error) {
 	return
 }
 
+// BeforeSave hook rejects criteria with custom rules that do not parse
+func (bbc *BuyingBoxCriteria) BeforeSave(tx *gorm.DB) (err error) {
+	return bbc.CustomRules.Validate()
+}
+
 // TableName specifies the table name for GORM
 func (BuyingBoxCriteria) TableName() string {
 	return "buying_box_criteria"
 }
 
-// PropertyComparison represents how a property compares against buying criteria
+// Missing data policies decide how criteria that cannot be evaluated affect the score
+const (
+	// MissingDataFail treats an unknown criterion as missed with no credit
+	MissingDataFail = "fail"
+	// MissingDataIgnore leaves unknown criteria out of the score
+	MissingDataIgnore = "ignore"
+	// MissingDataFlag leaves unknown criteria out of the score and flags the comparison for review
+	MissingDataFlag = "flag"
+)
+
+// Criterion outcomes
+const (
+	OutcomeMet     = "met"
+	OutcomeMissed  = "missed"
+	OutcomeUnknown = "unknown"
+)
+
+// CriterionRule configures how a single criterion contributes to the comparison score
+type CriterionRule struct {
+	// Weight scales the criterion's contribution; defaults to 1
+	Weight *float64 `json:"weight,omitempty"`
+	// Hard criteria zero the whole score when missed
+	Hard bool `json:"hard,omitempty"`
+	// Tolerance is the distance past the threshold at which partial credit reaches zero
+	Tolerance *float64 `json:"tolerance,omitempty"`
+}
+
+// CriterionRules maps criterion names (cap_rate, purchase_price, ...) to their scoring rules
+type CriterionRules map[string]CriterionRule
+
+// Scan implements the Scanner interface for database/sql
+func (r *CriterionRules) Scan(value interface{}) error {
+	*r = make(CriterionRules)
+	return scanJSON(value, r)
+}
+
+// Value implements the Valuer interface for database/sql
+func (r CriterionRules) Value() (driver.Value, error) {
+	if r == nil {
+		return "{}", nil
+	}
+	return jsonValue(r)
+}
+
+// GormDBDataType implements schema.GormDBDataTypeInterface
+func (CriterionRules) GormDBDataType(db *gorm.DB, field *schema.Field) string {
+	return jsonDataType(db)
+}
+
+// Default partial-credit tolerances per criterion, in the criterion's own units
+var defaultTolerances = map[string]float64{
+	"cap_rate":       2,
+	"cash_on_cash":   5,
+	"rent_to_value":  2,
+	"min_year_built": 10,
+	"max_year_built": 10,
+}
+
+// Default partial-credit tolerances expressed as a fraction of the threshold
+var defaultRelativeTolerances = map[string]float64{
+	"purchase_price":  0.10,
+	"location_radius": 0.25,
+}
+
+// CriterionResult explains how one criterion contributed to the score
+type CriterionResult struct {
+	Criterion   string   `json:"criterion"`
+	Threshold   *float64 `json:"threshold,omitempty"`
+	Actual      *float64 `json:"actual,omitempty"`
+	Outcome     string   `json:"outcome"`
+	Met         bool     `json:"met"`
+	Hard        bool     `json:"hard"`
+	Weight      float64  `json:"weight"`
+	Credit      float64  `json:"credit"`
+	Points      float64  `json:"points"`
+	Explanation string   `json:"explanation"`
+}
+
+// PropertyComparison represents how a property compares against buying criteria.
+// Matches only holds criteria that could be evaluated; criteria lacking data are
+// listed in UnknownCriteria and reduce Completeness instead.
 type PropertyComparison struct {
-	Property       *Property          `json:"property"`
-	Criteria       *BuyingBoxCriteria `json:"criteria"`
-	Matches        map[string]bool    `json:"matches"`
-	Score          float64            `json:"score"`
-	FailureReasons []string           `json:"failure_reasons,omitempty"`
+	Property          *Property          `json:"property"`
+	Criteria          *BuyingBoxCriteria `json:"criteria"`
+	CriteriaVersion   int                `json:"criteria_version"`
+	Matches           map[string]bool    `json:"matches"`
+	Score             float64            `json:"score"`
+	HardFailure       bool               `json:"hard_failure"`
+	MeetsCriteria     bool               `json:"meets_criteria"`
+	Completeness      float64            `json:"completeness"`
+	MissingDataPolicy string             `json:"missing_data_policy"`
+	NeedsReview       bool               `json:"needs_review"`
+	UnknownCriteria   []string           `json:"unknown_criteria"`
+	Contributions     []CriterionResult  `json:"contributions"`
+	FailureReasons    []string           `json:"failure_reasons,omitempty"`
+}
+
+// criterionCheck is a single comparison evaluated by CompareProperty. Threshold
+// checks compare actual against threshold; categorical checks (location, property
+// type) are decided up front and carry their outcome in met and detail.
+type criterionCheck struct {
+	name      string
+	threshold float64
+	actual    float64
+	// atMost is true for maximum thresholds, false for minimums
+	atMost        bool
+	failureReason string
+
+	categorical bool
+	met         bool
+	detail      string
+
+	// missing names the data that prevented evaluation; set for unknown checks
+	missing string
 }
 
-// CompareProperty evaluates a property against this buying criteria
+// CompareProperty evaluates a property against this buying criteria.
+// Each criterion earns full credit when met and partial credit that fades out
+// linearly over its tolerance when missed; the score is the weighted average of
+// credits. Missing any hard criterion zeroes the score.
+//
+// Criteria the property lacks data for are reported as unknown and handled by the
+// missing data policy. Whatever the policy, a comparison with unknown criteria
+// never meets the criteria outright.
 func (bbc *BuyingBoxCriteria) CompareProperty(property *Property, metrics *FinancialMetrics) *PropertyComparison {
+	policy := bbc.GetMissingDataPolicy()
 	comparison := &PropertyComparison{
-		Property:       property,
-		Criteria:       bbc,
-		Matches:        make(map[string]bool),
-		FailureReasons: []string{},
+		Property:          property,
+		Criteria:          bbc,
+		CriteriaVersion:   bbc.Version,
+		Matches:           make(map[string]bool),
+		MissingDataPolicy: policy,
+		UnknownCriteria:   []string{},
+		Contributions:     []CriterionResult{},
+		FailureReasons:    []string{},
 	}
 
-	totalCriteria := 0
-	metCriteria := 0
+	checks := bbc.collectChecks(property, metrics)
+	totalWeight := 0.0
+	earnedPoints := 0.0
+	for _, check := range checks {
+		result := bbc.evaluateCheck(check, policy)
+		comparison.Contributions = append(comparison.Contributions, result)
 
-	// Check cap rate
-	if bbc.MinCapRate != nil && metrics != nil && metrics.CapRate != nil {
-		totalCriteria++
-		if *metrics.CapRate >= *bbc.MinCapRate {
-			comparison.Matches["cap_rate"] = true
-			metCriteria++
+		if check.missing != "" {
+			comparison.UnknownCriteria = append(comparison.UnknownCriteria, check.name)
+			switch policy {
+			case MissingDataFail:
+				comparison.FailureReasons = append(comparison.FailureReasons, "Missing "+check.missing)
+				if result.Hard {
+					comparison.HardFailure = true
+				}
+			case MissingDataFlag:
+				comparison.FailureReasons = append(comparison.FailureReasons, "Missing "+check.missing)
+				comparison.NeedsReview = true
+			}
+			if policy != MissingDataFail {
+				continue
+			}
 		} else {
-			comparison.Matches["cap_rate"] = false
-			comparison.FailureReasons = append(comparison.FailureReasons, "Cap rate below minimum")
+			comparison.Matches[check.name] = result.Met
+			if !result.Met {
+				comparison.FailureReasons = append(comparison.FailureReasons, check.failureReason)
+				if result.Hard {
+					comparison.HardFailure = true
+				}
+			}
 		}
+
+		totalWeight += result.Weight
+		earnedPoints += result.Points
 	}
 
-	// Check cash-on-cash return
-	if bbc.MinCashOnCash != nil && metrics != nil && metrics.CashOnCashReturn != nil {
-		totalCriteria++
-		if *metrics.CashOnCashReturn >= *bbc.MinCashOnCash {
-			comparison.Matches["cash_on_cash"] = true
-			metCriteria++
-		} else {
-			comparison.Matches["cash_on_cash"] = false
-			comparison.FailureReasons = append(comparison.FailureReasons, "Cash-on-cash return below minimum")
+	// Calculate score
+	switch {
+	case comparison.HardFailure:
+		comparison.Score = 0
+	case totalWeight > 0:
+		comparison.Score = earnedPoints / totalWeight * 100
+	case len(comparison.UnknownCriteria) > 0:
+		comparison.Score = 0 // Nothing could be verified
+	default:
+		comparison.Score = 100 // No criteria defined, so property "passes"
+	}
+
+	comparison.Completeness = 100
+	if len(checks) > 0 {
+		known := len(checks) - len(comparison.UnknownCriteria)
+		comparison.Completeness = float64(known) / float64(len(checks)) * 100
+	}
+
+	comparison.MeetsCriteria = !comparison.HardFailure &&
+		len(comparison.UnknownCriteria) == 0 &&
+		len(comparison.FailureReasons) == 0
+
+	return comparison
+}
+
+// GetMissingDataPolicy returns the configured missing data policy, defaulting to flag
+func (bbc *BuyingBoxCriteria) GetMissingDataPolicy() string {
+	switch bbc.MissingDataPolicy {
+	case MissingDataFail, MissingDataIgnore:
+		return bbc.MissingDataPolicy
+	default:
+		return MissingDataFlag
+	}
+}
+
+// collectChecks lists the criteria that apply to the property, including those
+// that cannot be evaluated because the property lacks the data
+func (bbc *BuyingBoxCriteria) collectChecks(property *Property, metrics *FinancialMetrics) []criterionCheck {
+	var checks []criterionCheck
+
+	// metricCheck builds a minimum-threshold check on a financial metric
+	metricCheck := func(name string, threshold *float64, value func(*FinancialMetrics) *float64, label, failureReason string) {
+		if threshold == nil {
+			return
 		}
+		check := criterionCheck{name: name, threshold: *threshold, failureReason: failureReason}
+		switch {
+		case metrics == nil:
+			check.missing = "financial metrics"
+		case value(metrics) == nil:
+			check.missing = label
+		default:
+			check.actual = *value(metrics)
+		}
+		checks = append(checks, check)
 	}
 
+	// Check cap rate
+	metricCheck("cap_rate", bbc.MinCapRate, func(m *FinancialMetrics) *float64 { return m.CapRate },
+		"cap rate", "Cap rate below minimum")
+
+	// Check cash-on-cash return
+	metricCheck("cash_on_cash", bbc.MinCashOnCash, func(m *FinancialMetrics) *float64 { return m.CashOnCashReturn },
+		"cash-on-cash return", "Cash-on-cash return below minimum")
+
 	// Check maximum purchase price
 	if bbc.MaxPurchasePrice != nil {
-		totalCriteria++
-		if property.PurchasePrice <= *bbc.MaxPurchasePrice {
-			comparison.Matches["purchase_price"] = true
-			metCriteria++
-		} else {
-			comparison.Matches["purchase_price"] = false
-			comparison.FailureReasons = append(comparison.FailureReasons, "Purchase price above maximum")
+		check := criterionCheck{name: "purchase_price", threshold: *bbc.MaxPurchasePrice, actual: property.PurchasePrice, atMost: true, failureReason: "Purchase price above maximum"}
+		if property.PurchasePrice <= 0 {
+			check.missing = "purchase price"
 		}
+		checks = append(checks, check)
 	}
 
 	// Check rent-to-value ratio
-	if bbc.MinRentToValue != nil && metrics != nil && metrics.RentToValueRatio != nil {
-		totalCriteria++
-		if *metrics.RentToValueRatio >= *bbc.MinRentToValue {
-			comparison.Matches["rent_to_value"] = true
-			metCriteria++
+	metricCheck("rent_to_value", bbc.MinRentToValue, func(m *FinancialMetrics) *float64 { return m.RentToValueRatio },
+		"rent-to-value ratio", "Rent-to-value ratio below minimum")
+
+	// Check year built range
+	yearCheck := func(name string, threshold *int, atMost bool, failureReason string) {
+		if threshold == nil {
+			return
+		}
+		check := criterionCheck{name: name, threshold: float64(*threshold), atMost: atMost, failureReason: failureReason}
+		if property.YearBuilt == nil {
+			check.missing = "year built"
 		} else {
-			comparison.Matches["rent_to_value"] = false
-			comparison.FailureReasons = append(comparison.FailureReasons, "Rent-to-value ratio below minimum")
+			check.actual = float64(*property.YearBuilt)
 		}
+		checks = append(checks, check)
 	}
+	yearCheck("min_year_built", bbc.MinYearBuilt, false, "Property too old")
+	yearCheck("max_year_built", bbc.MaxYearBuilt, true, "Property too new")
 
-	// Check year built range
-	if property.YearBuilt != nil {
-		if bbc.MinYearBuilt != nil {
-			totalCriteria++
-			if *property.YearBuilt >= *bbc.MinYearBuilt {
-				comparison.Matches["min_year_built"] = true
-				metCriteria++
-			} else {
-				comparison.Matches["min_year_built"] = false
-				comparison.FailureReasons = append(comparison.FailureReasons, "Property too old")
-			}
+	// Check location allow/exclusion lists
+	if bbc.LocationPreferences.hasListFilters() {
+		check := criterionCheck{name: "location", categorical: true, failureReason: "Location outside preferred areas"}
+		if property.State == nil && property.City == nil && property.ZipCode == nil {
+			check.missing = "city, state and ZIP code"
+		} else {
+			check.met, check.detail = bbc.LocationPreferences.evaluateLists(property)
 		}
+		checks = append(checks, check)
+	}
+
+	// Check distance from the preferred point
+	if radius := bbc.LocationPreferences.Radius; radius != nil {
+		check := criterionCheck{name: "location_radius", threshold: radius.Miles, atMost: true, failureReason: "Property outside search radius"}
+		if property.HasCoordinates() {
+			check.actual = DistanceMiles(radius.Latitude, radius.Longitude, *property.Latitude, *property.Longitude)
+		} else {
+			check.missing = "coordinates"
+		}
+		checks = append(checks, check)
+	}
+
+	// Check property type
+	if !bbc.PropertyTypePreferences.IsEmpty() {
+		check := criterionCheck{name: "property_type", categorical: true, failureReason: "Property type not preferred"}
+		if property.PropertyType == nil {
+			check.missing = "property type"
+		} else {
+			check.met, check.detail = bbc.PropertyTypePreferences.evaluate(*property.PropertyType)
+		}
+		checks = append(checks, check)
+	}
+
+	// Check custom rule expressions
+	checks = append(checks, bbc.customRuleChecks(property, metrics)...)
+
+	return checks
+}
+
+// evaluateCheck scores one criterion according to its rule
+func (bbc *BuyingBoxCriteria) evaluateCheck(check criterionCheck, policy string) CriterionResult {
+	rule := bbc.ruleFor(check.name)
+
+	weight := 1.0
+	if rule.Weight != nil && *rule.Weight >= 0 {
+		weight = *rule.Weight
+	}
 
-		if bbc.MaxYearBuilt != nil {
-			totalCriteria++
-			if *property.YearBuilt <= *bbc.MaxYearBuilt {
-				comparison.Matches["max_year_built"] = true
-				metCriteria++
+	result := CriterionResult{
+		Criterion: check.name,
+		Hard:      rule.Hard,
+		Weight:    weight,
+	}
+
+	if check.missing != "" {
+		result.Outcome = OutcomeUnknown
+		if !check.categorical {
+			threshold := check.threshold
+			result.Threshold = &threshold
+		}
+		switch policy {
+		case MissingDataFail:
+			if rule.Hard {
+				result.Explanation = "unknown: missing " + check.missing + "; hard criterion, score set to 0"
 			} else {
-				comparison.Matches["max_year_built"] = false
-				comparison.FailureReasons = append(comparison.FailureReasons, "Property too new")
+				result.Explanation = "unknown: missing " + check.missing + "; counted as missed, no credit"
 			}
+		case MissingDataIgnore:
+			result.Explanation = "unknown: missing " + check.missing + "; left out of the score"
+		default:
+			result.Explanation = "unknown: missing " + check.missing + "; left out of the score and flagged for review"
 		}
+		return result
 	}
 
-	// Calculate score
-	if totalCriteria > 0 {
-		comparison.Score = float64(metCriteria) / float64(totalCriteria) * 100
-	} else {
-		comparison.Score = 100 // No criteria defined, so property "passes"
+	if check.categorical {
+		// Categorical criteria are all or nothing
+		result.Met = check.met
+		switch {
+		case result.Met:
+			result.Credit = 1
+			result.Explanation = check.detail + ": met, full credit"
+		case rule.Hard:
+			result.Explanation = check.detail + ": hard criterion, score set to 0"
+		default:
+			result.Explanation = check.detail + ": no credit"
+		}
+		result.Outcome = outcomeOf(result.Met)
+		result.Points = result.Credit * weight
+		return result
 	}
 
-	return comparison
+	// shortfall is how far the actual value is on the wrong side of the threshold
+	shortfall := check.threshold - check.actual
+	comparator := ">="
+	if check.atMost {
+		shortfall = check.actual - check.threshold
+		comparator = "<="
+	}
+
+	threshold, actual := check.threshold, check.actual
+	result.Threshold = &threshold
+	result.Actual = &actual
+	result.Met = shortfall <= 0
+
+	switch {
+	case result.Met:
+		result.Credit = 1
+		result.Explanation = fmt.Sprintf("%s %s %s: met, full credit", formatAmount(check.actual), comparator, formatAmount(check.threshold))
+	case rule.Hard:
+		result.Credit = 0
+		result.Explanation = fmt.Sprintf("%s misses %s %s by %s: hard criterion, score set to 0",
+			formatAmount(check.actual), comparator, formatAmount(check.threshold), formatAmount(shortfall))
+	default:
+		tolerance := bbc.toleranceFor(check, rule)
+		if tolerance > 0 {
+			result.Credit = math.Max(0, 1-shortfall/tolerance)
+		}
+		result.Explanation = fmt.Sprintf("%s misses %s %s by %s (tolerance %s): %.0f%% partial credit",
+			formatAmount(check.actual), comparator, formatAmount(check.threshold), formatAmount(shortfall),
+			formatAmount(tolerance), result.Credit*100)
+	}
+
+	result.Outcome = outcomeOf(result.Met)
+	result.Points = result.Credit * weight
+	return result
+}
+
+// outcomeOf maps an evaluated criterion to its outcome
+func outcomeOf(met bool) string {
+	if met {
+		return OutcomeMet
+	}
+	return OutcomeMissed
+}
+
+// ruleFor returns the configured rule for a criterion, or the default soft rule
+func (bbc *BuyingBoxCriteria) ruleFor(name string) CriterionRule {
+	if rule, ok := bbc.ScoringRules[name]; ok {
+		return rule
+	}
+	return CriterionRule{}
+}
+
+// toleranceFor returns the distance past the threshold at which credit reaches zero
+func (bbc *BuyingBoxCriteria) toleranceFor(check criterionCheck, rule CriterionRule) float64 {
+	if rule.Tolerance != nil {
+		return *rule.Tolerance
+	}
+	if fraction, ok := defaultRelativeTolerances[check.name]; ok {
+		return check.threshold * fraction
+	}
+	return defaultTolerances[check.name]
+}
+
+// formatAmount renders a number without trailing zeros
+func formatAmount(value float64) string {
+	return strconv.FormatFloat(math.Round(value*100)/100, 'f', -1, 64)
 }
 
 // Deactivate marks the criteria as inactive
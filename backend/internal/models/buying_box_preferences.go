@@ -0,0 +1,186 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// earthRadiusMiles is the mean Earth radius used for distance calculations
+const earthRadiusMiles = 3958.8
+
+// RadiusPreference limits properties to a distance from a point
+type RadiusPreference struct {
+	Latitude  float64 `json:"latitude" validate:"gte=-90,lte=90"`
+	Longitude float64 `json:"longitude" validate:"gte=-180,lte=180"`
+	Miles     float64 `json:"miles" validate:"gt=0"`
+}
+
+// LocationPreferences describes where a buying box looks for properties.
+// Every non-empty allow list must contain the property's value; any exclusion
+// match rejects the property.
+type LocationPreferences struct {
+	States          []string          `json:"states,omitempty"`
+	Cities          []string          `json:"cities,omitempty"`
+	ZipCodes        []string          `json:"zip_codes,omitempty"`
+	ExcludeStates   []string          `json:"exclude_states,omitempty"`
+	ExcludeCities   []string          `json:"exclude_cities,omitempty"`
+	ExcludeZipCodes []string          `json:"exclude_zip_codes,omitempty"`
+	Radius          *RadiusPreference `json:"radius,omitempty" validate:"omitempty"`
+}
+
+// Scan implements the Scanner interface for database/sql
+func (lp *LocationPreferences) Scan(value interface{}) error {
+	*lp = LocationPreferences{}
+	return scanJSON(value, lp)
+}
+
+// Value implements the Valuer interface for database/sql
+func (lp LocationPreferences) Value() (driver.Value, error) {
+	return jsonValue(lp)
+}
+
+// GormDBDataType implements schema.GormDBDataTypeInterface
+func (LocationPreferences) GormDBDataType(db *gorm.DB, field *schema.Field) string {
+	return jsonDataType(db)
+}
+
+// IsEmpty returns true if no location filtering is configured
+func (lp *LocationPreferences) IsEmpty() bool {
+	return !lp.hasListFilters() && lp.Radius == nil
+}
+
+// hasListFilters returns true if any allow or exclusion list is set
+func (lp *LocationPreferences) hasListFilters() bool {
+	return len(lp.States) > 0 || len(lp.Cities) > 0 || len(lp.ZipCodes) > 0 ||
+		len(lp.ExcludeStates) > 0 || len(lp.ExcludeCities) > 0 || len(lp.ExcludeZipCodes) > 0
+}
+
+// evaluateLists checks the property's address components against the allow and
+// exclusion lists. It returns whether the property matches and why not.
+func (lp *LocationPreferences) evaluateLists(property *Property) (bool, string) {
+	state := derefString(property.State)
+	city := derefString(property.City)
+	zip := derefString(property.ZipCode)
+
+	switch {
+	case containsFold(lp.ExcludeStates, state):
+		return false, fmt.Sprintf("state %s is excluded", state)
+	case containsFold(lp.ExcludeCities, city):
+		return false, fmt.Sprintf("city %s is excluded", city)
+	case containsFold(lp.ExcludeZipCodes, zip):
+		return false, fmt.Sprintf("ZIP code %s is excluded", zip)
+	case len(lp.States) > 0 && !containsFold(lp.States, state):
+		return false, fmt.Sprintf("state %q not in %s", state, strings.Join(lp.States, ", "))
+	case len(lp.Cities) > 0 && !containsFold(lp.Cities, city):
+		return false, fmt.Sprintf("city %q not in %s", city, strings.Join(lp.Cities, ", "))
+	case len(lp.ZipCodes) > 0 && !containsFold(lp.ZipCodes, zip):
+		return false, fmt.Sprintf("ZIP code %q not in %s", zip, strings.Join(lp.ZipCodes, ", "))
+	}
+	return true, "location within preferred areas"
+}
+
+// PropertyTypePreferences lists the property types a buying box accepts or rejects
+type PropertyTypePreferences struct {
+	Types        []string `json:"types,omitempty" validate:"dive,oneof=sfr duplex triplex fourplex multifamily condo townhouse"`
+	ExcludeTypes []string `json:"exclude_types,omitempty" validate:"dive,oneof=sfr duplex triplex fourplex multifamily condo townhouse"`
+}
+
+// Scan implements the Scanner interface for database/sql
+func (ptp *PropertyTypePreferences) Scan(value interface{}) error {
+	*ptp = PropertyTypePreferences{}
+	return scanJSON(value, ptp)
+}
+
+// Value implements the Valuer interface for database/sql
+func (ptp PropertyTypePreferences) Value() (driver.Value, error) {
+	return jsonValue(ptp)
+}
+
+// GormDBDataType implements schema.GormDBDataTypeInterface
+func (PropertyTypePreferences) GormDBDataType(db *gorm.DB, field *schema.Field) string {
+	return jsonDataType(db)
+}
+
+// IsEmpty returns true if no property type filtering is configured
+func (ptp *PropertyTypePreferences) IsEmpty() bool {
+	return len(ptp.Types) == 0 && len(ptp.ExcludeTypes) == 0
+}
+
+// evaluate checks a property type against the preferences
+func (ptp *PropertyTypePreferences) evaluate(propertyType string) (bool, string) {
+	if containsFold(ptp.ExcludeTypes, propertyType) {
+		return false, fmt.Sprintf("property type %s is excluded", propertyType)
+	}
+	if len(ptp.Types) > 0 && !containsFold(ptp.Types, propertyType) {
+		return false, fmt.Sprintf("property type %s not in %s", propertyType, strings.Join(ptp.Types, ", "))
+	}
+	return true, fmt.Sprintf("property type %s accepted", propertyType)
+}
+
+// DistanceMiles returns the great-circle distance between two coordinates
+func DistanceMiles(lat1, lon1, lat2, lon2 float64) float64 {
+	toRadians := func(deg float64) float64 { return deg * math.Pi / 180 }
+
+	dLat := toRadians(lat2 - lat1)
+	dLon := toRadians(lon2 - lon1)
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(toRadians(lat1))*math.Cos(toRadians(lat2))*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return earthRadiusMiles * 2 * math.Atan2(math.Sqrt(a), math.Sqrt(1-a))
+}
+
+// scanJSON decodes a JSON database value into dst, leaving dst untouched for NULL
+func scanJSON(value interface{}, dst interface{}) error {
+	switch v := value.(type) {
+	case []byte:
+		return json.Unmarshal(v, dst)
+	case string:
+		return json.Unmarshal([]byte(v), dst)
+	default:
+		return nil
+	}
+}
+
+// jsonValue encodes v as JSON text, which PostgreSQL jsonb and SQLite json
+// columns both accept
+func jsonValue(v interface{}) (driver.Value, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// jsonDataType is the column type for JSON values on the connected database
+func jsonDataType(db *gorm.DB) string {
+	if db.Dialector.Name() == "postgres" {
+		return "jsonb"
+	}
+	return "json"
+}
+
+// containsFold reports whether list contains value, ignoring case
+func containsFold(list []string, value string) bool {
+	if value == "" {
+		return false
+	}
+	for _, item := range list {
+		if strings.EqualFold(strings.TrimSpace(item), value) {
+			return true
+		}
+	}
+	return false
+}
+
+// derefString returns the pointed-to string or ""
+func derefString(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
@@ -0,0 +1,206 @@
+package models
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+
+	"rental-property-mgmt/pkg/rules"
+)
+
+// customRulePrefix namespaces custom rule names in comparison results and scoring rules
+const customRulePrefix = "rule:"
+
+// RuleFields lists the fields custom rule expressions may reference
+var RuleFields = []string{
+	"purchase_price",
+	"intended_rent",
+	"year_built",
+	"building_area_sqft",
+	"land_area_sqft",
+	"price_per_sqft",
+	"rent_per_sqft",
+	"cap_rate",
+	"cash_on_cash",
+	"rent_to_value",
+	"gross_rent_multiplier",
+	"noi",
+	"monthly_mortgage_payment",
+	"monthly_cash_flow",
+	"cash_to_close",
+	"dscr",
+}
+
+// CustomRule is a named rule expression evaluated alongside the built-in criteria,
+// for example "price per sqft < 150" or "dscr >= 1.25 or cash_on_cash >= 10"
+type CustomRule struct {
+	Name        string `json:"name" validate:"required,max=50"`
+	Expression  string `json:"expression" validate:"required"`
+	Description string `json:"description,omitempty" validate:"max=255"`
+}
+
+// CustomRules is the list of custom rules stored on a criteria set
+type CustomRules []CustomRule
+
+// Scan implements the Scanner interface for database/sql
+func (cr *CustomRules) Scan(value interface{}) error {
+	*cr = CustomRules{}
+	return scanJSON(value, cr)
+}
+
+// Value implements the Valuer interface for database/sql
+func (cr CustomRules) Value() (driver.Value, error) {
+	if cr == nil {
+		return "[]", nil
+	}
+	return jsonValue(cr)
+}
+
+// GormDBDataType implements schema.GormDBDataTypeInterface
+func (CustomRules) GormDBDataType(db *gorm.DB, field *schema.Field) string {
+	return jsonDataType(db)
+}
+
+// Validate parses every rule, returning the first syntax error with the rule's name
+func (cr CustomRules) Validate() error {
+	seen := make(map[string]bool, len(cr))
+	for _, rule := range cr {
+		name := strings.TrimSpace(rule.Name)
+		if name == "" {
+			return fmt.Errorf("custom rule name is required")
+		}
+		if seen[strings.ToLower(name)] {
+			return fmt.Errorf("custom rule %q is defined more than once", name)
+		}
+		seen[strings.ToLower(name)] = true
+
+		if _, err := rules.Parse(rule.Expression, RuleFields); err != nil {
+			return fmt.Errorf("custom rule %q: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// ruleValues exposes property fields and metrics to rule expressions
+func ruleValues(property *Property, metrics *FinancialMetrics) rules.Values {
+	values := rules.Values{
+		"purchase_price":     positive(property.PurchasePrice),
+		"intended_rent":      property.IntendedRent,
+		"year_built":         intToFloat(property.YearBuilt),
+		"building_area_sqft": intToFloat(property.BuildingAreaSqft),
+		"land_area_sqft":     intToFloat(property.LandAreaSqft),
+	}
+
+	if sqft := values["building_area_sqft"]; sqft != nil && *sqft > 0 {
+		if price := values["purchase_price"]; price != nil {
+			values["price_per_sqft"] = positive(*price / *sqft)
+		}
+		if property.IntendedRent != nil {
+			rentPerSqft := *property.IntendedRent / *sqft
+			values["rent_per_sqft"] = &rentPerSqft
+		}
+	}
+
+	if metrics != nil {
+		values["cap_rate"] = metrics.CapRate
+		values["cash_on_cash"] = metrics.CashOnCashReturn
+		values["rent_to_value"] = metrics.RentToValueRatio
+		values["gross_rent_multiplier"] = metrics.GrossRentMultiplier
+		values["noi"] = metrics.NetOperatingIncome
+		values["monthly_mortgage_payment"] = metrics.MonthlyMortgagePayment
+		values["cash_to_close"] = metrics.CashToClose
+
+		if metrics.NetOperatingIncome != nil && metrics.MonthlyMortgagePayment != nil {
+			annualDebtService := *metrics.MonthlyMortgagePayment * 12
+			cashFlow := (*metrics.NetOperatingIncome - annualDebtService) / 12
+			values["monthly_cash_flow"] = &cashFlow
+			if annualDebtService > 0 {
+				dscr := *metrics.NetOperatingIncome / annualDebtService
+				values["dscr"] = &dscr
+			}
+		}
+	}
+
+	return values
+}
+
+// customRuleChecks turns each custom rule into a categorical check. Rules that
+// fail to parse, or have no result although every field has a value (e.g. a
+// division by zero), are reported as missed so a bad rule never passes silently.
+func (bbc *BuyingBoxCriteria) customRuleChecks(property *Property, metrics *FinancialMetrics) []criterionCheck {
+	if len(bbc.CustomRules) == 0 {
+		return nil
+	}
+
+	values := ruleValues(property, metrics)
+	checks := make([]criterionCheck, 0, len(bbc.CustomRules))
+	for _, rule := range bbc.CustomRules {
+		check := criterionCheck{
+			name:          customRulePrefix + rule.Name,
+			categorical:   true,
+			failureReason: fmt.Sprintf("Custom rule %q not met", rule.Name),
+		}
+
+		expression, err := rules.Parse(rule.Expression, RuleFields)
+		if err != nil {
+			check.detail = fmt.Sprintf("%s: invalid rule (%v)", rule.Expression, err)
+			checks = append(checks, check)
+			continue
+		}
+
+		switch expression.Evaluate(values) {
+		case rules.True:
+			check.met = true
+			check.detail = describeRule(expression, values)
+		case rules.False:
+			check.detail = describeRule(expression, values)
+		default:
+			var missing []string
+			for _, field := range expression.Fields() {
+				if values[field] == nil {
+					missing = append(missing, field)
+				}
+			}
+			if len(missing) == 0 {
+				check.detail = describeRule(expression, values) + ": could not be evaluated"
+			}
+			check.missing = strings.Join(missing, ", ")
+		}
+		checks = append(checks, check)
+	}
+	return checks
+}
+
+// describeRule renders a rule with the values it was evaluated against
+func describeRule(expression *rules.Expression, values rules.Values) string {
+	var parts []string
+	for _, field := range expression.Fields() {
+		if value := values[field]; value != nil {
+			parts = append(parts, fmt.Sprintf("%s = %s", field, formatAmount(*value)))
+		}
+	}
+	if len(parts) == 0 {
+		return expression.String()
+	}
+	return fmt.Sprintf("%s (%s)", expression.String(), strings.Join(parts, ", "))
+}
+
+// positive returns a pointer to value, or nil when it is not positive
+func positive(value float64) *float64 {
+	if value <= 0 {
+		return nil
+	}
+	return &value
+}
+
+// intToFloat converts an optional integer field
+func intToFloat(value *int) *float64 {
+	if value == nil {
+		return nil
+	}
+	converted := float64(*value)
+	return &converted
+}
@@ -1,28 +1,50 @@
 package models
 
 import (
+	"html"
+	"regexp"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+
+	"rental-property-mgmt/pkg/markdown"
 )
 
-// Comment represents user collaboration and notes on properties
+// DeletedCommentPlaceholder replaces the content of a deleted comment
+const DeletedCommentPlaceholder = "[deleted]"
+
+// Comment represents user collaboration and notes on properties.
+// Comments form threads: ThreadID is the top-level comment's ID (its own ID for
+// top-level comments) and Depth counts the replies above it, starting at 0.
+// Deleting a comment only blanks it out (DeletedAt is set and the content
+// becomes a placeholder) so its replies stay in place.
 type Comment struct {
-	ID         uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	ID         uuid.UUID  `json:"id" gorm:"type:uuid;primary_key"`
 	PropertyID uuid.UUID  `json:"property_id" gorm:"type:uuid;not null;index"`
 	UserID     uuid.UUID  `json:"user_id" gorm:"type:uuid;not null;index"`
 	Content    string     `json:"content" gorm:"type:text;not null;check:LENGTH(TRIM(content)) > 0 AND LENGTH(content) <= 2000" validate:"required,max=2000"`
 	ParentID   *uuid.UUID `json:"parent_id" gorm:"type:uuid;index"`
+	ThreadID   uuid.UUID  `json:"thread_id" gorm:"type:uuid;not null;index"`
+	Depth      int        `json:"depth" gorm:"not null;default:0;check:depth >= 0"`
 	CreatedAt  time.Time  `json:"created_at" gorm:"autoCreateTime;index"`
 	UpdatedAt  time.Time  `json:"updated_at" gorm:"autoUpdateTime"`
+	DeletedAt  *time.Time `json:"deleted_at,omitempty" gorm:"index"`
+
+	// Computed when serving comment trees
+	UserName      string            `json:"user_name,omitempty" gorm:"-"`
+	ReplyCount    int               `json:"reply_count" gorm:"-"`
+	ContentHTML   string            `json:"content_html" gorm:"-"`
+	PropertyCards []PropertyCard    `json:"property_cards" gorm:"-"`
+	Reactions     []ReactionSummary `json:"reactions" gorm:"-"`
 
 	// Relationships
-	Property Property  `json:"property,omitempty" gorm:"foreignKey:PropertyID;constraint:OnDelete:CASCADE"`
-	User     User      `json:"user,omitempty" gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+	Property *Property `json:"property,omitempty" gorm:"foreignKey:PropertyID;constraint:OnDelete:CASCADE"`
+	User     *User     `json:"-" gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
 	Parent   *Comment  `json:"parent,omitempty" gorm:"foreignKey:ParentID;constraint:OnDelete:CASCADE"`
-	Replies  []Comment `json:"replies,omitempty" gorm:"foreignKey:ParentID;constraint:OnDelete:CASCADE"`
+	Replies  []Comment `json:"replies" gorm:"foreignKey:ParentID;constraint:OnDelete:CASCADE"`
 }
 
 // BeforeCreate hook to generate UUID and validate content
@@ -30,6 +52,9 @@ func (c *Comment) BeforeCreate(tx *gorm.DB) (err error) {
 	if c.ID == uuid.Nil {
 		c.ID = uuid.New()
 	}
+	if c.ThreadID == uuid.Nil {
+		c.ThreadID = c.ID
+	}
 
 	// Validate content
 	c.Content = strings.TrimSpace(c.Content)
@@ -56,6 +81,11 @@ func (Comment) TableName() string {
 	return "comments"
 }
 
+// IsDeleted returns true if the comment has been deleted
+func (c *Comment) IsDeleted() bool {
+	return c.DeletedAt != nil
+}
+
 // IsReply returns true if this comment is a reply to another comment
 func (c *Comment) IsReply() bool {
 	return c.ParentID != nil
@@ -66,10 +96,135 @@ func (c *Comment) IsTopLevel() bool {
 	return c.ParentID == nil
 }
 
-// GetContentPreview returns a truncated version of the content for previews
+// ReplyTo places the comment in the thread of its parent
+func (c *Comment) ReplyTo(parent *Comment) {
+	c.PropertyID = parent.PropertyID
+	c.ParentID = &parent.ID
+	c.ThreadID = parent.ThreadID
+	c.Depth = parent.Depth + 1
+}
+
+// BuildCommentTrees nests comments under their parents, oldest first at every
+// level, and sets each comment's direct reply count. Replies deeper than
+// maxDepth are counted but left out of the tree. Comments whose parent is not
+// in the list become roots.
+func BuildCommentTrees(comments []Comment, maxDepth int) []Comment {
+	byParent := make(map[uuid.UUID][]int)
+	present := make(map[uuid.UUID]bool, len(comments))
+	for _, comment := range comments {
+		present[comment.ID] = true
+	}
+
+	var roots []int
+	for i, comment := range comments {
+		if comment.ParentID != nil && present[*comment.ParentID] {
+			byParent[*comment.ParentID] = append(byParent[*comment.ParentID], i)
+		} else {
+			roots = append(roots, i)
+		}
+	}
+
+	byAge := func(indexes []int) {
+		sort.SliceStable(indexes, func(a, b int) bool {
+			return comments[indexes[a]].CreatedAt.Before(comments[indexes[b]].CreatedAt)
+		})
+	}
+
+	var build func(index, level int) Comment
+	build = func(index, level int) Comment {
+		comment := comments[index]
+		children := byParent[comment.ID]
+		comment.ReplyCount = len(children)
+		comment.Replies = []Comment{}
+		if level < maxDepth {
+			byAge(children)
+			for _, child := range children {
+				comment.Replies = append(comment.Replies, build(child, level+1))
+			}
+		}
+		return comment
+	}
+
+	byAge(roots)
+	trees := make([]Comment, 0, len(roots))
+	for _, root := range roots {
+		trees = append(trees, build(root, 0))
+	}
+	return trees
+}
+
+// GetContentPreview returns the content without Markdown markup, cut to at
+// most maxLength characters for previews
 func (c *Comment) GetContentPreview(maxLength int) string {
-	if len(c.Content) <= maxLength {
-		return c.Content
+	return markdown.Preview(c.Content, maxLength)
+}
+
+// RenderContent fills in ContentHTML from the Markdown content. Property cards
+// are left for the caller to load from LinkedPropertyIDs.
+func (c *Comment) RenderContent() {
+	c.PropertyCards = []PropertyCard{}
+	if c.IsDeleted() {
+		c.ContentHTML = "<p>" + html.EscapeString(DeletedCommentPlaceholder) + "</p>"
+		return
+	}
+	c.ContentHTML = markdown.ToHTML(c.Content)
+}
+
+// propertyLinkPattern matches a link to a property page or API resource
+var propertyLinkPattern = regexp.MustCompile(`(?i)/properties/([0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12})/?(?:[?#]|$)`)
+
+// LinkedPropertyIDs returns the distinct properties the content links to, in
+// order of first appearance. Any link whose path ends in /properties/<id>
+// counts, so both app and API URLs expand into property cards.
+func (c *Comment) LinkedPropertyIDs() []uuid.UUID {
+	if c.IsDeleted() {
+		return nil
+	}
+
+	var ids []uuid.UUID
+	seen := make(map[uuid.UUID]bool)
+	for _, link := range markdown.Links(c.Content) {
+		match := propertyLinkPattern.FindStringSubmatch(link)
+		if match == nil {
+			continue
+		}
+		id, err := uuid.Parse(match[1])
+		if err != nil || seen[id] {
+			continue
+		}
+		seen[id] = true
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// PropertyCard is the summary of a linked property shown under a comment
+type PropertyCard struct {
+	ID               uuid.UUID `json:"id"`
+	Address          string    `json:"address"`
+	City             *string   `json:"city"`
+	State            *string   `json:"state"`
+	PropertyType     *string   `json:"property_type"`
+	PurchasePrice    float64   `json:"purchase_price"`
+	IntendedRent     *float64  `json:"intended_rent"`
+	CapRate          *float64  `json:"cap_rate"`
+	CashOnCashReturn *float64  `json:"cash_on_cash_return"`
+}
+
+// NewPropertyCard summarizes a property, with its metrics when loaded
+func NewPropertyCard(property *Property) PropertyCard {
+	card := PropertyCard{
+		ID:            property.ID,
+		Address:       property.Address,
+		City:          property.City,
+		State:         property.State,
+		PropertyType:  property.PropertyType,
+		PurchasePrice: property.PurchasePrice,
+		IntendedRent:  property.IntendedRent,
+	}
+	if property.FinancialMetrics != nil {
+		card.CapRate = property.FinancialMetrics.CapRate
+		card.CashOnCashReturn = property.FinancialMetrics.CashOnCashReturn
 	}
-	return c.Content[:maxLength] + "..."
+	return card
 }
@@ -0,0 +1,47 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// CommentRevision is a prior version of a comment's content, saved whenever the
+// comment is edited or deleted
+type CommentRevision struct {
+	ID        uuid.UUID `json:"id" gorm:"type:uuid;primary_key"`
+	CommentID uuid.UUID `json:"comment_id" gorm:"type:uuid;not null;index"`
+	Content   string    `json:"content" gorm:"type:text;not null"`
+	WrittenAt time.Time `json:"written_at" gorm:"not null"`
+	CreatedAt time.Time `json:"replaced_at" gorm:"autoCreateTime"`
+
+	// Relationships
+	Comment *Comment `json:"-" gorm:"foreignKey:CommentID;constraint:OnDelete:CASCADE"`
+}
+
+// BeforeCreate hook to generate UUID if not provided
+func (cr *CommentRevision) BeforeCreate(tx *gorm.DB) (err error) {
+	if cr.ID == uuid.Nil {
+		cr.ID = uuid.New()
+	}
+	return
+}
+
+// TableName specifies the table name for GORM
+func (CommentRevision) TableName() string {
+	return "comment_revisions"
+}
+
+// NewCommentRevision captures the comment's current content before it changes
+func NewCommentRevision(comment *Comment) *CommentRevision {
+	writtenAt := comment.UpdatedAt
+	if writtenAt.IsZero() {
+		writtenAt = comment.CreatedAt
+	}
+	return &CommentRevision{
+		CommentID: comment.ID,
+		Content:   comment.Content,
+		WrittenAt: writtenAt,
+	}
+}
@@ -0,0 +1,255 @@
+package models
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ValuationSourceComps is the source of valuations derived from a property's
+// comparable sales
+const ValuationSourceComps = "Comps"
+
+// Comp adjustment features, in the order they are applied
+const (
+	CompFeatureTime      = "time"
+	CompFeatureSize      = "size"
+	CompFeatureAge       = "age"
+	CompFeatureBedrooms  = "bedrooms"
+	CompFeatureBathrooms = "bathrooms"
+)
+
+// Comp is a comparable sale recorded against a property
+type Comp struct {
+	ID               uuid.UUID `json:"id" gorm:"type:uuid;primary_key"`
+	PropertyID       uuid.UUID `json:"property_id" gorm:"type:uuid;not null;index"`
+	UserID           uuid.UUID `json:"user_id" gorm:"type:uuid;not null;index"`
+	Address          string    `json:"address" gorm:"not null;size:255" validate:"required,max=255"`
+	SalePrice        float64   `json:"sale_price" gorm:"type:decimal(12,2);not null;check:sale_price > 0" validate:"required,gt=0"`
+	SaleDate         time.Time `json:"sale_date" gorm:"type:date;not null"`
+	BuildingAreaSqft *int      `json:"building_area_sqft" gorm:"check:building_area_sqft > 0"`
+	Bedrooms         *int      `json:"bedrooms" gorm:"check:bedrooms >= 0"`
+	Bathrooms        *float64  `json:"bathrooms" gorm:"type:decimal(3,1);check:bathrooms >= 0"`
+	YearBuilt        *int      `json:"year_built"`
+	DistanceMiles    *float64  `json:"distance_miles" gorm:"type:decimal(6,2);check:distance_miles >= 0"`
+	CreatedAt        time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt        time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+
+	// Relationships
+	Property *Property `json:"-" gorm:"foreignKey:PropertyID;constraint:OnDelete:CASCADE"`
+	User     *User     `json:"-" gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+}
+
+// BeforeCreate hook to generate UUID if not provided
+func (c *Comp) BeforeCreate(tx *gorm.DB) (err error) {
+	if c.ID == uuid.Nil {
+		c.ID = uuid.New()
+	}
+	return
+}
+
+// TableName specifies the table name for GORM
+func (Comp) TableName() string {
+	return "comps"
+}
+
+// CompAdjustments configures how comps are adjusted toward the subject
+// property. Each comp's sale price moves by the subject's difference from the
+// comp, so a subject with an extra bedroom adjusts every smaller comp up.
+type CompAdjustments struct {
+	// PricePerSqft prices each square foot of size difference; when unset
+	// each comp's own sale price per square foot is used
+	PricePerSqft *float64 `json:"price_per_sqft" validate:"omitempty,gt=0"`
+	// PerYearBuilt is added for each year the subject is newer than the comp
+	PerYearBuilt float64 `json:"per_year_built" validate:"gte=0"`
+	PerBedroom   float64 `json:"per_bedroom" validate:"gte=0"`
+	PerBathroom  float64 `json:"per_bathroom" validate:"gte=0"`
+	// AnnualAppreciationPercent brings sale prices forward to the valuation
+	// date, compounded; negative in a falling market
+	AnnualAppreciationPercent float64 `json:"annual_appreciation_percent" validate:"gte=-50,lte=50"`
+}
+
+// DefaultCompAdjustments returns the adjustments used unless overridden
+func DefaultCompAdjustments() CompAdjustments {
+	return CompAdjustments{
+		PerYearBuilt:              500,
+		PerBedroom:                5000,
+		PerBathroom:               7500,
+		AnnualAppreciationPercent: 3,
+	}
+}
+
+// CompAdjustment is one feature's adjustment to a comp's sale price. A feature
+// that cannot be compared has no amount and says why.
+type CompAdjustment struct {
+	Feature string  `json:"feature"`
+	Amount  float64 `json:"amount"`
+	Note    string  `json:"note,omitempty"`
+}
+
+// AdjustedComp is a comp with its adjustments toward the subject
+type AdjustedComp struct {
+	Comp          Comp             `json:"comp"`
+	Adjustments   []CompAdjustment `json:"adjustments"`
+	AdjustedValue float64          `json:"adjusted_value"`
+	// NetAdjustmentPercent and GrossAdjustmentPercent relate the sum and the
+	// sum of absolute adjustments to the sale price; a high gross adjustment
+	// marks a poor comparable
+	NetAdjustmentPercent   float64 `json:"net_adjustment_percent"`
+	GrossAdjustmentPercent float64 `json:"gross_adjustment_percent"`
+}
+
+// CompValuation is the adjusted value range a property's comps support. The
+// point value is the median adjusted value.
+type CompValuation struct {
+	PropertyID  uuid.UUID       `json:"property_id"`
+	AsOf        time.Time       `json:"as_of"`
+	Adjustments CompAdjustments `json:"adjustments"`
+	Comps       []AdjustedComp  `json:"comps"`
+	Count       int             `json:"count"`
+	Low         float64         `json:"low"`
+	High        float64         `json:"high"`
+	Mean        float64         `json:"mean"`
+	Median      float64         `json:"median"`
+	// Valuation is the stored market value, when the result was saved
+	Valuation *PropertyValuation `json:"valuation,omitempty"`
+}
+
+// AdjustComps adjusts every comp toward the subject property as of a date and
+// summarizes the adjusted values. Comps come back in the order given.
+func AdjustComps(subject *Property, comps []Comp, settings CompAdjustments, asOf time.Time) *CompValuation {
+	valuation := &CompValuation{
+		PropertyID:  subject.ID,
+		AsOf:        asOf,
+		Adjustments: settings,
+		Comps:       make([]AdjustedComp, 0, len(comps)),
+		Count:       len(comps),
+	}
+	if len(comps) == 0 {
+		return valuation
+	}
+
+	values := make([]float64, 0, len(comps))
+	for _, comp := range comps {
+		adjusted := AdjustComp(subject, comp, settings, asOf)
+		valuation.Comps = append(valuation.Comps, adjusted)
+		values = append(values, adjusted.AdjustedValue)
+	}
+
+	valuation.Low, valuation.High = minMax(values)
+	valuation.Mean = roundCents(mean(values))
+	valuation.Median = roundCents(median(values))
+	return valuation
+}
+
+// AdjustComp applies the adjustments to one comp
+func AdjustComp(subject *Property, comp Comp, settings CompAdjustments, asOf time.Time) AdjustedComp {
+	adjusted := AdjustedComp{Comp: comp}
+	add := func(feature string, amount float64, note string) {
+		adjusted.Adjustments = append(adjusted.Adjustments, CompAdjustment{
+			Feature: feature,
+			Amount:  roundCents(amount),
+			Note:    note,
+		})
+	}
+
+	// Time: compound the market's appreciation from the sale to the valuation date
+	years := asOf.Sub(comp.SaleDate).Hours() / 24 / 365.25
+	if years < 0 {
+		years = 0
+	}
+	add(CompFeatureTime, comp.SalePrice*(math.Pow(1+settings.AnnualAppreciationPercent/100, years)-1), "")
+
+	// Size: price the square footage difference
+	switch {
+	case subject.BuildingAreaSqft == nil:
+		add(CompFeatureSize, 0, "subject square footage unknown")
+	case comp.BuildingAreaSqft == nil:
+		add(CompFeatureSize, 0, "comp square footage unknown")
+	default:
+		rate := float64(0)
+		if settings.PricePerSqft != nil {
+			rate = *settings.PricePerSqft
+		} else {
+			rate = comp.SalePrice / float64(*comp.BuildingAreaSqft)
+		}
+		add(CompFeatureSize, float64(*subject.BuildingAreaSqft-*comp.BuildingAreaSqft)*rate, "")
+	}
+
+	// Age: credit each year the subject is newer
+	switch {
+	case subject.YearBuilt == nil:
+		add(CompFeatureAge, 0, "subject year built unknown")
+	case comp.YearBuilt == nil:
+		add(CompFeatureAge, 0, "comp year built unknown")
+	default:
+		add(CompFeatureAge, float64(*subject.YearBuilt-*comp.YearBuilt)*settings.PerYearBuilt, "")
+	}
+
+	// Bedroom and bathroom deltas
+	switch {
+	case subject.Bedrooms == nil:
+		add(CompFeatureBedrooms, 0, "subject bedrooms unknown")
+	case comp.Bedrooms == nil:
+		add(CompFeatureBedrooms, 0, "comp bedrooms unknown")
+	default:
+		add(CompFeatureBedrooms, float64(*subject.Bedrooms-*comp.Bedrooms)*settings.PerBedroom, "")
+	}
+	switch {
+	case subject.Bathrooms == nil:
+		add(CompFeatureBathrooms, 0, "subject bathrooms unknown")
+	case comp.Bathrooms == nil:
+		add(CompFeatureBathrooms, 0, "comp bathrooms unknown")
+	default:
+		add(CompFeatureBathrooms, (*subject.Bathrooms-*comp.Bathrooms)*settings.PerBathroom, "")
+	}
+
+	net, gross := 0.0, 0.0
+	for _, adjustment := range adjusted.Adjustments {
+		net += adjustment.Amount
+		gross += math.Abs(adjustment.Amount)
+	}
+	adjusted.AdjustedValue = roundCents(comp.SalePrice + net)
+	adjusted.NetAdjustmentPercent = math.Round(net/comp.SalePrice*10000) / 100
+	adjusted.GrossAdjustmentPercent = math.Round(gross/comp.SalePrice*10000) / 100
+	return adjusted
+}
+
+// ToValuation turns the comp valuation into a market value for the property:
+// the median adjusted value, bounded by the low and high adjusted values
+func (cv *CompValuation) ToValuation() (*PropertyValuation, error) {
+	if cv.Count == 0 {
+		return nil, fmt.Errorf("no comps to value the property with")
+	}
+	low, high := cv.Low, cv.High
+	return &PropertyValuation{
+		PropertyID:    cv.PropertyID,
+		Source:        ValuationSourceComps,
+		ValuationType: ValuationTypeMarketValue,
+		Value:         cv.Median,
+		ValueLow:      &low,
+		ValueHigh:     &high,
+		ValuationDate: cv.AsOf,
+	}, nil
+}
+
+// SortCompsByDistance orders comps nearest first, unknown distances last,
+// then by most recent sale
+func SortCompsByDistance(comps []Comp) {
+	sort.SliceStable(comps, func(i, j int) bool {
+		a, b := comps[i].DistanceMiles, comps[j].DistanceMiles
+		switch {
+		case a != nil && b != nil && *a != *b:
+			return *a < *b
+		case a != nil && b == nil:
+			return true
+		case a == nil && b != nil:
+			return false
+		}
+		return comps[i].SaleDate.After(comps[j].SaleDate)
+	})
+}
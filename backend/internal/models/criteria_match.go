@@ -0,0 +1,41 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// CriteriaMatch records that a property met a user's active buying criteria.
+// Each criteria/property pair is recorded once, when the property first matches,
+// and reaches the user as a match notification in their inbox.
+type CriteriaMatch struct {
+	ID         uuid.UUID `json:"id" gorm:"type:uuid;primary_key"`
+	CriteriaID uuid.UUID `json:"criteria_id" gorm:"type:uuid;not null;uniqueIndex:idx_criteria_match_pair"`
+	PropertyID uuid.UUID `json:"property_id" gorm:"type:uuid;not null;uniqueIndex:idx_criteria_match_pair;index"`
+	UserID     uuid.UUID `json:"user_id" gorm:"type:uuid;not null;index"`
+	// CriteriaVersion is the version of the criteria the property was scored against
+	CriteriaVersion int       `json:"criteria_version" gorm:"not null;default:1"`
+	Score           float64   `json:"score" gorm:"type:decimal(5,2);not null"`
+	Completeness    float64   `json:"completeness" gorm:"type:decimal(5,2);not null"`
+	CreatedAt       time.Time `json:"created_at" gorm:"autoCreateTime;index"`
+
+	// Relationships
+	Criteria *BuyingBoxCriteria `json:"criteria,omitempty" gorm:"foreignKey:CriteriaID;constraint:OnDelete:CASCADE"`
+	Property *Property          `json:"property,omitempty" gorm:"foreignKey:PropertyID;constraint:OnDelete:CASCADE"`
+	User     *User              `json:"-" gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+}
+
+// BeforeCreate hook to generate UUID if not provided
+func (cm *CriteriaMatch) BeforeCreate(tx *gorm.DB) (err error) {
+	if cm.ID == uuid.Nil {
+		cm.ID = uuid.New()
+	}
+	return
+}
+
+// TableName specifies the table name for GORM
+func (CriteriaMatch) TableName() string {
+	return "criteria_matches"
+}
@@ -0,0 +1,132 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// ErrCriteriaVersionImmutable is returned when code tries to change a stored version
+var ErrCriteriaVersionImmutable = errors.New("criteria versions are immutable")
+
+// CriteriaDefinition is the part of a buying box that affects scoring. Versions
+// snapshot it; activation and naming metadata are not part of it, so renaming
+// criteria does not record a version.
+type CriteriaDefinition struct {
+	MinCapRate              *float64                `json:"min_cap_rate"`
+	MinCashOnCash           *float64                `json:"min_cash_on_cash"`
+	MaxPurchasePrice        *float64                `json:"max_purchase_price"`
+	MinRentToValue          *float64                `json:"min_rent_to_value"`
+	MaxYearBuilt            *int                    `json:"max_year_built"`
+	MinYearBuilt            *int                    `json:"min_year_built"`
+	LocationPreferences     LocationPreferences     `json:"location_preferences"`
+	PropertyTypePreferences PropertyTypePreferences `json:"property_type_preferences"`
+	CustomRules             CustomRules             `json:"custom_rules"`
+	ScoringRules            CriterionRules          `json:"scoring_rules"`
+	MissingDataPolicy       string                  `json:"missing_data_policy"`
+}
+
+// Scan implements the Scanner interface for database/sql
+func (cd *CriteriaDefinition) Scan(value interface{}) error {
+	*cd = CriteriaDefinition{}
+	return scanJSON(value, cd)
+}
+
+// Value implements the Valuer interface for database/sql
+func (cd CriteriaDefinition) Value() (driver.Value, error) {
+	return jsonValue(cd)
+}
+
+// GormDBDataType implements schema.GormDBDataTypeInterface
+func (CriteriaDefinition) GormDBDataType(db *gorm.DB, field *schema.Field) string {
+	return jsonDataType(db)
+}
+
+// Definition returns the scoring-relevant fields of the criteria, with empty
+// rule collections normalized so nil and empty compare equal
+func (bbc *BuyingBoxCriteria) Definition() CriteriaDefinition {
+	customRules := bbc.CustomRules
+	if customRules == nil {
+		customRules = CustomRules{}
+	}
+	scoringRules := bbc.ScoringRules
+	if scoringRules == nil {
+		scoringRules = CriterionRules{}
+	}
+
+	return CriteriaDefinition{
+		MinCapRate:              bbc.MinCapRate,
+		MinCashOnCash:           bbc.MinCashOnCash,
+		MaxPurchasePrice:        bbc.MaxPurchasePrice,
+		MinRentToValue:          bbc.MinRentToValue,
+		MaxYearBuilt:            bbc.MaxYearBuilt,
+		MinYearBuilt:            bbc.MinYearBuilt,
+		LocationPreferences:     bbc.LocationPreferences,
+		PropertyTypePreferences: bbc.PropertyTypePreferences,
+		CustomRules:             customRules,
+		ScoringRules:            scoringRules,
+		MissingDataPolicy:       bbc.GetMissingDataPolicy(),
+	}
+}
+
+// SameDefinition reports whether two criteria would score properties identically
+func (bbc *BuyingBoxCriteria) SameDefinition(other *BuyingBoxCriteria) bool {
+	a, errA := json.Marshal(bbc.Definition())
+	b, errB := json.Marshal(other.Definition())
+	if errA != nil || errB != nil {
+		return false
+	}
+	var decodedA, decodedB interface{}
+	if json.Unmarshal(a, &decodedA) != nil || json.Unmarshal(b, &decodedB) != nil {
+		return false
+	}
+	return reflect.DeepEqual(decodedA, decodedB)
+}
+
+// BuyingBoxCriteriaVersion is an immutable snapshot of a criteria definition.
+// Version 1 is recorded on create and each edit to the definition adds the next.
+type BuyingBoxCriteriaVersion struct {
+	ID         uuid.UUID          `json:"id" gorm:"type:uuid;primary_key"`
+	CriteriaID uuid.UUID          `json:"criteria_id" gorm:"type:uuid;not null;uniqueIndex:idx_criteria_version"`
+	Version    int                `json:"version" gorm:"not null;uniqueIndex:idx_criteria_version"`
+	Definition CriteriaDefinition `json:"definition" gorm:"not null"`
+	CreatedBy  uuid.UUID          `json:"created_by" gorm:"type:uuid;not null"`
+	CreatedAt  time.Time          `json:"created_at" gorm:"autoCreateTime"`
+
+	// Relationships
+	Criteria *BuyingBoxCriteria `json:"-" gorm:"foreignKey:CriteriaID;constraint:OnDelete:CASCADE"`
+}
+
+// BeforeCreate hook to generate UUID if not provided
+func (v *BuyingBoxCriteriaVersion) BeforeCreate(tx *gorm.DB) (err error) {
+	if v.ID == uuid.Nil {
+		v.ID = uuid.New()
+	}
+	return
+}
+
+// BeforeUpdate hook keeps versions immutable
+func (v *BuyingBoxCriteriaVersion) BeforeUpdate(tx *gorm.DB) (err error) {
+	return ErrCriteriaVersionImmutable
+}
+
+// TableName specifies the table name for GORM
+func (BuyingBoxCriteriaVersion) TableName() string {
+	return "buying_box_criteria_versions"
+}
+
+// NewCriteriaVersion snapshots the criteria's current definition
+func NewCriteriaVersion(criteria *BuyingBoxCriteria, createdBy uuid.UUID) *BuyingBoxCriteriaVersion {
+	return &BuyingBoxCriteriaVersion{
+		CriteriaID: criteria.ID,
+		Version:    criteria.Version,
+		Definition: criteria.Definition(),
+		CreatedBy:  createdBy,
+	}
+}
@@ -9,7 +9,7 @@ import (
 
 // FinancialMetrics represents calculated investment metrics for each property
 type FinancialMetrics struct {
-	ID                     uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	ID                     uuid.UUID `json:"id" gorm:"type:uuid;primary_key"`
 	PropertyID             uuid.UUID `json:"property_id" gorm:"type:uuid;unique;not null;index"`
 	MonthlyMortgagePayment *float64  `json:"monthly_mortgage_payment" gorm:"type:decimal(10,2)"`
 	NetOperatingIncome     *float64  `json:"net_operating_income" gorm:"type:decimal(10,2)"`
@@ -21,10 +21,22 @@ type FinancialMetrics struct {
 	CalculatedAt           time.Time `json:"calculated_at" gorm:"autoCreateTime"`
 	IsCurrent              bool      `json:"is_current" gorm:"default:true"`
 
+	// Computed when serving metrics: which rent the metrics assume
+	RentBasis   string   `json:"rent_basis,omitempty" gorm:"-"`
+	MonthlyRent *float64 `json:"monthly_rent,omitempty" gorm:"-"`
+
 	// Relationships
 	Property Property `json:"property,omitempty" gorm:"foreignKey:PropertyID;constraint:OnDelete:CASCADE"`
 }
 
+// Rent bases for metrics
+const (
+	// RentBasisIntended uses the property's IntendedRent
+	RentBasisIntended = "intended"
+	// RentBasisMarket uses the consensus of third-party rental estimates
+	RentBasisMarket = "market"
+)
+
 // BeforeCreate hook to generate UUID if not provided
 func (fm *FinancialMetrics) BeforeCreate(tx *gorm.DB) (err error) {
 	if fm.ID == uuid.Nil {
@@ -0,0 +1,99 @@
+package models
+
+import (
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Notification types
+const (
+	NotificationMention = "mention"
+	NotificationReply   = "reply"
+	NotificationMatch   = "match"
+)
+
+// CommentMention records a user @mentioned in a comment
+type CommentMention struct {
+	ID        uuid.UUID `json:"id" gorm:"type:uuid;primary_key"`
+	CommentID uuid.UUID `json:"comment_id" gorm:"type:uuid;not null;uniqueIndex:idx_comment_mention"`
+	UserID    uuid.UUID `json:"user_id" gorm:"type:uuid;not null;uniqueIndex:idx_comment_mention;index"`
+	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
+
+	// Relationships
+	Comment *Comment `json:"-" gorm:"foreignKey:CommentID;constraint:OnDelete:CASCADE"`
+	User    *User    `json:"-" gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+}
+
+// BeforeCreate hook to generate UUID if not provided
+func (cm *CommentMention) BeforeCreate(tx *gorm.DB) (err error) {
+	if cm.ID == uuid.Nil {
+		cm.ID = uuid.New()
+	}
+	return
+}
+
+// TableName specifies the table name for GORM
+func (CommentMention) TableName() string {
+	return "comment_mentions"
+}
+
+// Notification is an entry in a user's in-app inbox: a comment mention or
+// reply, with its comment and actor, or a buying-box match, with its match
+type Notification struct {
+	ID         uuid.UUID  `json:"id" gorm:"type:uuid;primary_key"`
+	UserID     uuid.UUID  `json:"user_id" gorm:"type:uuid;not null;index"`
+	ActorID    *uuid.UUID `json:"actor_id,omitempty" gorm:"type:uuid"`
+	Type       string     `json:"type" gorm:"size:20;not null;check:type IN ('mention', 'reply', 'match')"`
+	CommentID  *uuid.UUID `json:"comment_id,omitempty" gorm:"type:uuid;index"`
+	MatchID    *uuid.UUID `json:"match_id,omitempty" gorm:"type:uuid;index"`
+	PropertyID uuid.UUID  `json:"property_id" gorm:"type:uuid;not null"`
+	ReadAt     *time.Time `json:"read_at"`
+	CreatedAt  time.Time  `json:"created_at" gorm:"autoCreateTime;index"`
+
+	// Computed when serving the inbox
+	ActorName string `json:"actor_name,omitempty" gorm:"-"`
+
+	// Relationships
+	User    *User          `json:"-" gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+	Actor   *User          `json:"-" gorm:"foreignKey:ActorID;constraint:OnDelete:CASCADE"`
+	Comment *Comment       `json:"comment,omitempty" gorm:"foreignKey:CommentID;constraint:OnDelete:CASCADE"`
+	Match   *CriteriaMatch `json:"match,omitempty" gorm:"foreignKey:MatchID;constraint:OnDelete:CASCADE"`
+}
+
+// BeforeCreate hook to generate UUID if not provided
+func (n *Notification) BeforeCreate(tx *gorm.DB) (err error) {
+	if n.ID == uuid.Nil {
+		n.ID = uuid.New()
+	}
+	return
+}
+
+// TableName specifies the table name for GORM
+func (Notification) TableName() string {
+	return "notifications"
+}
+
+// mentionPattern matches @handle or @full@email.address not preceded by a word
+// character, so plain email addresses in text are not mentions
+var mentionPattern = regexp.MustCompile(`(?:^|[^\w@.])@([A-Za-z0-9._%+-]+(?:@[A-Za-z0-9-]+(?:\.[A-Za-z0-9-]+)+)?)`)
+
+// ParseMentions returns the distinct lowercase handles @mentioned in content, in
+// order of first appearance. A handle is a full email address or its local part.
+func ParseMentions(content string) []string {
+	var handles []string
+	seen := make(map[string]bool)
+	for _, match := range mentionPattern.FindAllStringSubmatch(content, -1) {
+		// Sentence punctuation is not part of the handle
+		handle := strings.ToLower(strings.TrimRight(match[1], "."))
+		if handle == "" || seen[handle] {
+			continue
+		}
+		seen[handle] = true
+		handles = append(handles, handle)
+	}
+	return handles
+}
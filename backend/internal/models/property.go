@@ -3,13 +3,16 @@ package models
 import (
 	"database/sql/driver"
 	"encoding/json"
+	"regexp"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
 )
 
-// JSONB is a custom type for PostgreSQL JSONB fields
+// JSONB is a JSON object column: jsonb on PostgreSQL, json text on SQLite
 type JSONB map[string]interface{}
 
 // Scan implements the Scanner interface for database/sql
@@ -35,31 +38,58 @@ func (j JSONB) Value() (driver.Value, error) {
 	if j == nil {
 		return "{}", nil
 	}
-	return json.Marshal(j)
+	return jsonValue(j)
 }
 
+// GormDBDataType implements schema.GormDBDataTypeInterface
+func (JSONB) GormDBDataType(db *gorm.DB, field *schema.Field) string {
+	return jsonDataType(db)
+}
+
+// Property types
+const (
+	PropertyTypeSFR         = "sfr"
+	PropertyTypeDuplex      = "duplex"
+	PropertyTypeTriplex     = "triplex"
+	PropertyTypeFourplex    = "fourplex"
+	PropertyTypeMultifamily = "multifamily"
+	PropertyTypeCondo       = "condo"
+	PropertyTypeTownhouse   = "townhouse"
+)
+
 // Property represents a rental property with all investment-related data
 type Property struct {
-	ID                   uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
-	UserID               uuid.UUID `json:"user_id" gorm:"type:uuid;not null;index"`
-	Address              string    `json:"address" gorm:"not null;size:255" validate:"required,max=255"`
-	YearBuilt            *int      `json:"year_built" gorm:"check:year_built >= 1800 AND year_built <= EXTRACT(YEAR FROM NOW()) + 1"`
-	LandAreaSqft         *int      `json:"land_area_sqft" gorm:"check:land_area_sqft > 0"`
-	BuildingAreaSqft     *int      `json:"building_area_sqft" gorm:"check:building_area_sqft > 0"`
-	PurchasePrice        float64   `json:"purchase_price" gorm:"type:decimal(12,2);not null" validate:"required,gt=0"`
-	IntendedRent         *float64  `json:"intended_rent" gorm:"type:decimal(10,2)"`
-	OperatingExpenses    JSONB     `json:"operating_expenses" gorm:"type:jsonb;default:'{}'"`
-	FinancingTerms       JSONB     `json:"financing_terms" gorm:"type:jsonb;default:'{}'"`
-	OperatingAssumptions JSONB     `json:"operating_assumptions" gorm:"type:jsonb;default:'{}'"`
-	LocalContext         JSONB     `json:"local_context" gorm:"type:jsonb;default:'{}'"`
-	CreatedAt            time.Time `json:"created_at" gorm:"autoCreateTime"`
-	UpdatedAt            time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+	ID                   uuid.UUID  `json:"id" gorm:"type:uuid;primary_key"`
+	UserID               uuid.UUID  `json:"user_id" gorm:"type:uuid;not null;index"`
+	Address              string     `json:"address" gorm:"not null;size:255" validate:"required,max=255"`
+	City                 *string    `json:"city" gorm:"size:100;index"`
+	State                *string    `json:"state" gorm:"size:2;index"`
+	ZipCode              *string    `json:"zip_code" gorm:"size:10;index"`
+	Latitude             *float64   `json:"latitude" gorm:"type:decimal(9,6);check:latitude IS NULL OR (latitude >= -90 AND latitude <= 90)"`
+	Longitude            *float64   `json:"longitude" gorm:"type:decimal(9,6);check:longitude IS NULL OR (longitude >= -180 AND longitude <= 180)"`
+	PropertyType         *string    `json:"property_type" gorm:"size:20;index;check:property_type IS NULL OR property_type IN ('sfr', 'duplex', 'triplex', 'fourplex', 'multifamily', 'condo', 'townhouse')" validate:"omitempty,oneof=sfr duplex triplex fourplex multifamily condo townhouse"`
+	YearBuilt            *int       `json:"year_built" gorm:"check:year_built >= 1800"`
+	LandAreaSqft         *int       `json:"land_area_sqft" gorm:"check:land_area_sqft > 0"`
+	BuildingAreaSqft     *int       `json:"building_area_sqft" gorm:"check:building_area_sqft > 0"`
+	Bedrooms             *int       `json:"bedrooms" gorm:"check:bedrooms >= 0"`
+	Bathrooms            *float64   `json:"bathrooms" gorm:"type:decimal(3,1);check:bathrooms >= 0"`
+	PurchasePrice        float64    `json:"purchase_price" gorm:"type:decimal(12,2);not null" validate:"required,gt=0"`
+	IntendedRent         *float64   `json:"intended_rent" gorm:"type:decimal(10,2)"`
+	OperatingExpenses    JSONB      `json:"operating_expenses" gorm:"default:'{}'"`
+	FinancingTerms       JSONB      `json:"financing_terms" gorm:"default:'{}'"`
+	OperatingAssumptions JSONB      `json:"operating_assumptions" gorm:"default:'{}'"`
+	LocalContext         JSONB      `json:"local_context" gorm:"default:'{}'"`
+	AssumptionProfileID  *uuid.UUID `json:"assumption_profile_id" gorm:"type:uuid;index"`
+	DefaultedFields      JSONB      `json:"defaulted_fields" gorm:"default:'{}'"`
+	CreatedAt            time.Time  `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt            time.Time  `json:"updated_at" gorm:"autoUpdateTime"`
 
 	// Relationships
-	User             User                `json:"user,omitempty" gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
-	Comments         []Comment           `json:"comments,omitempty" gorm:"foreignKey:PropertyID;constraint:OnDelete:CASCADE"`
-	FinancialMetrics *FinancialMetrics   `json:"financial_metrics,omitempty" gorm:"foreignKey:PropertyID;constraint:OnDelete:CASCADE"`
-	Valuations       []PropertyValuation `json:"valuations,omitempty" gorm:"foreignKey:PropertyID;constraint:OnDelete:CASCADE"`
+	User              User                `json:"user,omitempty" gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+	Comments          []Comment           `json:"comments,omitempty" gorm:"foreignKey:PropertyID;constraint:OnDelete:CASCADE"`
+	FinancialMetrics  *FinancialMetrics   `json:"financial_metrics,omitempty" gorm:"foreignKey:PropertyID;constraint:OnDelete:CASCADE"`
+	Valuations        []PropertyValuation `json:"valuations,omitempty" gorm:"foreignKey:PropertyID;constraint:OnDelete:CASCADE"`
+	AssumptionProfile *AssumptionProfile  `json:"assumption_profile,omitempty" gorm:"foreignKey:AssumptionProfileID;constraint:OnDelete:SET NULL"`
 }
 
 // BeforeCreate hook to generate UUID if not provided
@@ -122,3 +152,101 @@ func (p *Property) GetOperatingAssumption(key string) float64 {
 	}
 	return 0
 }
+
+// addressPattern matches a US address of the form "street, city, ST 12345[-6789]"
+var addressPattern = regexp.MustCompile(`^\s*(.+?),\s*([^,]+?),\s*([A-Za-z]{2})\s+(\d{5})(?:-\d{4})?\s*$`)
+
+// FillAddressComponents parses city, state and ZIP code out of Address for any
+// component that was not provided explicitly
+func (p *Property) FillAddressComponents() {
+	if p.State != nil {
+		state := strings.ToUpper(*p.State)
+		p.State = &state
+	}
+
+	match := addressPattern.FindStringSubmatch(p.Address)
+	if match == nil {
+		return
+	}
+	if p.City == nil {
+		city := strings.TrimSpace(match[2])
+		p.City = &city
+	}
+	if p.State == nil {
+		state := strings.ToUpper(match[3])
+		p.State = &state
+	}
+	if p.ZipCode == nil {
+		zip := match[4]
+		p.ZipCode = &zip
+	}
+}
+
+// GetZipCode returns the property's ZIP code, or "" if unknown
+func (p *Property) GetZipCode() string {
+	if p.ZipCode == nil {
+		return ""
+	}
+	return *p.ZipCode
+}
+
+// HasCoordinates returns true if the property has a geocoded location
+func (p *Property) HasCoordinates() bool {
+	return p.Latitude != nil && p.Longitude != nil
+}
+
+// Metro returns the metro area recorded in the property's local context
+func (p *Property) Metro() string {
+	if p.LocalContext == nil {
+		return ""
+	}
+	if metro, ok := p.LocalContext["metro"].(string); ok {
+		return metro
+	}
+	return ""
+}
+
+// addressAbbreviations maps common street words to their USPS abbreviations
+var addressAbbreviations = map[string]string{
+	"street": "st", "avenue": "ave", "road": "rd", "drive": "dr", "lane": "ln",
+	"boulevard": "blvd", "court": "ct", "place": "pl", "terrace": "ter",
+	"circle": "cir", "parkway": "pkwy", "highway": "hwy", "square": "sq",
+	"north": "n", "south": "s", "east": "e", "west": "w",
+	"apartment": "apt", "suite": "ste", "unit": "unit",
+}
+
+// NormalizeAddress reduces an address to a comparable key: lower case,
+// punctuation dropped, whitespace collapsed, street words abbreviated and any
+// ZIP+4 extension removed, so "12 Oak Street, Springfield, IL 62701-1234" and
+// "12 oak st springfield il 62701" normalize alike
+func NormalizeAddress(address string) string {
+	cleaned := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '-':
+			return r
+		case r >= 'A' && r <= 'Z':
+			return r + ('a' - 'A')
+		default:
+			return ' '
+		}
+	}, address)
+
+	words := strings.Fields(cleaned)
+	for i, word := range words {
+		if abbreviation, ok := addressAbbreviations[word]; ok {
+			words[i] = abbreviation
+		} else if len(word) == 10 && word[5] == '-' && isDigits(word[:5]) && isDigits(word[6:]) {
+			words[i] = word[:5]
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
@@ -7,18 +7,31 @@ import (
 	"gorm.io/gorm"
 )
 
+// Valuation types
+const (
+	ValuationTypeMarketValue    = "market_value"
+	ValuationTypeRentalEstimate = "rental_estimate"
+)
+
+// ValuationTypes lists the valuation types in display order
+var ValuationTypes = []string{ValuationTypeMarketValue, ValuationTypeRentalEstimate}
+
 // PropertyValuation represents third-party valuation data from Zillow, Redfin, etc.
+// Source names a provider in the valuation registry.
 type PropertyValuation struct {
-	ID            uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	ID            uuid.UUID `json:"id" gorm:"type:uuid;primary_key"`
 	PropertyID    uuid.UUID `json:"property_id" gorm:"type:uuid;not null;index"`
-	Source        string    `json:"source" gorm:"not null;size:50;check:source IN ('Zillow', 'Redfin', 'Rentimate')" validate:"required,oneof=Zillow Redfin Rentimate"`
+	Source        string    `json:"source" gorm:"not null;size:50" validate:"required,max=50"`
 	ValuationType string    `json:"valuation_type" gorm:"not null;size:20;check:valuation_type IN ('market_value', 'rental_estimate')" validate:"required,oneof=market_value rental_estimate"`
 	Value         float64   `json:"value" gorm:"type:decimal(12,2);not null;check:value > 0" validate:"required,gt=0"`
+	// ValueLow and ValueHigh bound Value for sources that estimate a range
+	ValueLow      *float64  `json:"value_low,omitempty" gorm:"type:decimal(12,2)"`
+	ValueHigh     *float64  `json:"value_high,omitempty" gorm:"type:decimal(12,2)"`
 	ValuationDate time.Time `json:"valuation_date" gorm:"type:date;not null" validate:"required"`
 	CreatedAt     time.Time `json:"created_at" gorm:"autoCreateTime"`
 
 	// Relationships
-	Property Property `json:"property,omitempty" gorm:"foreignKey:PropertyID;constraint:OnDelete:CASCADE"`
+	Property *Property `json:"property,omitempty" gorm:"foreignKey:PropertyID;constraint:OnDelete:CASCADE"`
 }
 
 // BeforeCreate hook to generate UUID if not provided
@@ -36,10 +49,10 @@ func (PropertyValuation) TableName() string {
 
 // IsMarketValue returns true if this is a market value valuation
 func (pv *PropertyValuation) IsMarketValue() bool {
-	return pv.ValuationType == "market_value"
+	return pv.ValuationType == ValuationTypeMarketValue
 }
 
 // IsRentalEstimate returns true if this is a rental estimate valuation
 func (pv *PropertyValuation) IsRentalEstimate() bool {
-	return pv.ValuationType == "rental_estimate"
+	return pv.ValuationType == ValuationTypeRentalEstimate
 }
@@ -0,0 +1,346 @@
+package models
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// DefaultAnnualRentGrowthPercent brings listed rents forward to the estimate
+// date unless overridden
+const DefaultAnnualRentGrowthPercent = 3.0
+
+// RentComp is a comparable rental listing recorded against a property
+type RentComp struct {
+	ID               uuid.UUID `json:"id" gorm:"type:uuid;primary_key"`
+	PropertyID       uuid.UUID `json:"property_id" gorm:"type:uuid;not null;index"`
+	UserID           uuid.UUID `json:"user_id" gorm:"type:uuid;not null;index"`
+	Address          string    `json:"address" gorm:"not null;size:255" validate:"required,max=255"`
+	MonthlyRent      float64   `json:"monthly_rent" gorm:"type:decimal(10,2);not null;check:monthly_rent > 0" validate:"required,gt=0"`
+	ListingDate      time.Time `json:"listing_date" gorm:"type:date;not null"`
+	BuildingAreaSqft *int      `json:"building_area_sqft" gorm:"check:building_area_sqft > 0"`
+	Bedrooms         *int      `json:"bedrooms" gorm:"check:bedrooms >= 0"`
+	Bathrooms        *float64  `json:"bathrooms" gorm:"type:decimal(3,1);check:bathrooms >= 0"`
+	DistanceMiles    *float64  `json:"distance_miles" gorm:"type:decimal(6,2);check:distance_miles >= 0"`
+	CreatedAt        time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt        time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+
+	// Relationships
+	Property *Property `json:"-" gorm:"foreignKey:PropertyID;constraint:OnDelete:CASCADE"`
+	User     *User     `json:"-" gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+}
+
+// BeforeCreate hook to generate UUID if not provided
+func (rc *RentComp) BeforeCreate(tx *gorm.DB) (err error) {
+	if rc.ID == uuid.Nil {
+		rc.ID = uuid.New()
+	}
+	return
+}
+
+// TableName specifies the table name for GORM
+func (RentComp) TableName() string {
+	return "rent_comps"
+}
+
+// AdjustedRentComp is a rent comp brought forward to the estimate date
+type AdjustedRentComp struct {
+	Comp         RentComp `json:"comp"`
+	AdjustedRent float64  `json:"adjusted_rent"`
+	// AdjustedRentPerSqft is empty when the comp's size is unknown
+	AdjustedRentPerSqft *float64 `json:"adjusted_rent_per_sqft"`
+}
+
+// RentPerSqftEstimate prices the subject's size at the comps' median
+// adjusted rent per square foot
+type RentPerSqftEstimate struct {
+	Comps             int     `json:"comps"`
+	MedianRentPerSqft float64 `json:"median_rent_per_sqft"`
+	Rent              float64 `json:"rent"`
+}
+
+// RentRegression is a least-squares fit of adjusted rent on bedrooms and
+// bathrooms. A feature every comp shares is left out of the fit.
+type RentRegression struct {
+	Observations int      `json:"observations"`
+	Intercept    float64  `json:"intercept"`
+	PerBedroom   *float64 `json:"per_bedroom"`
+	PerBathroom  *float64 `json:"per_bathroom"`
+	RSquared     float64  `json:"r_squared"`
+	Rent         float64  `json:"rent"`
+}
+
+// RentEstimate is the rent a property's rental comps support. The suggested
+// rent averages the per-sqft and regression estimates that could be made.
+type RentEstimate struct {
+	PropertyID              uuid.UUID          `json:"property_id"`
+	AsOf                    time.Time          `json:"as_of"`
+	AnnualRentGrowthPercent float64            `json:"annual_rent_growth_percent"`
+	Comps                   []AdjustedRentComp `json:"comps"`
+	Count                   int                `json:"count"`
+	// MedianRent is the median adjusted rent, on the same footing as the
+	// per-sqft and regression estimates
+	MedianRent    float64              `json:"median_rent"`
+	PerSqft       *RentPerSqftEstimate `json:"per_sqft"`
+	Regression    *RentRegression      `json:"regression"`
+	SuggestedRent *float64             `json:"suggested_rent"`
+	// IntendedRent is compared with the comps' median adjusted rent:
+	// IntendedDeviation is IntendedRent - MedianRent, and the percent relates
+	// it to the median
+	IntendedRent             *float64 `json:"intended_rent"`
+	IntendedDeviation        *float64 `json:"intended_deviation"`
+	IntendedDeviationPercent *float64 `json:"intended_deviation_percent"`
+	// Valuation is the stored rental estimate, when the result was saved
+	Valuation *PropertyValuation `json:"valuation,omitempty"`
+}
+
+// EstimateRent brings each rent comp forward to asOf at the annual growth
+// rate and estimates the subject's rent from them
+func EstimateRent(subject *Property, comps []RentComp, annualGrowthPercent float64, asOf time.Time) *RentEstimate {
+	estimate := &RentEstimate{
+		PropertyID:              subject.ID,
+		AsOf:                    asOf,
+		AnnualRentGrowthPercent: annualGrowthPercent,
+		Comps:                   make([]AdjustedRentComp, 0, len(comps)),
+		Count:                   len(comps),
+		IntendedRent:            subject.IntendedRent,
+	}
+	if len(comps) == 0 {
+		return estimate
+	}
+
+	rents := make([]float64, 0, len(comps))
+	var perSqft []float64
+	for _, comp := range comps {
+		years := math.Max(asOf.Sub(comp.ListingDate).Hours()/24/365.25, 0)
+		adjusted := AdjustedRentComp{
+			Comp:         comp,
+			AdjustedRent: roundCents(comp.MonthlyRent * math.Pow(1+annualGrowthPercent/100, years)),
+		}
+		if comp.BuildingAreaSqft != nil {
+			rate := math.Round(adjusted.AdjustedRent/float64(*comp.BuildingAreaSqft)*10000) / 10000
+			adjusted.AdjustedRentPerSqft = &rate
+			perSqft = append(perSqft, rate)
+		}
+		estimate.Comps = append(estimate.Comps, adjusted)
+		rents = append(rents, adjusted.AdjustedRent)
+	}
+	estimate.MedianRent = roundCents(median(rents))
+
+	if subject.BuildingAreaSqft != nil && len(perSqft) > 0 {
+		rate := median(perSqft)
+		estimate.PerSqft = &RentPerSqftEstimate{
+			Comps:             len(perSqft),
+			MedianRentPerSqft: math.Round(rate*10000) / 10000,
+			Rent:              roundCents(rate * float64(*subject.BuildingAreaSqft)),
+		}
+	}
+	estimate.Regression = regressRent(subject, estimate.Comps)
+
+	var methods []float64
+	if estimate.PerSqft != nil {
+		methods = append(methods, estimate.PerSqft.Rent)
+	}
+	if estimate.Regression != nil {
+		methods = append(methods, estimate.Regression.Rent)
+	}
+	if len(methods) > 0 {
+		suggested := roundCents(mean(methods))
+		estimate.SuggestedRent = &suggested
+	}
+
+	if subject.IntendedRent != nil && estimate.MedianRent > 0 {
+		deviation := roundCents(*subject.IntendedRent - estimate.MedianRent)
+		percent := math.Round(deviation/estimate.MedianRent*10000) / 100
+		estimate.IntendedDeviation = &deviation
+		estimate.IntendedDeviationPercent = &percent
+	}
+	return estimate
+}
+
+// ToValuation turns the suggested rent into a rental estimate for the
+// property, ranging over the methods' estimates when both were made
+func (re *RentEstimate) ToValuation() (*PropertyValuation, error) {
+	if re.SuggestedRent == nil {
+		return nil, fmt.Errorf("rent comps do not support an estimate")
+	}
+
+	valuation := &PropertyValuation{
+		PropertyID:    re.PropertyID,
+		Source:        ValuationSourceComps,
+		ValuationType: ValuationTypeRentalEstimate,
+		Value:         *re.SuggestedRent,
+		ValuationDate: re.AsOf,
+	}
+	if re.PerSqft != nil && re.Regression != nil {
+		low, high := minMax([]float64{re.PerSqft.Rent, re.Regression.Rent})
+		valuation.ValueLow = &low
+		valuation.ValueHigh = &high
+	}
+	return valuation, nil
+}
+
+// regressRent fits adjusted rent on bedrooms and bathrooms over the comps
+// that have both and predicts the subject's rent. It needs the subject's room
+// counts, a feature that varies across comps, and more comps than fitted terms.
+func regressRent(subject *Property, comps []AdjustedRentComp) *RentRegression {
+	if subject.Bedrooms == nil || subject.Bathrooms == nil {
+		return nil
+	}
+
+	var beds, baths, rents []float64
+	for _, comp := range comps {
+		if comp.Comp.Bedrooms == nil || comp.Comp.Bathrooms == nil {
+			continue
+		}
+		beds = append(beds, float64(*comp.Comp.Bedrooms))
+		baths = append(baths, *comp.Comp.Bathrooms)
+		rents = append(rents, comp.AdjustedRent)
+	}
+
+	// Only features that vary can be fitted
+	var columns [][]float64
+	var subjectRow []float64
+	useBeds, useBaths := varies(beds), varies(baths)
+	if useBeds {
+		columns = append(columns, beds)
+		subjectRow = append(subjectRow, float64(*subject.Bedrooms))
+	}
+	if useBaths {
+		columns = append(columns, baths)
+		subjectRow = append(subjectRow, *subject.Bathrooms)
+	}
+	if len(columns) == 0 || len(rents) <= len(columns)+1 {
+		return nil
+	}
+
+	coefficients, ok := leastSquares(columns, rents)
+	if !ok {
+		return nil
+	}
+
+	regression := &RentRegression{
+		Observations: len(rents),
+		Intercept:    roundCents(coefficients[0]),
+	}
+	prediction := coefficients[0]
+	for i, value := range subjectRow {
+		prediction += coefficients[i+1] * value
+	}
+	next := 1
+	if useBeds {
+		regression.PerBedroom = floatPtr(roundCents(coefficients[next]))
+		next++
+	}
+	if useBaths {
+		regression.PerBathroom = floatPtr(roundCents(coefficients[next]))
+	}
+
+	// R² = 1 - residual sum of squares / total sum of squares
+	average := mean(rents)
+	residual, total := 0.0, 0.0
+	for i, rent := range rents {
+		fitted := coefficients[0]
+		for j, column := range columns {
+			fitted += coefficients[j+1] * column[i]
+		}
+		residual += (rent - fitted) * (rent - fitted)
+		total += (rent - average) * (rent - average)
+	}
+	if total > 0 {
+		regression.RSquared = math.Round((1-residual/total)*10000) / 10000
+	}
+
+	regression.Rent = roundCents(math.Max(prediction, 0))
+	return regression
+}
+
+// leastSquares solves the normal equations for y = b0 + b1*x1 + ... and
+// returns [b0, b1, ...], or false when the system is singular
+func leastSquares(columns [][]float64, y []float64) ([]float64, bool) {
+	n := len(columns) + 1
+	row := func(i int) []float64 {
+		values := make([]float64, n)
+		values[0] = 1
+		for j, column := range columns {
+			values[j+1] = column[i]
+		}
+		return values
+	}
+
+	// Augmented matrix [XᵀX | Xᵀy]
+	matrix := make([][]float64, n)
+	for i := range matrix {
+		matrix[i] = make([]float64, n+1)
+	}
+	for i := range y {
+		x := row(i)
+		for a := 0; a < n; a++ {
+			for b := 0; b < n; b++ {
+				matrix[a][b] += x[a] * x[b]
+			}
+			matrix[a][n] += x[a] * y[i]
+		}
+	}
+
+	// Gauss-Jordan elimination with partial pivoting
+	for col := 0; col < n; col++ {
+		pivot := col
+		for r := col + 1; r < n; r++ {
+			if math.Abs(matrix[r][col]) > math.Abs(matrix[pivot][col]) {
+				pivot = r
+			}
+		}
+		if math.Abs(matrix[pivot][col]) < 1e-9 {
+			return nil, false
+		}
+		matrix[col], matrix[pivot] = matrix[pivot], matrix[col]
+
+		for r := 0; r < n; r++ {
+			if r == col {
+				continue
+			}
+			factor := matrix[r][col] / matrix[col][col]
+			for c := col; c <= n; c++ {
+				matrix[r][c] -= factor * matrix[col][c]
+			}
+		}
+	}
+
+	solution := make([]float64, n)
+	for i := range solution {
+		solution[i] = matrix[i][n] / matrix[i][i]
+	}
+	return solution, true
+}
+
+// varies reports whether the values are not all equal
+func varies(values []float64) bool {
+	for i := 1; i < len(values); i++ {
+		if values[i] != values[0] {
+			return true
+		}
+	}
+	return false
+}
+
+// SortRentCompsByDistance orders rent comps nearest first, unknown distances
+// last, then by most recent listing
+func SortRentCompsByDistance(comps []RentComp) {
+	sort.SliceStable(comps, func(i, j int) bool {
+		a, b := comps[i].DistanceMiles, comps[j].DistanceMiles
+		switch {
+		case a != nil && b != nil && *a != *b:
+			return *a < *b
+		case a != nil && b == nil:
+			return true
+		case a == nil && b != nil:
+			return false
+		}
+		return comps[i].ListingDate.After(comps[j].ListingDate)
+	})
+}
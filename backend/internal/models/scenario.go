@@ -0,0 +1,81 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Scenario is a what-if version of a property deal. Any field left nil (or any
+// JSON key left out) falls back to the base property's value.
+type Scenario struct {
+	ID                   uuid.UUID `json:"id" gorm:"type:uuid;primary_key"`
+	PropertyID           uuid.UUID `json:"property_id" gorm:"type:uuid;not null;index"`
+	UserID               uuid.UUID `json:"user_id" gorm:"type:uuid;not null;index"`
+	Name                 string    `json:"name" gorm:"not null;size:100" validate:"required,max=100"`
+	Description          string    `json:"description" gorm:"type:text"`
+	PurchasePrice        *float64  `json:"purchase_price" gorm:"type:decimal(12,2);check:purchase_price IS NULL OR purchase_price > 0"`
+	IntendedRent         *float64  `json:"intended_rent" gorm:"type:decimal(10,2)"`
+	OperatingExpenses    JSONB     `json:"operating_expenses" gorm:"default:'{}'"`
+	FinancingTerms       JSONB     `json:"financing_terms" gorm:"default:'{}'"`
+	OperatingAssumptions JSONB     `json:"operating_assumptions" gorm:"default:'{}'"`
+	CreatedAt            time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt            time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+
+	// Metrics are computed from the merged property on read and never stored
+	Metrics *FinancialMetrics `json:"metrics,omitempty" gorm:"-"`
+
+	// Relationships
+	Property Property `json:"property,omitempty" gorm:"foreignKey:PropertyID;constraint:OnDelete:CASCADE"`
+	User     User     `json:"user,omitempty" gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+}
+
+// BeforeCreate hook to generate UUID if not provided
+func (s *Scenario) BeforeCreate(tx *gorm.DB) (err error) {
+	if s.ID == uuid.Nil {
+		s.ID = uuid.New()
+	}
+	return
+}
+
+// TableName specifies the table name for GORM
+func (Scenario) TableName() string {
+	return "scenarios"
+}
+
+// Apply returns a copy of the base property with this scenario's overrides merged in.
+// The base property is left untouched.
+func (s *Scenario) Apply(base *Property) *Property {
+	merged := *base
+	merged.OperatingExpenses = mergeJSONB(base.OperatingExpenses, s.OperatingExpenses)
+	merged.FinancingTerms = mergeJSONB(base.FinancingTerms, s.FinancingTerms)
+	merged.OperatingAssumptions = mergeJSONB(base.OperatingAssumptions, s.OperatingAssumptions)
+	merged.FinancialMetrics = nil
+
+	if s.PurchasePrice != nil {
+		merged.PurchasePrice = *s.PurchasePrice
+	}
+	if s.IntendedRent != nil {
+		rent := *s.IntendedRent
+		merged.IntendedRent = &rent
+	}
+
+	return &merged
+}
+
+// mergeJSONB returns a new map holding base's keys overlaid with overrides
+func mergeJSONB(base, overrides JSONB) JSONB {
+	if base == nil && overrides == nil {
+		return nil
+	}
+
+	merged := make(JSONB, len(base)+len(overrides))
+	for key, value := range base {
+		merged[key] = value
+	}
+	for key, value := range overrides {
+		merged[key] = value
+	}
+	return merged
+}
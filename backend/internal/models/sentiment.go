@@ -0,0 +1,219 @@
+package models
+
+import (
+	"sort"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Property decisions a team member can vote for
+const (
+	DecisionPass   = "pass"
+	DecisionMaybe  = "maybe"
+	DecisionPursue = "pursue"
+)
+
+// Decisions lists the valid property decisions
+var Decisions = []string{DecisionPass, DecisionMaybe, DecisionPursue}
+
+// IsValidDecision checks if a decision is one of Decisions
+func IsValidDecision(decision string) bool {
+	for _, valid := range Decisions {
+		if decision == valid {
+			return true
+		}
+	}
+	return false
+}
+
+// PropertyVote is a team member's pass/maybe/pursue decision on a property.
+// Each member has at most one vote per property; voting again replaces it.
+type PropertyVote struct {
+	ID         uuid.UUID `json:"id" gorm:"type:uuid;primary_key"`
+	PropertyID uuid.UUID `json:"property_id" gorm:"type:uuid;not null;uniqueIndex:idx_property_vote"`
+	UserID     uuid.UUID `json:"user_id" gorm:"type:uuid;not null;uniqueIndex:idx_property_vote;index"`
+	Decision   string    `json:"decision" gorm:"size:10;not null;check:decision IN ('pass', 'maybe', 'pursue')" validate:"required,oneof=pass maybe pursue"`
+	CreatedAt  time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt  time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+
+	// Computed when listing votes
+	UserName string `json:"user_name,omitempty" gorm:"-"`
+
+	// Relationships
+	Property *Property `json:"-" gorm:"foreignKey:PropertyID;constraint:OnDelete:CASCADE"`
+	User     *User     `json:"-" gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+}
+
+// BeforeCreate hook to generate UUID if not provided
+func (pv *PropertyVote) BeforeCreate(tx *gorm.DB) (err error) {
+	if pv.ID == uuid.Nil {
+		pv.ID = uuid.New()
+	}
+	return
+}
+
+// TableName specifies the table name for GORM
+func (PropertyVote) TableName() string {
+	return "property_votes"
+}
+
+// VoteTally counts a property's votes by decision
+type VoteTally struct {
+	Pass   int `json:"pass"`
+	Maybe  int `json:"maybe"`
+	Pursue int `json:"pursue"`
+	Total  int `json:"total"`
+	// Leading is the decision with the most votes, or nil when there are no
+	// votes or the top decisions are tied
+	Leading *string `json:"leading"`
+	// MyVote is the requesting user's decision, if they voted
+	MyVote *string `json:"my_vote"`
+}
+
+// NewVoteTally counts votes by decision and works out the leading decision
+func NewVoteTally(pass, maybe, pursue int) VoteTally {
+	tally := VoteTally{Pass: pass, Maybe: maybe, Pursue: pursue, Total: pass + maybe + pursue}
+
+	counts := map[string]int{DecisionPass: pass, DecisionMaybe: maybe, DecisionPursue: pursue}
+	best, tied := "", false
+	for _, decision := range Decisions {
+		switch {
+		case counts[decision] == 0:
+		case best == "" || counts[decision] > counts[best]:
+			best, tied = decision, false
+		case counts[decision] == counts[best]:
+			tied = true
+		}
+	}
+	if best != "" && !tied {
+		tally.Leading = &best
+	}
+	return tally
+}
+
+// TallyVotes counts the votes, recording userID's own decision as MyVote
+func TallyVotes(votes []PropertyVote, userID uuid.UUID) VoteTally {
+	counts := make(map[string]int)
+	var mine *string
+	for i := range votes {
+		counts[votes[i].Decision]++
+		if votes[i].UserID == userID {
+			mine = &votes[i].Decision
+		}
+	}
+	tally := NewVoteTally(counts[DecisionPass], counts[DecisionMaybe], counts[DecisionPursue])
+	tally.MyVote = mine
+	return tally
+}
+
+// MaxReactionRunes bounds the length of a reaction emoji, which may combine
+// several code points (skin tones, flags, ZWJ sequences)
+const MaxReactionRunes = 10
+
+// CommentReaction is an emoji reaction by a team member on a comment. A member
+// can react with several different emoji, each once.
+type CommentReaction struct {
+	ID        uuid.UUID `json:"id" gorm:"type:uuid;primary_key"`
+	CommentID uuid.UUID `json:"comment_id" gorm:"type:uuid;not null;uniqueIndex:idx_comment_reaction"`
+	UserID    uuid.UUID `json:"user_id" gorm:"type:uuid;not null;uniqueIndex:idx_comment_reaction;index"`
+	Emoji     string    `json:"emoji" gorm:"size:64;not null;uniqueIndex:idx_comment_reaction"`
+	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
+
+	// Relationships
+	Comment *Comment `json:"-" gorm:"foreignKey:CommentID;constraint:OnDelete:CASCADE"`
+	User    *User    `json:"-" gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+}
+
+// BeforeCreate hook to generate UUID if not provided
+func (cr *CommentReaction) BeforeCreate(tx *gorm.DB) (err error) {
+	if cr.ID == uuid.Nil {
+		cr.ID = uuid.New()
+	}
+	return
+}
+
+// TableName specifies the table name for GORM
+func (CommentReaction) TableName() string {
+	return "comment_reactions"
+}
+
+// IsEmoji reports whether s is a single emoji, up to MaxReactionRunes code
+// points: one pictographic symbol, or several joined by zero-width joiners,
+// each optionally followed by a variation selector, skin tone or subdivision
+// tags; a flag made of two regional indicators; or a keycap
+func IsEmoji(s string) bool {
+	if s == "" || !utf8.ValidString(s) || utf8.RuneCountInString(s) > MaxReactionRunes {
+		return false
+	}
+
+	runes := []rune(s)
+	if isRegionalIndicator(runes[0]) {
+		return len(runes) == 2 && isRegionalIndicator(runes[1])
+	}
+	if r := runes[0]; r == '#' || r == '*' || (r >= '0' && r <= '9') {
+		rest := string(runes[1:])
+		return rest == "\u20e3" || rest == "\ufe0f\u20e3"
+	}
+
+	expectSymbol := true
+	for _, r := range runes {
+		switch {
+		case expectSymbol:
+			if !unicode.Is(unicode.So, r) || isRegionalIndicator(r) {
+				return false
+			}
+			expectSymbol = false
+		case r == '\u200d':
+			// Zero-width joiner: another symbol must follow
+			expectSymbol = true
+		case r == '\ufe0f':
+			// Emoji presentation selector
+		case unicode.Is(unicode.Sk, r) && r >= 0x1f3fb && r <= 0x1f3ff:
+			// Skin tone modifiers
+		case r >= 0xe0020 && r <= 0xe007f:
+			// Tag characters in subdivision flags
+		default:
+			return false
+		}
+	}
+	return !expectSymbol
+}
+
+// isRegionalIndicator reports whether r is one of the letters flags are made of
+func isRegionalIndicator(r rune) bool {
+	return r >= 0x1f1e6 && r <= 0x1f1ff
+}
+
+// ReactionSummary counts one emoji's reactions on a comment
+type ReactionSummary struct {
+	Emoji   string      `json:"emoji"`
+	Count   int         `json:"count"`
+	UserIDs []uuid.UUID `json:"user_ids"`
+}
+
+// SummarizeReactions groups reactions by emoji, ordered by the first reaction
+// with each emoji
+func SummarizeReactions(reactions []CommentReaction) []ReactionSummary {
+	sorted := append([]CommentReaction(nil), reactions...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].CreatedAt.Before(sorted[j].CreatedAt)
+	})
+
+	summaries := []ReactionSummary{}
+	index := make(map[string]int)
+	for _, reaction := range sorted {
+		i, ok := index[reaction.Emoji]
+		if !ok {
+			i = len(summaries)
+			index[reaction.Emoji] = i
+			summaries = append(summaries, ReactionSummary{Emoji: reaction.Emoji, UserIDs: []uuid.UUID{}})
+		}
+		summaries[i].Count++
+		summaries[i].UserIDs = append(summaries[i].UserIDs, reaction.UserID)
+	}
+	return summaries
+}
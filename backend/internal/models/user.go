@@ -9,7 +9,7 @@ import (
 
 // User represents an individual investor or team member
 type User struct {
-	ID           uuid.UUID `json:"id" gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
+	ID           uuid.UUID `json:"id" gorm:"type:uuid;primaryKey"`
 	Email        string    `json:"email" gorm:"unique;not null;size:255" validate:"required,email"`
 	PasswordHash string    `json:"-" gorm:"not null;size:255"`
 	FirstName    string    `json:"first_name" gorm:"not null;size:50" validate:"required,max=50"`
@@ -0,0 +1,223 @@
+package models
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Outlier detection defaults
+const (
+	// DefaultOutlierSigma is how many standard deviations from the other
+	// sources' consensus make a valuation an outlier
+	DefaultOutlierSigma = 2.0
+	// MinOutlierPeers is how many other sources must have reported before a
+	// valuation can be judged
+	MinOutlierPeers = 2
+	// minPeerDeviationRatio floors the peers' standard deviation at a share
+	// of their consensus, so sources that happen to agree exactly do not turn
+	// every small difference into an outlier
+	minPeerDeviationRatio = 0.01
+)
+
+// DefaultTrailingWindows are the trailing change windows, in days
+var DefaultTrailingWindows = []int{30, 90, 365}
+
+// ValuationPoint is one valuation in a source's time series
+type ValuationPoint struct {
+	ValuationID uuid.UUID `json:"valuation_id"`
+	Date        time.Time `json:"date"`
+	Value       float64   `json:"value"`
+	// ChangePercent is the change from the source's previous point
+	ChangePercent *float64 `json:"change_percent"`
+	// PeerConsensus is the median of the other sources' values as of the
+	// point's date, and Deviation is the distance from it in standard
+	// deviations of those values; both are empty with too few peers
+	PeerConsensus *float64 `json:"peer_consensus"`
+	Deviation     *float64 `json:"deviation"`
+	Outlier       bool     `json:"outlier"`
+}
+
+// TrailingChange is the change of a source's latest value over a window
+// ending at its latest point. The base is the source's value as of the start
+// of the window; without one the change is unknown.
+type TrailingChange struct {
+	Days          int        `json:"days"`
+	BaseDate      *time.Time `json:"base_date"`
+	BaseValue     *float64   `json:"base_value"`
+	ChangePercent *float64   `json:"change_percent"`
+}
+
+// ValuationSeries is one source's valuations of one type, oldest first
+type ValuationSeries struct {
+	Source   string           `json:"source"`
+	Points   []ValuationPoint `json:"points"`
+	Latest   ValuationPoint   `json:"latest"`
+	Trailing []TrailingChange `json:"trailing"`
+	Outliers int              `json:"outliers"`
+}
+
+// ValuationHistory holds every source's series for one valuation type
+type ValuationHistory struct {
+	ValuationType string            `json:"valuation_type"`
+	Series        []ValuationSeries `json:"series"`
+	Outliers      int               `json:"outliers"`
+}
+
+// BuildValuationHistory turns valuations into per-type, per-source time
+// series in ValuationTypes order, skipping types without valuations. A source
+// reporting twice on one date keeps its later record. Each point is compared
+// with the other sources' latest values as of its date and flagged when it is
+// more than sigma standard deviations from their median.
+func BuildValuationHistory(valuations []PropertyValuation, sigma float64, windows []int) []ValuationHistory {
+	// type -> source -> date -> valuation
+	byDate := make(map[string]map[string]map[time.Time]PropertyValuation)
+	for _, valuation := range valuations {
+		bySource := byDate[valuation.ValuationType]
+		if bySource == nil {
+			bySource = make(map[string]map[time.Time]PropertyValuation)
+			byDate[valuation.ValuationType] = bySource
+		}
+		dates := bySource[valuation.Source]
+		if dates == nil {
+			dates = make(map[time.Time]PropertyValuation)
+			bySource[valuation.Source] = dates
+		}
+		day := dayOf(valuation.ValuationDate)
+		if current, ok := dates[day]; !ok || valuation.CreatedAt.After(current.CreatedAt) {
+			dates[day] = valuation
+		}
+	}
+
+	histories := []ValuationHistory{}
+	for _, valuationType := range ValuationTypes {
+		bySource := byDate[valuationType]
+		if len(bySource) == 0 {
+			continue
+		}
+
+		history := ValuationHistory{ValuationType: valuationType}
+		for source, dates := range bySource {
+			series := ValuationSeries{Source: source}
+			for day, valuation := range dates {
+				series.Points = append(series.Points, ValuationPoint{
+					ValuationID: valuation.ID,
+					Date:        day,
+					Value:       valuation.Value,
+				})
+			}
+			sort.Slice(series.Points, func(i, j int) bool {
+				return series.Points[i].Date.Before(series.Points[j].Date)
+			})
+			for i := 1; i < len(series.Points); i++ {
+				series.Points[i].ChangePercent = percentChange(series.Points[i-1].Value, series.Points[i].Value)
+			}
+			history.Series = append(history.Series, series)
+		}
+		sort.Slice(history.Series, func(i, j int) bool {
+			return history.Series[i].Source < history.Series[j].Source
+		})
+
+		for i := range history.Series {
+			series := &history.Series[i]
+			for j := range series.Points {
+				point := &series.Points[j]
+				var peers []float64
+				for k := range history.Series {
+					if k == i {
+						continue
+					}
+					if value, _, ok := valueAsOf(history.Series[k].Points, point.Date); ok {
+						peers = append(peers, value)
+					}
+				}
+				flagOutlier(point, peers, sigma)
+				if point.Outlier {
+					series.Outliers++
+				}
+			}
+
+			series.Latest = series.Points[len(series.Points)-1]
+			for _, days := range windows {
+				series.Trailing = append(series.Trailing, trailingChange(series.Points, days))
+			}
+			history.Outliers += series.Outliers
+		}
+
+		histories = append(histories, history)
+	}
+	return histories
+}
+
+// flagOutlier compares a point with its peers' values
+func flagOutlier(point *ValuationPoint, peers []float64, sigma float64) {
+	if len(peers) < MinOutlierPeers {
+		return
+	}
+
+	consensus := median(peers)
+	deviation := math.Max(standardDeviation(peers), consensus*minPeerDeviationRatio)
+	if deviation <= 0 {
+		return
+	}
+
+	distance := (point.Value - consensus) / deviation
+	point.PeerConsensus = floatPtr(roundCents(consensus))
+	point.Deviation = floatPtr(math.Round(distance*100) / 100)
+	point.Outlier = math.Abs(distance) > sigma
+}
+
+// trailingChange measures the change of the latest point over a window
+func trailingChange(points []ValuationPoint, days int) TrailingChange {
+	change := TrailingChange{Days: days}
+	latest := points[len(points)-1]
+	baseValue, baseDate, ok := valueAsOf(points, latest.Date.AddDate(0, 0, -days))
+	if !ok {
+		return change
+	}
+
+	change.BaseDate = &baseDate
+	change.BaseValue = &baseValue
+	change.ChangePercent = percentChange(baseValue, latest.Value)
+	return change
+}
+
+// valueAsOf returns the value of the last point on or before a date
+func valueAsOf(points []ValuationPoint, date time.Time) (float64, time.Time, bool) {
+	i := sort.Search(len(points), func(i int) bool {
+		return points[i].Date.After(date)
+	})
+	if i == 0 {
+		return 0, time.Time{}, false
+	}
+	return points[i-1].Value, points[i-1].Date, true
+}
+
+// percentChange returns the change from one value to another in percent
+func percentChange(from, to float64) *float64 {
+	if from == 0 {
+		return nil
+	}
+	return floatPtr(math.Round((to-from)/from*10000) / 100)
+}
+
+// standardDeviation is the population standard deviation of the values
+func standardDeviation(values []float64) float64 {
+	average := mean(values)
+	total := 0.0
+	for _, value := range values {
+		total += (value - average) * (value - average)
+	}
+	return math.Sqrt(total / float64(len(values)))
+}
+
+// dayOf truncates a time to its UTC date
+func dayOf(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func floatPtr(value float64) *float64 {
+	return &value
+}
@@ -0,0 +1,151 @@
+package models
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// ValuationSummary aggregates one valuation type across sources. Only each
+// source's latest valuation counts, so a source that reports often does not
+// outweigh one that reports rarely.
+type ValuationSummary struct {
+	ValuationType string              `json:"valuation_type"`
+	Latest        []PropertyValuation `json:"latest"`
+	Sources       int                 `json:"sources"`
+	Mean          float64             `json:"mean"`
+	Median        float64             `json:"median"`
+	Min           float64             `json:"min"`
+	Max           float64             `json:"max"`
+	// Spread is Max - Min; SpreadPercent is the spread relative to the mean
+	Spread        float64 `json:"spread"`
+	SpreadPercent float64 `json:"spread_percent"`
+	// NewestDate and OldestDate bound the dates of the latest valuations, and
+	// the age fields count days from them to the summary date
+	NewestDate    time.Time `json:"newest_date"`
+	OldestDate    time.Time `json:"oldest_date"`
+	NewestAgeDays int       `json:"newest_age_days"`
+	OldestAgeDays int       `json:"oldest_age_days"`
+}
+
+// Consensus is the value the sources agree on: the median, so one outlying
+// source cannot drag it far
+func (vs *ValuationSummary) Consensus() float64 {
+	return vs.Median
+}
+
+// SummarizeValuations summarizes the valuations per type, in ValuationTypes
+// order, skipping types without valuations. Ages are measured at asOf.
+func SummarizeValuations(valuations []PropertyValuation, asOf time.Time) []ValuationSummary {
+	latest := make(map[string]map[string]PropertyValuation)
+	for _, valuation := range valuations {
+		bySource := latest[valuation.ValuationType]
+		if bySource == nil {
+			bySource = make(map[string]PropertyValuation)
+			latest[valuation.ValuationType] = bySource
+		}
+		current, ok := bySource[valuation.Source]
+		if !ok || isNewerValuation(valuation, current) {
+			bySource[valuation.Source] = valuation
+		}
+	}
+
+	summaries := []ValuationSummary{}
+	for _, valuationType := range ValuationTypes {
+		bySource := latest[valuationType]
+		if len(bySource) == 0 {
+			continue
+		}
+
+		summary := ValuationSummary{ValuationType: valuationType}
+		for _, valuation := range bySource {
+			summary.Latest = append(summary.Latest, valuation)
+		}
+		sort.Slice(summary.Latest, func(i, j int) bool {
+			return summary.Latest[i].Source < summary.Latest[j].Source
+		})
+
+		values := make([]float64, 0, len(summary.Latest))
+		summary.NewestDate = summary.Latest[0].ValuationDate
+		summary.OldestDate = summary.Latest[0].ValuationDate
+		for _, valuation := range summary.Latest {
+			values = append(values, valuation.Value)
+			if valuation.ValuationDate.After(summary.NewestDate) {
+				summary.NewestDate = valuation.ValuationDate
+			}
+			if valuation.ValuationDate.Before(summary.OldestDate) {
+				summary.OldestDate = valuation.ValuationDate
+			}
+		}
+
+		summary.Sources = len(values)
+		summary.Mean = roundCents(mean(values))
+		summary.Median = roundCents(median(values))
+		summary.Min, summary.Max = minMax(values)
+		summary.Spread = roundCents(summary.Max - summary.Min)
+		if summary.Mean > 0 {
+			summary.SpreadPercent = math.Round(summary.Spread/summary.Mean*10000) / 100
+		}
+		summary.NewestAgeDays = daysBetween(summary.NewestDate, asOf)
+		summary.OldestAgeDays = daysBetween(summary.OldestDate, asOf)
+
+		summaries = append(summaries, summary)
+	}
+	return summaries
+}
+
+// FindValuationSummary returns the summary of the given type, or nil
+func FindValuationSummary(summaries []ValuationSummary, valuationType string) *ValuationSummary {
+	for i := range summaries {
+		if summaries[i].ValuationType == valuationType {
+			return &summaries[i]
+		}
+	}
+	return nil
+}
+
+// isNewerValuation orders valuations by date, then by when they were recorded
+func isNewerValuation(a, b PropertyValuation) bool {
+	if !a.ValuationDate.Equal(b.ValuationDate) {
+		return a.ValuationDate.After(b.ValuationDate)
+	}
+	return a.CreatedAt.After(b.CreatedAt)
+}
+
+func mean(values []float64) float64 {
+	total := 0.0
+	for _, value := range values {
+		total += value
+	}
+	return total / float64(len(values))
+}
+
+func median(values []float64) float64 {
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	middle := len(sorted) / 2
+	if len(sorted)%2 == 1 {
+		return sorted[middle]
+	}
+	return (sorted[middle-1] + sorted[middle]) / 2
+}
+
+func minMax(values []float64) (float64, float64) {
+	lowest, highest := values[0], values[0]
+	for _, value := range values[1:] {
+		lowest = math.Min(lowest, value)
+		highest = math.Max(highest, value)
+	}
+	return lowest, highest
+}
+
+// daysBetween counts whole calendar days from one date to another, never negative
+func daysBetween(from, to time.Time) int {
+	fromDay := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.UTC)
+	toDay := time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, time.UTC)
+	days := int(toDay.Sub(fromDay).Hours() / 24)
+	if days < 0 {
+		return 0
+	}
+	return days
+}
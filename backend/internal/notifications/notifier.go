@@ -0,0 +1,99 @@
+package notifications
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"time"
+)
+
+// Message is a notification addressed to one or more users
+type Message struct {
+	// To holds recipient email addresses
+	To      []string
+	Subject string
+	Body    string
+	// Event names what happened, e.g. "criteria.match"
+	Event string
+	// Data carries structured details for machine consumers such as webhooks
+	Data interface{}
+}
+
+// Notifier delivers messages over one channel
+type Notifier interface {
+	// Name identifies the channel in logs, e.g. "smtp"
+	Name() string
+	Send(ctx context.Context, msg Message) error
+}
+
+// SendAll delivers msg through every notifier, joining any failures
+func SendAll(ctx context.Context, notifiers []Notifier, msg Message) error {
+	var errs []error
+	for _, notifier := range notifiers {
+		if err := notifier.Send(ctx, msg); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", notifier.Name(), err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// DeliveryTimeout bounds how long one message may take across all notifiers
+const DeliveryTimeout = 30 * time.Second
+
+// Deliver sends messages through the notifiers in the background, so a slow or
+// failing channel never holds up the request that produced them. Each message
+// gets its own DeliveryTimeout; failures are logged under prefix.
+func Deliver(prefix string, notifiers []Notifier, msgs []Message) {
+	if len(notifiers) == 0 || len(msgs) == 0 {
+		return
+	}
+	go func() {
+		for _, msg := range msgs {
+			ctx, cancel := context.WithTimeout(context.Background(), DeliveryTimeout)
+			if err := SendAll(ctx, notifiers, msg); err != nil {
+				log.Printf("%s: failed to deliver %s %q: %v", prefix, msg.Event, msg.Subject, err)
+			}
+			cancel()
+		}
+	}()
+}
+
+// FromEnv builds the notifiers enabled by the environment:
+//
+//	SMTP_HOST, SMTP_PORT (default 25), SMTP_USERNAME, SMTP_PASSWORD,
+//	SMTP_FROM (default alerts@localhost) enable email;
+//	NOTIFY_WEBHOOK_URL enables a JSON webhook.
+func FromEnv() []Notifier {
+	var notifiers []Notifier
+
+	if host := os.Getenv("SMTP_HOST"); host != "" {
+		port, err := strconv.Atoi(getEnv("SMTP_PORT", "25"))
+		if err != nil {
+			port = 25
+		}
+		notifiers = append(notifiers, &SMTPNotifier{
+			Host:     host,
+			Port:     port,
+			Username: os.Getenv("SMTP_USERNAME"),
+			Password: os.Getenv("SMTP_PASSWORD"),
+			From:     getEnv("SMTP_FROM", "alerts@localhost"),
+			Timeout:  10 * time.Second,
+		})
+	}
+
+	if url := os.Getenv("NOTIFY_WEBHOOK_URL"); url != "" {
+		notifiers = append(notifiers, NewWebhookNotifier(url))
+	}
+
+	return notifiers
+}
+
+func getEnv(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"rental-property-mgmt/internal/models"
+)
+
+// CommentRepository stores property comments with their revisions and emoji
+// reactions
+type CommentRepository interface {
+	Get(id uuid.UUID) (*models.Comment, error)
+	Create(comment *models.Comment) error
+	Save(comment *models.Comment) error
+	// ThreadIDs returns a page of a property's top-level comment IDs, oldest
+	// first, and the number of top-level comments
+	ThreadIDs(propertyID uuid.UUID, limit, offset int) ([]uuid.UUID, int64, error)
+	// ListThreads returns the comments of the given threads down to maxDepth,
+	// oldest first, with their authors
+	ListThreads(threadIDs []uuid.UUID, maxDepth int) ([]models.Comment, error)
+
+	CreateRevision(revision *models.CommentRevision) error
+	// Revisions returns a comment's revisions, oldest first
+	Revisions(commentID uuid.UUID) ([]models.CommentRevision, error)
+
+	// AddReaction records a reaction; a repeated reaction is ignored
+	AddReaction(reaction *models.CommentReaction) error
+	// RemoveReaction reports whether the reaction existed
+	RemoveReaction(commentID, userID uuid.UUID, emoji string) (bool, error)
+	// Reactions returns the reactions on the given comments, oldest first
+	Reactions(commentIDs []uuid.UUID) ([]models.CommentReaction, error)
+}
+
+type gormCommentRepository struct {
+	db *gorm.DB
+}
+
+func (r *gormCommentRepository) Get(id uuid.UUID) (*models.Comment, error) {
+	var comment models.Comment
+	if err := first(r.db, &comment, "id = ?", id); err != nil {
+		return nil, err
+	}
+	return &comment, nil
+}
+
+func (r *gormCommentRepository) Create(comment *models.Comment) error {
+	return r.db.Omit(clause.Associations).Create(comment).Error
+}
+
+func (r *gormCommentRepository) Save(comment *models.Comment) error {
+	return r.db.Omit(clause.Associations).Save(comment).Error
+}
+
+func (r *gormCommentRepository) ThreadIDs(propertyID uuid.UUID, limit, offset int) ([]uuid.UUID, int64, error) {
+	topLevel := r.db.Model(&models.Comment{}).Where("property_id = ? AND parent_id IS NULL", propertyID)
+
+	var total int64
+	if err := topLevel.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var ids []uuid.UUID
+	err := topLevel.
+		Order("created_at ASC, id ASC").
+		Limit(limit).
+		Offset(offset).
+		Pluck("id", &ids).Error
+	return ids, total, err
+}
+
+func (r *gormCommentRepository) ListThreads(threadIDs []uuid.UUID, maxDepth int) ([]models.Comment, error) {
+	var comments []models.Comment
+	err := r.db.
+		Preload("User").
+		Where("thread_id IN ? AND depth <= ?", threadIDs, maxDepth).
+		Order("created_at ASC, id ASC").
+		Find(&comments).Error
+	return comments, err
+}
+
+func (r *gormCommentRepository) CreateRevision(revision *models.CommentRevision) error {
+	return r.db.Omit(clause.Associations).Create(revision).Error
+}
+
+func (r *gormCommentRepository) Revisions(commentID uuid.UUID) ([]models.CommentRevision, error) {
+	revisions := []models.CommentRevision{}
+	err := r.db.
+		Where("comment_id = ?", commentID).
+		Order("created_at ASC, id ASC").
+		Find(&revisions).Error
+	return revisions, err
+}
+
+func (r *gormCommentRepository) AddReaction(reaction *models.CommentReaction) error {
+	return r.db.
+		Omit(clause.Associations).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(reaction).Error
+}
+
+func (r *gormCommentRepository) RemoveReaction(commentID, userID uuid.UUID, emoji string) (bool, error) {
+	result := r.db.
+		Where("comment_id = ? AND user_id = ? AND emoji = ?", commentID, userID, emoji).
+		Delete(&models.CommentReaction{})
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *gormCommentRepository) Reactions(commentIDs []uuid.UUID) ([]models.CommentReaction, error) {
+	var reactions []models.CommentReaction
+	err := r.db.
+		Where("comment_id IN ?", commentIDs).
+		Order("created_at ASC, id ASC").
+		Find(&reactions).Error
+	return reactions, err
+}
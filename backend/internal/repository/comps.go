@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"rental-property-mgmt/internal/models"
+)
+
+// CompRepository stores the comparable sales recorded against properties
+type CompRepository interface {
+	List(propertyID uuid.UUID) ([]models.Comp, error)
+	Get(id uuid.UUID) (*models.Comp, error)
+	Create(comp *models.Comp) error
+	Save(comp *models.Comp) error
+	Delete(comp *models.Comp) error
+}
+
+// RentCompRepository stores the comparable rentals recorded against properties
+type RentCompRepository interface {
+	List(propertyID uuid.UUID) ([]models.RentComp, error)
+	Get(id uuid.UUID) (*models.RentComp, error)
+	Create(comp *models.RentComp) error
+	Save(comp *models.RentComp) error
+	Delete(comp *models.RentComp) error
+}
+
+type gormCompRepository struct {
+	db *gorm.DB
+}
+
+func (r *gormCompRepository) List(propertyID uuid.UUID) ([]models.Comp, error) {
+	comps := []models.Comp{}
+	err := r.db.Where("property_id = ?", propertyID).Find(&comps).Error
+	return comps, err
+}
+
+func (r *gormCompRepository) Get(id uuid.UUID) (*models.Comp, error) {
+	var comp models.Comp
+	if err := first(r.db, &comp, "id = ?", id); err != nil {
+		return nil, err
+	}
+	return &comp, nil
+}
+
+func (r *gormCompRepository) Create(comp *models.Comp) error {
+	return r.db.Omit(clause.Associations).Create(comp).Error
+}
+
+func (r *gormCompRepository) Save(comp *models.Comp) error {
+	return r.db.Omit(clause.Associations).Save(comp).Error
+}
+
+func (r *gormCompRepository) Delete(comp *models.Comp) error {
+	return r.db.Delete(comp).Error
+}
+
+type gormRentCompRepository struct {
+	db *gorm.DB
+}
+
+func (r *gormRentCompRepository) List(propertyID uuid.UUID) ([]models.RentComp, error) {
+	comps := []models.RentComp{}
+	err := r.db.Where("property_id = ?", propertyID).Find(&comps).Error
+	return comps, err
+}
+
+func (r *gormRentCompRepository) Get(id uuid.UUID) (*models.RentComp, error) {
+	var comp models.RentComp
+	if err := first(r.db, &comp, "id = ?", id); err != nil {
+		return nil, err
+	}
+	return &comp, nil
+}
+
+func (r *gormRentCompRepository) Create(comp *models.RentComp) error {
+	return r.db.Omit(clause.Associations).Create(comp).Error
+}
+
+func (r *gormRentCompRepository) Save(comp *models.RentComp) error {
+	return r.db.Omit(clause.Associations).Save(comp).Error
+}
+
+func (r *gormRentCompRepository) Delete(comp *models.RentComp) error {
+	return r.db.Delete(comp).Error
+}
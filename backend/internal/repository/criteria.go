@@ -0,0 +1,134 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"rental-property-mgmt/internal/models"
+)
+
+// CriteriaRepository stores buying-box criteria and their versions. Criteria
+// are private to the user who defined them.
+type CriteriaRepository interface {
+	GetForUser(userID, id uuid.UUID) (*models.BuyingBoxCriteria, error)
+	// GetForUpdate is GetForUser that also locks the row until the
+	// transaction ends, so concurrent edits take turns
+	GetForUpdate(userID, id uuid.UUID) (*models.BuyingBoxCriteria, error)
+	// List returns the user's criteria by name, only the active ones when
+	// activeOnly is set
+	List(userID uuid.UUID, activeOnly bool) ([]models.BuyingBoxCriteria, error)
+	// ListActive returns every user's active criteria
+	ListActive() ([]models.BuyingBoxCriteria, error)
+	Create(criteria *models.BuyingBoxCriteria) error
+	Save(criteria *models.BuyingBoxCriteria) error
+	SetActive(criteria *models.BuyingBoxCriteria) error
+	Delete(criteria *models.BuyingBoxCriteria) error
+
+	// CreateVersion returns ErrConflict when the criteria set already has
+	// the version
+	CreateVersion(version *models.BuyingBoxCriteriaVersion) error
+	// Versions returns a criteria set's versions, newest first
+	Versions(criteriaID uuid.UUID) ([]models.BuyingBoxCriteriaVersion, error)
+	GetVersion(criteriaID uuid.UUID, version int) (*models.BuyingBoxCriteriaVersion, error)
+}
+
+// MatchRepository stores buying-box matches; users see them through match
+// notifications
+type MatchRepository interface {
+	// Create reports whether the match is new; a property matches each
+	// criteria set at most once
+	Create(match *models.CriteriaMatch) (bool, error)
+}
+
+type gormCriteriaRepository struct {
+	db *gorm.DB
+}
+
+func (r *gormCriteriaRepository) GetForUser(userID, id uuid.UUID) (*models.BuyingBoxCriteria, error) {
+	var criteria models.BuyingBoxCriteria
+	if err := first(r.db.Where("id = ? AND user_id = ?", id, userID), &criteria); err != nil {
+		return nil, err
+	}
+	return &criteria, nil
+}
+
+func (r *gormCriteriaRepository) GetForUpdate(userID, id uuid.UUID) (*models.BuyingBoxCriteria, error) {
+	var criteria models.BuyingBoxCriteria
+	query := r.db.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ? AND user_id = ?", id, userID)
+	if err := first(query, &criteria); err != nil {
+		return nil, err
+	}
+	return &criteria, nil
+}
+
+func (r *gormCriteriaRepository) List(userID uuid.UUID, activeOnly bool) ([]models.BuyingBoxCriteria, error) {
+	query := r.db.Where("user_id = ?", userID)
+	if activeOnly {
+		query = query.Where("is_active = ?", true)
+	}
+
+	var criteria []models.BuyingBoxCriteria
+	err := query.Order("name ASC").Find(&criteria).Error
+	return criteria, err
+}
+
+func (r *gormCriteriaRepository) ListActive() ([]models.BuyingBoxCriteria, error) {
+	var criteria []models.BuyingBoxCriteria
+	err := r.db.Where("is_active = ?", true).Find(&criteria).Error
+	return criteria, err
+}
+
+// Create selects every column so an explicit is_active=false is not replaced
+// by the column default
+func (r *gormCriteriaRepository) Create(criteria *models.BuyingBoxCriteria) error {
+	return r.db.Select("*").Omit(clause.Associations).Create(criteria).Error
+}
+
+func (r *gormCriteriaRepository) Save(criteria *models.BuyingBoxCriteria) error {
+	return r.db.Omit(clause.Associations).Save(criteria).Error
+}
+
+func (r *gormCriteriaRepository) SetActive(criteria *models.BuyingBoxCriteria) error {
+	return r.db.Model(criteria).Update("is_active", criteria.IsActive).Error
+}
+
+func (r *gormCriteriaRepository) Delete(criteria *models.BuyingBoxCriteria) error {
+	return r.db.Delete(criteria).Error
+}
+
+func (r *gormCriteriaRepository) CreateVersion(version *models.BuyingBoxCriteriaVersion) error {
+	err := r.db.Omit(clause.Associations).Create(version).Error
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return ErrConflict
+	}
+	return err
+}
+
+func (r *gormCriteriaRepository) Versions(criteriaID uuid.UUID) ([]models.BuyingBoxCriteriaVersion, error) {
+	var versions []models.BuyingBoxCriteriaVersion
+	err := r.db.Where("criteria_id = ?", criteriaID).Order("version DESC").Find(&versions).Error
+	return versions, err
+}
+
+func (r *gormCriteriaRepository) GetVersion(criteriaID uuid.UUID, version int) (*models.BuyingBoxCriteriaVersion, error) {
+	var snapshot models.BuyingBoxCriteriaVersion
+	if err := first(r.db.Where("criteria_id = ? AND version = ?", criteriaID, version), &snapshot); err != nil {
+		return nil, err
+	}
+	return &snapshot, nil
+}
+
+type gormMatchRepository struct {
+	db *gorm.DB
+}
+
+func (r *gormMatchRepository) Create(match *models.CriteriaMatch) (bool, error) {
+	result := r.db.
+		Omit(clause.Associations).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(match)
+	return result.RowsAffected > 0, result.Error
+}
@@ -0,0 +1,27 @@
+package repository
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"rental-property-mgmt/internal/models"
+)
+
+// MetricsRepository stores the calculated financial metrics of properties
+type MetricsRepository interface {
+	Create(metrics *models.FinancialMetrics) error
+	DeleteForProperty(propertyID uuid.UUID) error
+}
+
+type gormMetricsRepository struct {
+	db *gorm.DB
+}
+
+func (r *gormMetricsRepository) Create(metrics *models.FinancialMetrics) error {
+	return r.db.Omit(clause.Associations).Create(metrics).Error
+}
+
+func (r *gormMetricsRepository) DeleteForProperty(propertyID uuid.UUID) error {
+	return r.db.Where("property_id = ?", propertyID).Delete(&models.FinancialMetrics{}).Error
+}
@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"rental-property-mgmt/internal/models"
+)
+
+// NotificationRepository stores comment mentions and users' notification
+// inboxes of mentions, replies and buying-box matches
+type NotificationRepository interface {
+	Mentions(commentID uuid.UUID) ([]models.CommentMention, error)
+	CreateMention(mention *models.CommentMention) error
+	// DeleteMentions removes a comment's mentions of everyone but keep
+	DeleteMentions(commentID uuid.UUID, keep []uuid.UUID) error
+
+	Create(notification *models.Notification) error
+	GetForUser(userID, id uuid.UUID) (*models.Notification, error)
+	// List returns a page of the user's notifications, newest first, with
+	// actor and comment or match, and the number of notifications matching
+	List(userID uuid.UUID, unreadOnly bool, limit, offset int) ([]models.Notification, int64, error)
+	CountUnread(userID uuid.UUID) (int64, error)
+	MarkRead(notification *models.Notification, at time.Time) error
+	MarkAllRead(userID uuid.UUID, at time.Time) error
+}
+
+type gormNotificationRepository struct {
+	db *gorm.DB
+}
+
+func (r *gormNotificationRepository) Mentions(commentID uuid.UUID) ([]models.CommentMention, error) {
+	var mentions []models.CommentMention
+	err := r.db.Where("comment_id = ?", commentID).Find(&mentions).Error
+	return mentions, err
+}
+
+func (r *gormNotificationRepository) CreateMention(mention *models.CommentMention) error {
+	return r.db.Omit(clause.Associations).Create(mention).Error
+}
+
+func (r *gormNotificationRepository) DeleteMentions(commentID uuid.UUID, keep []uuid.UUID) error {
+	stale := r.db.Where("comment_id = ?", commentID)
+	if len(keep) > 0 {
+		stale = stale.Where("user_id NOT IN ?", keep)
+	}
+	return stale.Delete(&models.CommentMention{}).Error
+}
+
+func (r *gormNotificationRepository) Create(notification *models.Notification) error {
+	return r.db.Omit(clause.Associations).Create(notification).Error
+}
+
+func (r *gormNotificationRepository) GetForUser(userID, id uuid.UUID) (*models.Notification, error) {
+	var notification models.Notification
+	if err := first(r.db.Where("id = ? AND user_id = ?", id, userID), &notification); err != nil {
+		return nil, err
+	}
+	return &notification, nil
+}
+
+func (r *gormNotificationRepository) List(userID uuid.UUID, unreadOnly bool, limit, offset int) ([]models.Notification, int64, error) {
+	query := r.db.Model(&models.Notification{}).Where("user_id = ?", userID)
+	if unreadOnly {
+		query = query.Where("read_at IS NULL")
+	}
+
+	var total int64
+	if err := query.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	notifications := []models.Notification{}
+	err := query.
+		Preload("Actor").
+		Preload("Comment").
+		Preload("Match.Criteria").
+		Preload("Match.Property").
+		Order("created_at DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&notifications).Error
+	return notifications, total, err
+}
+
+func (r *gormNotificationRepository) CountUnread(userID uuid.UUID) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.Notification{}).
+		Where("user_id = ? AND read_at IS NULL", userID).
+		Count(&count).Error
+	return count, err
+}
+
+func (r *gormNotificationRepository) MarkRead(notification *models.Notification, at time.Time) error {
+	return r.db.Model(notification).Update("read_at", at).Error
+}
+
+func (r *gormNotificationRepository) MarkAllRead(userID uuid.UUID, at time.Time) error {
+	return r.db.Model(&models.Notification{}).
+		Where("user_id = ? AND read_at IS NULL", userID).
+		Update("read_at", at).Error
+}
@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"rental-property-mgmt/internal/models"
+)
+
+// AssumptionProfileRepository stores market assumption profiles. A user sees
+// their own profiles plus every team-scoped profile.
+type AssumptionProfileRepository interface {
+	ListVisible(userID uuid.UUID) ([]models.AssumptionProfile, error)
+	GetVisible(userID, id uuid.UUID) (*models.AssumptionProfile, error)
+	Create(profile *models.AssumptionProfile) error
+	Save(profile *models.AssumptionProfile) error
+	Delete(profile *models.AssumptionProfile) error
+}
+
+type gormAssumptionProfileRepository struct {
+	db *gorm.DB
+}
+
+// visibleTo scopes a query to profiles the user may read
+func (r *gormAssumptionProfileRepository) visibleTo(userID uuid.UUID) *gorm.DB {
+	return r.db.Where("user_id = ? OR scope = ?", userID, models.ProfileScopeTeam)
+}
+
+func (r *gormAssumptionProfileRepository) ListVisible(userID uuid.UUID) ([]models.AssumptionProfile, error) {
+	var profiles []models.AssumptionProfile
+	err := r.visibleTo(userID).Order("name ASC").Find(&profiles).Error
+	return profiles, err
+}
+
+func (r *gormAssumptionProfileRepository) GetVisible(userID, id uuid.UUID) (*models.AssumptionProfile, error) {
+	var profile models.AssumptionProfile
+	if err := first(r.visibleTo(userID).Where("id = ?", id), &profile); err != nil {
+		return nil, err
+	}
+	return &profile, nil
+}
+
+func (r *gormAssumptionProfileRepository) Create(profile *models.AssumptionProfile) error {
+	return r.db.Omit(clause.Associations).Create(profile).Error
+}
+
+func (r *gormAssumptionProfileRepository) Save(profile *models.AssumptionProfile) error {
+	return r.db.Omit(clause.Associations).Save(profile).Error
+}
+
+func (r *gormAssumptionProfileRepository) Delete(profile *models.AssumptionProfile) error {
+	return r.db.Delete(profile).Error
+}
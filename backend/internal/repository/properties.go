@@ -0,0 +1,203 @@
+package repository
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"rental-property-mgmt/internal/models"
+)
+
+// PropertyRepository stores properties. Loaded properties carry their current
+// financial metrics.
+type PropertyRepository interface {
+	Create(property *models.Property) error
+	Save(property *models.Property) error
+	Get(id uuid.UUID) (*models.Property, error)
+	Exists(id uuid.UUID) (bool, error)
+	ListByIDs(ids []uuid.UUID) ([]models.Property, error)
+	// ListByOwner returns the user's properties, oldest first
+	ListByOwner(userID uuid.UUID) ([]models.Property, error)
+	// ListAll returns every property, oldest first, without metrics
+	ListAll() ([]models.Property, error)
+	// ListSummaries returns a page of the user's portfolio listing with the
+	// team's vote counts, and the number of properties passing the filters
+	ListSummaries(userID uuid.UUID, opts PropertyListOptions) ([]PropertySummaryRow, int64, error)
+}
+
+// Portfolio listing sort keys
+const (
+	PropertySortCreatedAt     = "created_at"
+	PropertySortPurchasePrice = "purchase_price"
+	PropertySortCapRate       = "cap_rate"
+	PropertySortPursueVotes   = "pursue_votes"
+)
+
+// PropertyListOptions pages, sorts and filters the portfolio listing
+type PropertyListOptions struct {
+	Limit      int
+	Offset     int
+	Sort       string
+	Descending bool
+	// Decision keeps properties whose leading team vote is this decision
+	Decision *string
+	// MinPursue keeps properties with at least this many pursue votes
+	MinPursue *int
+	// MaxPass keeps properties with at most this many pass votes
+	MaxPass *int
+}
+
+// PropertySummaryRow is a portfolio listing row
+type PropertySummaryRow struct {
+	ID               uuid.UUID
+	Address          string
+	PropertyType     *string
+	YearBuilt        *int
+	PurchasePrice    float64
+	IntendedRent     *float64
+	CapRate          *float64
+	CashOnCashReturn *float64
+	CreatedAt        time.Time
+	PassVotes        int
+	MaybeVotes       int
+	PursueVotes      int
+	MyVote           *string
+}
+
+// propertySortColumns maps sort keys to listing columns
+var propertySortColumns = map[string]string{
+	PropertySortCreatedAt:     "properties.created_at",
+	PropertySortPurchasePrice: "properties.purchase_price",
+	PropertySortCapRate:       "financial_metrics.cap_rate",
+	PropertySortPursueVotes:   pursueVotesSQL,
+}
+
+// IsPropertySort reports whether sort is a portfolio listing sort key
+func IsPropertySort(sort string) bool {
+	_, ok := propertySortColumns[sort]
+	return ok
+}
+
+// Vote count expressions over the tallies join
+const (
+	passVotesSQL   = "COALESCE(tallies.pass_votes, 0)"
+	maybeVotesSQL  = "COALESCE(tallies.maybe_votes, 0)"
+	pursueVotesSQL = "COALESCE(tallies.pursue_votes, 0)"
+)
+
+// leadingDecisionSQL holds when a decision has strictly more votes than each other decision
+var leadingDecisionSQL = map[string]string{
+	models.DecisionPass:   passVotesSQL + " > " + maybeVotesSQL + " AND " + passVotesSQL + " > " + pursueVotesSQL,
+	models.DecisionMaybe:  maybeVotesSQL + " > " + passVotesSQL + " AND " + maybeVotesSQL + " > " + pursueVotesSQL,
+	models.DecisionPursue: pursueVotesSQL + " > " + passVotesSQL + " AND " + pursueVotesSQL + " > " + maybeVotesSQL,
+}
+
+type gormPropertyRepository struct {
+	db *gorm.DB
+}
+
+// withMetrics preloads the current financial metrics
+func (r *gormPropertyRepository) withMetrics() *gorm.DB {
+	return r.db.Preload("FinancialMetrics", "is_current = ?", true)
+}
+
+func (r *gormPropertyRepository) Create(property *models.Property) error {
+	return r.db.Omit(clause.Associations).Create(property).Error
+}
+
+func (r *gormPropertyRepository) Save(property *models.Property) error {
+	return r.db.Omit(clause.Associations).Save(property).Error
+}
+
+func (r *gormPropertyRepository) Get(id uuid.UUID) (*models.Property, error) {
+	var property models.Property
+	if err := first(r.withMetrics(), &property, "id = ?", id); err != nil {
+		return nil, err
+	}
+	return &property, nil
+}
+
+func (r *gormPropertyRepository) Exists(id uuid.UUID) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.Property{}).Where("id = ?", id).Count(&count).Error
+	return count > 0, err
+}
+
+func (r *gormPropertyRepository) ListByIDs(ids []uuid.UUID) ([]models.Property, error) {
+	var properties []models.Property
+	err := r.withMetrics().Where("id IN ?", ids).Find(&properties).Error
+	return properties, err
+}
+
+func (r *gormPropertyRepository) ListByOwner(userID uuid.UUID) ([]models.Property, error) {
+	var properties []models.Property
+	err := r.withMetrics().Where("user_id = ?", userID).Order("created_at").Find(&properties).Error
+	return properties, err
+}
+
+func (r *gormPropertyRepository) ListAll() ([]models.Property, error) {
+	var properties []models.Property
+	err := r.db.Order("created_at").Find(&properties).Error
+	return properties, err
+}
+
+// ListSummaries sorts properties without a value for the sort column last
+func (r *gormPropertyRepository) ListSummaries(userID uuid.UUID, opts PropertyListOptions) ([]PropertySummaryRow, int64, error) {
+	sortColumn, ok := propertySortColumns[opts.Sort]
+	if !ok {
+		return nil, 0, fmt.Errorf("unknown sort %q", opts.Sort)
+	}
+
+	tallies := r.db.Model(&models.PropertyVote{}).
+		Select("property_id, " +
+			"SUM(CASE WHEN decision = 'pass' THEN 1 ELSE 0 END) AS pass_votes, " +
+			"SUM(CASE WHEN decision = 'maybe' THEN 1 ELSE 0 END) AS maybe_votes, " +
+			"SUM(CASE WHEN decision = 'pursue' THEN 1 ELSE 0 END) AS pursue_votes").
+		Group("property_id")
+
+	query := r.db.Table("properties").
+		Joins("LEFT JOIN (?) AS tallies ON tallies.property_id = properties.id", tallies).
+		Joins("LEFT JOIN financial_metrics ON financial_metrics.property_id = properties.id AND financial_metrics.is_current = ?", true).
+		Joins("LEFT JOIN property_votes AS my_votes ON my_votes.property_id = properties.id AND my_votes.user_id = ?", userID).
+		Where("properties.user_id = ?", userID)
+
+	if opts.Decision != nil {
+		condition, ok := leadingDecisionSQL[*opts.Decision]
+		if !ok {
+			return nil, 0, fmt.Errorf("unknown decision %q", *opts.Decision)
+		}
+		query = query.Where(condition)
+	}
+	if opts.MinPursue != nil {
+		query = query.Where(pursueVotesSQL+" >= ?", *opts.MinPursue)
+	}
+	if opts.MaxPass != nil {
+		query = query.Where(passVotesSQL+" <= ?", *opts.MaxPass)
+	}
+
+	var total int64
+	if err := query.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	direction := "ASC"
+	if opts.Descending {
+		direction = "DESC"
+	}
+
+	var rows []PropertySummaryRow
+	err := query.
+		Select("properties.id, properties.address, properties.property_type, properties.year_built, " +
+			"properties.purchase_price, properties.intended_rent, properties.created_at, " +
+			"financial_metrics.cap_rate, financial_metrics.cash_on_cash_return, " +
+			passVotesSQL + " AS pass_votes, " + maybeVotesSQL + " AS maybe_votes, " + pursueVotesSQL + " AS pursue_votes, " +
+			"my_votes.decision AS my_vote").
+		Order(fmt.Sprintf("CASE WHEN %s IS NULL THEN 1 ELSE 0 END, %s %s, properties.id ASC", sortColumn, sortColumn, direction)).
+		Limit(opts.Limit).
+		Offset(opts.Offset).
+		Scan(&rows).Error
+	return rows, total, err
+}
@@ -0,0 +1,88 @@
+// Package repository holds the data access behind the services: one
+// interface per aggregate, with GORM implementations.
+package repository
+
+import (
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrNotFound is returned when a requested record does not exist
+	ErrNotFound = errors.New("record not found")
+
+	// ErrConflict is returned when a write would violate a uniqueness rule
+	ErrConflict = errors.New("record already exists")
+)
+
+// Store bundles the repositories the services use
+type Store struct {
+	Users         UserRepository
+	Profiles      AssumptionProfileRepository
+	Properties    PropertyRepository
+	Metrics       MetricsRepository
+	Valuations    ValuationRepository
+	Comps         CompRepository
+	RentComps     RentCompRepository
+	Scenarios     ScenarioRepository
+	Comments      CommentRepository
+	Votes         VoteRepository
+	Notifications NotificationRepository
+	Criteria      CriteriaRepository
+	Matches       MatchRepository
+	Search        SearchRepository
+
+	transaction func(fn func(*Store) error) error
+}
+
+// NewGormStore returns repositories backed by a GORM connection to
+// PostgreSQL or SQLite
+func NewGormStore(db *gorm.DB) *Store {
+	var search SearchRepository = &gormSearchRepository{db: db}
+	if db.Dialector.Name() == "sqlite" {
+		search = &sqliteSearchRepository{db: db}
+	}
+
+	store := &Store{
+		Users:         &gormUserRepository{db: db},
+		Profiles:      &gormAssumptionProfileRepository{db: db},
+		Properties:    &gormPropertyRepository{db: db},
+		Metrics:       &gormMetricsRepository{db: db},
+		Valuations:    &gormValuationRepository{db: db},
+		Comps:         &gormCompRepository{db: db},
+		RentComps:     &gormRentCompRepository{db: db},
+		Scenarios:     &gormScenarioRepository{db: db},
+		Comments:      &gormCommentRepository{db: db},
+		Votes:         &gormVoteRepository{db: db},
+		Notifications: &gormNotificationRepository{db: db},
+		Criteria:      &gormCriteriaRepository{db: db},
+		Matches:       &gormMatchRepository{db: db},
+		Search:        search,
+	}
+	store.transaction = func(fn func(*Store) error) error {
+		return db.Transaction(func(tx *gorm.DB) error {
+			return fn(NewGormStore(tx))
+		})
+	}
+	return store
+}
+
+// Transaction runs fn with repositories sharing one transaction, committed
+// when fn returns nil and rolled back otherwise. A store assembled without a
+// database, as in tests, runs fn on itself.
+func (s *Store) Transaction(fn func(*Store) error) error {
+	if s.transaction == nil {
+		return fn(s)
+	}
+	return s.transaction(fn)
+}
+
+// first loads one record into dest, translating a missing record to ErrNotFound
+func first(query *gorm.DB, dest interface{}, conds ...interface{}) error {
+	err := query.First(dest, conds...).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrNotFound
+	}
+	return err
+}
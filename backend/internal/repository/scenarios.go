@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"rental-property-mgmt/internal/models"
+)
+
+// ScenarioRepository stores what-if scenarios
+type ScenarioRepository interface {
+	// List returns a property's scenarios, oldest first, limited to ids when
+	// any are given
+	List(propertyID uuid.UUID, ids []uuid.UUID) ([]models.Scenario, error)
+	Get(id uuid.UUID) (*models.Scenario, error)
+	Create(scenario *models.Scenario) error
+	Save(scenario *models.Scenario) error
+	Delete(scenario *models.Scenario) error
+}
+
+type gormScenarioRepository struct {
+	db *gorm.DB
+}
+
+func (r *gormScenarioRepository) List(propertyID uuid.UUID, ids []uuid.UUID) ([]models.Scenario, error) {
+	query := r.db.Where("property_id = ?", propertyID)
+	if len(ids) > 0 {
+		query = query.Where("id IN ?", ids)
+	}
+
+	var scenarios []models.Scenario
+	err := query.Order("created_at ASC").Find(&scenarios).Error
+	return scenarios, err
+}
+
+func (r *gormScenarioRepository) Get(id uuid.UUID) (*models.Scenario, error) {
+	var scenario models.Scenario
+	if err := first(r.db, &scenario, "id = ?", id); err != nil {
+		return nil, err
+	}
+	return &scenario, nil
+}
+
+func (r *gormScenarioRepository) Create(scenario *models.Scenario) error {
+	return r.db.Omit(clause.Associations).Create(scenario).Error
+}
+
+func (r *gormScenarioRepository) Save(scenario *models.Scenario) error {
+	return r.db.Omit(clause.Associations).Save(scenario).Error
+}
+
+func (r *gormScenarioRepository) Delete(scenario *models.Scenario) error {
+	return r.db.Delete(scenario).Error
+}
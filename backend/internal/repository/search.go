@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Search hit types
+const (
+	SearchTypeProperty = "property"
+	SearchTypeComment  = "comment"
+)
+
+// Highlight markers around matching terms in search snippets. Private-use
+// characters never occur in stored text, so they survive HTML escaping and
+// can then be swapped for tags.
+const (
+	HighlightStart = "\ue000"
+	HighlightStop  = "\ue001"
+)
+
+// SearchQuery is a full-text search over properties and comments
+type SearchQuery struct {
+	// Text uses web search syntax: words, "quoted phrases", or and -excluded words
+	Text       string
+	Properties bool
+	Comments   bool
+	// OwnerID limits hits to one user's properties and their comments
+	OwnerID *uuid.UUID
+	Limit   int
+	Offset  int
+}
+
+// SearchHit is a matching property or comment. Snippet marks the matching
+// terms with HighlightStart and HighlightStop.
+type SearchHit struct {
+	Type       string    `json:"type"`
+	ID         uuid.UUID `json:"id"`
+	PropertyID uuid.UUID `json:"property_id"`
+	Address    string    `json:"address"`
+	Snippet    string    `json:"snippet"`
+	Rank       float64   `json:"rank"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// SearchRepository runs full-text searches
+type SearchRepository interface {
+	// Search returns a page of hits, best first, and the number of hits.
+	// Deleted comments are never matched.
+	Search(query SearchQuery) ([]SearchHit, int64, error)
+}
+
+// headlineOptions configures ts_headline snippets
+const headlineOptions = "StartSel=" + HighlightStart + ", StopSel=" + HighlightStop + ", MaxWords=30, MinWords=10, MaxFragments=2, FragmentDelimiter=\" … \""
+
+// searchSQL finds properties by address and local context notes, and comments
+// by content, ranked by relevance
+const searchSQL = `
+SELECT 'property' AS type, p.id, p.id AS property_id, p.address, p.created_at,
+	ts_rank(p.search_vector, q.query) AS rank,
+	ts_headline('english', p.address || ' — ' || coalesce(p.local_context->>'notes', ''), q.query, @options) AS snippet
+FROM properties p, websearch_to_tsquery('english', @query) AS q(query)
+WHERE p.search_vector @@ q.query AND @search_properties %[1]s
+UNION ALL
+SELECT 'comment' AS type, c.id, c.property_id, p.address, c.created_at,
+	ts_rank(c.search_vector, q.query) AS rank,
+	ts_headline('english', c.content, q.query, @options) AS snippet
+FROM comments c
+JOIN properties p ON p.id = c.property_id, websearch_to_tsquery('english', @query) AS q(query)
+WHERE c.search_vector @@ q.query AND c.deleted_at IS NULL AND @search_comments %[1]s
+`
+
+type gormSearchRepository struct {
+	db *gorm.DB
+}
+
+func (r *gormSearchRepository) Search(query SearchQuery) ([]SearchHit, int64, error) {
+	access := ""
+	if query.OwnerID != nil {
+		access = "AND p.user_id = @user_id"
+	}
+	hits := fmt.Sprintf(searchSQL, access)
+	args := map[string]interface{}{
+		"query":             query.Text,
+		"options":           headlineOptions,
+		"user_id":           query.OwnerID,
+		"search_properties": query.Properties,
+		"search_comments":   query.Comments,
+	}
+
+	var total int64
+	if err := r.db.Raw("SELECT COUNT(*) FROM ("+hits+") AS hits", args).Scan(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	args["limit"] = query.Limit
+	args["offset"] = query.Offset
+	results := []SearchHit{}
+	err := r.db.
+		Raw(hits+" ORDER BY rank DESC, created_at DESC, id ASC LIMIT @limit OFFSET @offset", args).
+		Scan(&results).Error
+	return results, total, err
+}
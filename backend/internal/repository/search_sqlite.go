@@ -0,0 +1,296 @@
+package repository
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"gorm.io/gorm"
+)
+
+// sqliteSearchSQL matches properties and comments with LIKE. SQLite has no
+// tsvector columns, so hits are ranked by how many search terms they contain,
+// with address terms weighing more than local context notes.
+const sqliteSearchSQL = `
+SELECT 'property' AS type, p.id, p.id AS property_id, p.address, p.created_at,
+	%[2]s AS rank,
+	p.address || ' — ' || coalesce(json_extract(p.local_context, '$.notes'), '') AS snippet
+FROM properties p
+WHERE %[1]s AND @search_properties %[5]s
+UNION ALL
+SELECT 'comment' AS type, c.id, c.property_id, p.address, c.created_at,
+	%[4]s AS rank,
+	c.content AS snippet
+FROM comments c
+JOIN properties p ON p.id = c.property_id
+WHERE %[3]s AND c.deleted_at IS NULL AND @search_comments %[5]s
+`
+
+// snippetRunes bounds the length of a SQLite search snippet
+const snippetRunes = 200
+
+type sqliteSearchRepository struct {
+	db *gorm.DB
+}
+
+func (r *sqliteSearchRepository) Search(query SearchQuery) ([]SearchHit, int64, error) {
+	terms := parseSearchText(query.Text)
+	if len(terms.groups) == 0 {
+		return []SearchHit{}, 0, nil
+	}
+
+	args := map[string]interface{}{
+		"user_id":           query.OwnerID,
+		"search_properties": query.Properties,
+		"search_comments":   query.Comments,
+	}
+	params := make(map[string]string)
+	param := func(term string) string {
+		if name, ok := params[term]; ok {
+			return name
+		}
+		name := fmt.Sprintf("term%d", len(params))
+		params[term] = name
+		args[name] = "%" + escapeLike(term) + "%"
+		return name
+	}
+
+	propertyText := "(p.address || ' ' || coalesce(json_extract(p.local_context, '$.notes'), ''))"
+	notesText := "coalesce(json_extract(p.local_context, '$.notes'), '')"
+	propertyRank := make([]string, 0, len(terms.ranked))
+	commentRank := make([]string, 0, len(terms.ranked))
+	for _, term := range terms.ranked {
+		like := " LIKE @" + param(term) + ` ESCAPE '\'`
+		propertyRank = append(propertyRank, "(p.address"+like+") + 0.4 * ("+notesText+like+")")
+		commentRank = append(commentRank, "(c.content"+like+")")
+	}
+
+	access := ""
+	if query.OwnerID != nil {
+		access = "AND p.user_id = @user_id"
+	}
+	hits := fmt.Sprintf(sqliteSearchSQL,
+		terms.condition(propertyText, param), strings.Join(propertyRank, " + "),
+		terms.condition("c.content", param), strings.Join(commentRank, " + "),
+		access)
+
+	var total int64
+	if err := r.db.Raw("SELECT COUNT(*) FROM ("+hits+") AS hits", args).Scan(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	args["limit"] = query.Limit
+	args["offset"] = query.Offset
+	results := []SearchHit{}
+	err := r.db.
+		Raw(hits+" ORDER BY rank DESC, created_at DESC, id ASC LIMIT @limit OFFSET @offset", args).
+		Scan(&results).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	for i := range results {
+		results[i].Snippet = highlightTerms(results[i].Snippet, terms.ranked)
+	}
+	return results, total, nil
+}
+
+// searchTerms is a parsed web search query: any one group matches when the
+// text contains all of its include terms and none of its exclude terms
+type searchTerms struct {
+	groups []searchGroup
+	// ranked lists every include term once, for ranking and highlighting
+	ranked []string
+}
+
+type searchGroup struct {
+	include []string
+	exclude []string
+}
+
+// condition renders the match for one text expression as SQL
+func (t searchTerms) condition(text string, param func(string) string) string {
+	groups := make([]string, 0, len(t.groups))
+	for _, group := range t.groups {
+		parts := make([]string, 0, len(group.include)+len(group.exclude))
+		for _, term := range group.include {
+			parts = append(parts, text+" LIKE @"+param(term)+` ESCAPE '\'`)
+		}
+		for _, term := range group.exclude {
+			parts = append(parts, text+" NOT LIKE @"+param(term)+` ESCAPE '\'`)
+		}
+		groups = append(groups, "("+strings.Join(parts, " AND ")+")")
+	}
+	return "(" + strings.Join(groups, " OR ") + ")"
+}
+
+// parseSearchText reads web search syntax the way websearch_to_tsquery does:
+// words, "quoted phrases", or between alternatives and -excluded terms. Words
+// are stemmed so "issues" finds "issue"; phrases match as written. A group
+// with nothing to include is dropped, as it would match everything.
+func parseSearchText(text string) searchTerms {
+	var terms searchTerms
+	seen := make(map[string]bool)
+	group := searchGroup{}
+	flush := func() {
+		if len(group.include) > 0 {
+			terms.groups = append(terms.groups, group)
+		}
+		group = searchGroup{}
+	}
+
+	for text = strings.TrimSpace(text); text != ""; text = strings.TrimLeftFunc(text, unicode.IsSpace) {
+		exclude := false
+		if strings.HasPrefix(text, "-") {
+			exclude = true
+			text = text[1:]
+		}
+
+		var term string
+		quoted := strings.HasPrefix(text, `"`)
+		if quoted {
+			end := strings.Index(text[1:], `"`)
+			if end < 0 {
+				term, text = text[1:], ""
+			} else {
+				term, text = text[1:end+1], text[end+2:]
+			}
+			term = strings.Join(strings.Fields(term), " ")
+		} else {
+			end := strings.IndexFunc(text, unicode.IsSpace)
+			if end < 0 {
+				end = len(text)
+			}
+			term, text = strings.Trim(text[:end], `"`), text[end:]
+		}
+		if term == "" {
+			continue
+		}
+
+		if !quoted {
+			if strings.EqualFold(term, "or") && !exclude {
+				flush()
+				continue
+			}
+			term = stem(term)
+		}
+
+		if exclude {
+			group.exclude = append(group.exclude, term)
+			continue
+		}
+		group.include = append(group.include, term)
+		if key := strings.ToLower(term); !seen[key] {
+			seen[key] = true
+			terms.ranked = append(terms.ranked, term)
+		}
+	}
+	flush()
+	return terms
+}
+
+// stem strips a common English suffix from a word, leaving at least three
+// letters; a rough stand-in for the stemming PostgreSQL search does
+func stem(word string) string {
+	lower := strings.ToLower(word)
+	for _, suffix := range []string{"ing", "ed", "es", "s"} {
+		if strings.HasSuffix(lower, suffix) && len(lower)-len(suffix) >= 3 {
+			return word[:len(word)-len(suffix)]
+		}
+	}
+	return word
+}
+
+// highlightTerms wraps each occurrence of terms in text, extended to the end
+// of the word, with HighlightStart and HighlightStop, trimming long text to a
+// window around the first match
+func highlightTerms(text string, terms []string) string {
+	// Lowercasing can change byte lengths outside ASCII; skip highlighting
+	// rather than cut a character in half
+	lower := strings.ToLower(text)
+	if len(lower) != len(text) {
+		terms = nil
+	}
+
+	type span struct{ start, end int }
+	var spans []span
+	for _, term := range terms {
+		needle := strings.ToLower(term)
+		for offset := 0; ; {
+			i := strings.Index(lower[offset:], needle)
+			if i < 0 {
+				break
+			}
+			start, end := offset+i, offset+i+len(needle)
+			for end < len(text) {
+				r, size := utf8.DecodeRuneInString(text[end:])
+				if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+					break
+				}
+				end += size
+			}
+			spans = append(spans, span{start, end})
+			offset = end
+		}
+	}
+
+	// Keep the earliest of overlapping matches
+	for i := 1; i < len(spans); i++ {
+		for j := i; j > 0 && spans[j].start < spans[j-1].start; j-- {
+			spans[j], spans[j-1] = spans[j-1], spans[j]
+		}
+	}
+	kept := spans[:0]
+	for _, s := range spans {
+		if len(kept) == 0 || s.start >= kept[len(kept)-1].end {
+			kept = append(kept, s)
+		}
+	}
+
+	from, to := 0, len(text)
+	if utf8.RuneCountInString(text) > snippetRunes {
+		if len(kept) > 0 {
+			from = backRunes(text, kept[0].start, snippetRunes/4)
+		}
+		to = forwardRunes(text, from, snippetRunes)
+	}
+
+	var b strings.Builder
+	if from > 0 {
+		b.WriteString("… ")
+	}
+	cursor := from
+	for _, s := range kept {
+		if s.start < from || s.end > to {
+			continue
+		}
+		b.WriteString(text[cursor:s.start])
+		b.WriteString(HighlightStart)
+		b.WriteString(text[s.start:s.end])
+		b.WriteString(HighlightStop)
+		cursor = s.end
+	}
+	b.WriteString(text[cursor:to])
+	if to < len(text) {
+		b.WriteString(" …")
+	}
+	return b.String()
+}
+
+// backRunes returns the byte offset n runes before i in text
+func backRunes(text string, i, n int) int {
+	for ; n > 0 && i > 0; n-- {
+		_, size := utf8.DecodeLastRuneInString(text[:i])
+		i -= size
+	}
+	return i
+}
+
+// forwardRunes returns the byte offset n runes after i in text
+func forwardRunes(text string, i, n int) int {
+	for ; n > 0 && i < len(text); n-- {
+		_, size := utf8.DecodeRuneInString(text[i:])
+		i += size
+	}
+	return i
+}
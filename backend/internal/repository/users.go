@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"strings"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"rental-property-mgmt/internal/models"
+)
+
+// UserRepository stores user accounts
+type UserRepository interface {
+	Create(user *models.User) error
+	Get(id uuid.UUID) (*models.User, error)
+	FindByEmail(email string) (*models.User, error)
+	EmailTaken(email string) (bool, error)
+	ListByIDs(ids []uuid.UUID) ([]models.User, error)
+	// MatchHandle finds up to limit users behind an @handle: a full email
+	// address, or an email local part when the handle has no @
+	MatchHandle(handle string, limit int) ([]models.User, error)
+}
+
+type gormUserRepository struct {
+	db *gorm.DB
+}
+
+func (r *gormUserRepository) Create(user *models.User) error {
+	return r.db.Omit(clause.Associations).Create(user).Error
+}
+
+func (r *gormUserRepository) Get(id uuid.UUID) (*models.User, error) {
+	var user models.User
+	if err := first(r.db, &user, "id = ?", id); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+func (r *gormUserRepository) FindByEmail(email string) (*models.User, error) {
+	var user models.User
+	if err := first(r.db.Where("email = ?", email), &user); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+func (r *gormUserRepository) EmailTaken(email string) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.User{}).Where("email = ?", email).Count(&count).Error
+	return count > 0, err
+}
+
+func (r *gormUserRepository) ListByIDs(ids []uuid.UUID) ([]models.User, error) {
+	var users []models.User
+	err := r.db.Where("id IN ?", ids).Find(&users).Error
+	return users, err
+}
+
+func (r *gormUserRepository) MatchHandle(handle string, limit int) ([]models.User, error) {
+	query := r.db.Limit(limit)
+	if strings.Contains(handle, "@") {
+		query = query.Where("LOWER(email) = ?", handle)
+	} else {
+		query = query.Where(`LOWER(email) LIKE ? ESCAPE '\'`, escapeLike(handle)+"@%")
+	}
+
+	var users []models.User
+	err := query.Find(&users).Error
+	return users, err
+}
+
+// escapeLike escapes LIKE wildcards in a user-supplied pattern fragment
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(value)
+}
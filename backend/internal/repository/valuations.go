@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"rental-property-mgmt/internal/models"
+)
+
+// ValuationRepository stores third-party valuations of properties
+type ValuationRepository interface {
+	Create(valuation *models.PropertyValuation) error
+	// List returns a property's valuations, newest first, of one type or of
+	// every type when valuationType is empty
+	List(propertyID uuid.UUID, valuationType string) ([]models.PropertyValuation, error)
+	// ListForProperties returns the valuations of several properties
+	ListForProperties(propertyIDs []uuid.UUID) ([]models.PropertyValuation, error)
+	// Exists reports whether a source already valued a property on a date
+	Exists(propertyID uuid.UUID, source, valuationType string, date time.Time) (bool, error)
+}
+
+type gormValuationRepository struct {
+	db *gorm.DB
+}
+
+func (r *gormValuationRepository) Create(valuation *models.PropertyValuation) error {
+	return r.db.Omit(clause.Associations).Create(valuation).Error
+}
+
+func (r *gormValuationRepository) List(propertyID uuid.UUID, valuationType string) ([]models.PropertyValuation, error) {
+	query := r.db.Where("property_id = ?", propertyID)
+	if valuationType != "" {
+		query = query.Where("valuation_type = ?", valuationType)
+	}
+
+	valuations := []models.PropertyValuation{}
+	err := query.Order("valuation_date DESC, created_at DESC").Find(&valuations).Error
+	return valuations, err
+}
+
+func (r *gormValuationRepository) ListForProperties(propertyIDs []uuid.UUID) ([]models.PropertyValuation, error) {
+	var valuations []models.PropertyValuation
+	err := r.db.Where("property_id IN ?", propertyIDs).Find(&valuations).Error
+	return valuations, err
+}
+
+func (r *gormValuationRepository) Exists(propertyID uuid.UUID, source, valuationType string, date time.Time) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.PropertyValuation{}).
+		Where("property_id = ? AND source = ? AND valuation_type = ? AND valuation_date = ?",
+			propertyID, source, valuationType, date).
+		Count(&count).Error
+	return count > 0, err
+}
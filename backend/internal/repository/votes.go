@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"rental-property-mgmt/internal/models"
+)
+
+// VoteRepository stores team members' decision votes on properties
+type VoteRepository interface {
+	// Upsert records a vote, replacing the user's earlier vote on the property
+	Upsert(vote *models.PropertyVote) error
+	// Delete reports whether the user had voted on the property
+	Delete(propertyID, userID uuid.UUID) (bool, error)
+	// List returns a property's votes, most recent first, with their voters
+	List(propertyID uuid.UUID) ([]models.PropertyVote, error)
+}
+
+type gormVoteRepository struct {
+	db *gorm.DB
+}
+
+func (r *gormVoteRepository) Upsert(vote *models.PropertyVote) error {
+	return r.db.
+		Omit(clause.Associations).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "property_id"}, {Name: "user_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"decision", "updated_at"}),
+		}).
+		Create(vote).Error
+}
+
+func (r *gormVoteRepository) Delete(propertyID, userID uuid.UUID) (bool, error) {
+	result := r.db.
+		Where("property_id = ? AND user_id = ?", propertyID, userID).
+		Delete(&models.PropertyVote{})
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *gormVoteRepository) List(propertyID uuid.UUID) ([]models.PropertyVote, error) {
+	votes := []models.PropertyVote{}
+	err := r.db.
+		Preload("User").
+		Where("property_id = ?", propertyID).
+		Order("updated_at DESC, id ASC").
+		Find(&votes).Error
+	return votes, err
+}
@@ -0,0 +1,164 @@
+package services
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"rental-property-mgmt/internal/models"
+	"rental-property-mgmt/internal/notifications"
+	"rental-property-mgmt/internal/repository"
+)
+
+// EventCriteriaMatch is the notification event sent for a new criteria match
+const EventCriteriaMatch = "criteria.match"
+
+// AlertService records buying-box matches and notifies the criteria owners,
+// in their notifications inbox and through the configured notifiers. It runs
+// as a PropertyHook whenever a property is created or updated.
+type AlertService struct {
+	store     *repository.Store
+	notifiers []notifications.Notifier
+}
+
+// NewAlertService creates an alert service delivering through the notifiers
+// configured in the environment; the in-app inbox is always on
+func NewAlertService(store *repository.Store) *AlertService {
+	return &AlertService{store: store, notifiers: notifications.FromEnv()}
+}
+
+// PropertySaved implements PropertyHook. It evaluates the property against every
+// active criteria set, records new matches and notifies their owners. A property
+// that already matched a criteria set is not reported again.
+func (as *AlertService) PropertySaved(property *models.Property) error {
+	matches, err := as.RecordMatches(property)
+	if err != nil {
+		return err
+	}
+	as.notify(property, matches)
+	return nil
+}
+
+// RecordMatches stores a match, with a notification for its owner, for each
+// active criteria set the property meets and returns the matches that are new
+func (as *AlertService) RecordMatches(property *models.Property) ([]models.CriteriaMatch, error) {
+	active, err := as.store.Criteria.ListActive()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load active criteria: %w", err)
+	}
+
+	var created []models.CriteriaMatch
+	err = as.store.Transaction(func(tx *repository.Store) error {
+		for i := range active {
+			criteria := &active[i]
+			comparison := criteria.CompareProperty(property, property.FinancialMetrics)
+			if !comparison.MeetsCriteria {
+				continue
+			}
+
+			match := models.CriteriaMatch{
+				CriteriaID:      criteria.ID,
+				PropertyID:      property.ID,
+				UserID:          criteria.UserID,
+				CriteriaVersion: comparison.CriteriaVersion,
+				Score:           comparison.Score,
+				Completeness:    comparison.Completeness,
+			}
+			isNew, err := tx.Matches.Create(&match)
+			if err != nil {
+				return fmt.Errorf("failed to record criteria match: %w", err)
+			}
+			if !isNew {
+				continue // Already matched before
+			}
+
+			notification := models.Notification{
+				UserID:     match.UserID,
+				Type:       models.NotificationMatch,
+				MatchID:    &match.ID,
+				PropertyID: match.PropertyID,
+			}
+			if err := tx.Notifications.Create(&notification); err != nil {
+				return fmt.Errorf("failed to create match notification: %w", err)
+			}
+
+			match.Criteria = criteria
+			created = append(created, match)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+// notify hands new matches to the configured notifiers. Messages are built
+// here but sent in the background; failures are logged, and the in-app feed
+// already holds the notification.
+func (as *AlertService) notify(property *models.Property, matches []models.CriteriaMatch) {
+	if len(as.notifiers) == 0 || len(matches) == 0 {
+		return
+	}
+
+	userIDs := make([]uuid.UUID, 0, len(matches))
+	for _, match := range matches {
+		userIDs = append(userIDs, match.UserID)
+	}
+	users, err := as.store.Users.ListByIDs(userIDs)
+	if err != nil {
+		log.Printf("alerts: failed to load recipients: %v", err)
+		return
+	}
+	emails := make(map[uuid.UUID]string, len(users))
+	for _, user := range users {
+		emails[user.ID] = user.Email
+	}
+
+	msgs := make([]notifications.Message, 0, len(matches))
+	for _, match := range matches {
+		msgs = append(msgs, MatchMessage(property, match, emails[match.UserID]))
+	}
+	notifications.Deliver("alerts", as.notifiers, msgs)
+}
+
+// MatchMessage builds the notification for a new criteria match
+func MatchMessage(property *models.Property, match models.CriteriaMatch, email string) notifications.Message {
+	criteriaName := "your buying criteria"
+	if match.Criteria != nil {
+		criteriaName = fmt.Sprintf("%q", match.Criteria.Name)
+	}
+
+	var body strings.Builder
+	fmt.Fprintf(&body, "%s matches %s.\n\n", property.Address, criteriaName)
+	fmt.Fprintf(&body, "Purchase price: $%.2f\n", property.PurchasePrice)
+	if metrics := property.FinancialMetrics; metrics != nil {
+		if metrics.CapRate != nil {
+			fmt.Fprintf(&body, "Cap rate: %.2f%%\n", *metrics.CapRate)
+		}
+		if metrics.CashOnCashReturn != nil {
+			fmt.Fprintf(&body, "Cash-on-cash return: %.2f%%\n", *metrics.CashOnCashReturn)
+		}
+	}
+	fmt.Fprintf(&body, "Score: %.0f\n", match.Score)
+
+	var to []string
+	if email != "" {
+		to = []string{email}
+	}
+	return notifications.Message{
+		To:      to,
+		Subject: "New property match: " + property.Address,
+		Body:    body.String(),
+		Event:   EventCriteriaMatch,
+		Data: map[string]interface{}{
+			"match_id":    match.ID,
+			"criteria_id": match.CriteriaID,
+			"property_id": match.PropertyID,
+			"user_id":     match.UserID,
+			"score":       match.Score,
+		},
+	}
+}
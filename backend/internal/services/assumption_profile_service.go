@@ -0,0 +1,128 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"rental-property-mgmt/internal/models"
+	"rental-property-mgmt/internal/repository"
+)
+
+// AssumptionProfileService manages reusable market assumption profiles.
+// A user sees their own profiles plus every team-scoped profile.
+type AssumptionProfileService struct {
+	store *repository.Store
+}
+
+// NewAssumptionProfileService creates a new assumption profile service
+func NewAssumptionProfileService(store *repository.Store) *AssumptionProfileService {
+	return &AssumptionProfileService{store: store}
+}
+
+// List returns all profiles visible to the user
+func (aps *AssumptionProfileService) List(userID uuid.UUID) ([]models.AssumptionProfile, error) {
+	profiles, err := aps.store.Profiles.ListVisible(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list assumption profiles: %w", err)
+	}
+	return profiles, nil
+}
+
+// Get returns a single profile visible to the user
+func (aps *AssumptionProfileService) Get(userID, id uuid.UUID) (*models.AssumptionProfile, error) {
+	profile, err := aps.store.Profiles.GetVisible(userID, id)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to load assumption profile: %w", err)
+	}
+	return profile, nil
+}
+
+// Create stores a new profile owned by the user
+func (aps *AssumptionProfileService) Create(userID uuid.UUID, profile *models.AssumptionProfile) error {
+	profile.ID = uuid.Nil
+	profile.UserID = userID
+	if err := aps.store.Profiles.Create(profile); err != nil {
+		return fmt.Errorf("failed to create assumption profile: %w", err)
+	}
+	return nil
+}
+
+// Update replaces the editable fields of a profile owned by the user
+func (aps *AssumptionProfileService) Update(userID, id uuid.UUID, changes *models.AssumptionProfile) (*models.AssumptionProfile, error) {
+	profile, err := aps.Get(userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if profile.UserID != userID {
+		return nil, ErrForbidden
+	}
+
+	changes.ID = profile.ID
+	changes.UserID = profile.UserID
+	changes.CreatedAt = profile.CreatedAt
+	if changes.Scope == "" {
+		changes.Scope = profile.Scope
+	}
+
+	if err := aps.store.Profiles.Save(changes); err != nil {
+		return nil, fmt.Errorf("failed to update assumption profile: %w", err)
+	}
+	return changes, nil
+}
+
+// Delete removes a profile owned by the user
+func (aps *AssumptionProfileService) Delete(userID, id uuid.UUID) error {
+	profile, err := aps.Get(userID, id)
+	if err != nil {
+		return err
+	}
+	if profile.UserID != userID {
+		return ErrForbidden
+	}
+
+	if err := aps.store.Profiles.Delete(profile); err != nil {
+		return fmt.Errorf("failed to delete assumption profile: %w", err)
+	}
+	return nil
+}
+
+// FindBestMatch returns the most specific profile for a location, or nil if none applies.
+// ZIP matches beat metro matches, which beat location-less defaults; on a tie the
+// user's own profiles win over team profiles.
+func (aps *AssumptionProfileService) FindBestMatch(userID uuid.UUID, zipCode, metro string) (*models.AssumptionProfile, error) {
+	profiles, err := aps.List(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var best *models.AssumptionProfile
+	bestRank := 0
+	for i := range profiles {
+		candidate := &profiles[i]
+		rank := candidate.MatchRank(zipCode, metro)
+		if rank == 0 {
+			continue
+		}
+		if rank > bestRank || (rank == bestRank && isPreferredProfile(userID, candidate, best)) {
+			best = candidate
+			bestRank = rank
+		}
+	}
+
+	return best, nil
+}
+
+// isPreferredProfile breaks ties between equally specific profiles
+func isPreferredProfile(userID uuid.UUID, candidate, current *models.AssumptionProfile) bool {
+	candidateOwn := candidate.UserID == userID
+	currentOwn := current.UserID == userID
+	if candidateOwn != currentOwn {
+		return candidateOwn
+	}
+	return candidate.UpdatedAt.After(current.UpdatedAt)
+}
@@ -0,0 +1,183 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"rental-property-mgmt/internal/models"
+	"rental-property-mgmt/internal/repository"
+)
+
+// BuyingCriteriaService manages buying-box criteria. Criteria are private to
+// the user who defined them. Every change to a criteria definition is recorded
+// as a new immutable version.
+type BuyingCriteriaService struct {
+	store *repository.Store
+}
+
+// NewBuyingCriteriaService creates a new buying criteria service
+func NewBuyingCriteriaService(store *repository.Store) *BuyingCriteriaService {
+	return &BuyingCriteriaService{store: store}
+}
+
+// Get returns a criteria set owned by the user
+func (bcs *BuyingCriteriaService) Get(userID, id uuid.UUID) (*models.BuyingBoxCriteria, error) {
+	criteria, err := bcs.store.Criteria.GetForUser(userID, id)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to load buying criteria: %w", err)
+	}
+	return criteria, nil
+}
+
+// ListActive returns the user's active criteria sets
+func (bcs *BuyingCriteriaService) ListActive(userID uuid.UUID) ([]models.BuyingBoxCriteria, error) {
+	criteria, err := bcs.store.Criteria.List(userID, true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list buying criteria: %w", err)
+	}
+	return criteria, nil
+}
+
+// List returns all of the user's criteria sets
+func (bcs *BuyingCriteriaService) List(userID uuid.UUID) ([]models.BuyingBoxCriteria, error) {
+	criteria, err := bcs.store.Criteria.List(userID, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list buying criteria: %w", err)
+	}
+	return criteria, nil
+}
+
+// Create stores a new criteria set owned by the user along with its first version
+func (bcs *BuyingCriteriaService) Create(userID uuid.UUID, criteria *models.BuyingBoxCriteria) error {
+	criteria.ID = uuid.Nil
+	criteria.UserID = userID
+	criteria.Version = 1
+	criteria.MissingDataPolicy = criteria.GetMissingDataPolicy()
+
+	return bcs.store.Transaction(func(tx *repository.Store) error {
+		if err := tx.Criteria.Create(criteria); err != nil {
+			return fmt.Errorf("failed to create buying criteria: %w", err)
+		}
+		if err := tx.Criteria.CreateVersion(models.NewCriteriaVersion(criteria, userID)); err != nil {
+			return fmt.Errorf("failed to record criteria version: %w", err)
+		}
+		return nil
+	})
+}
+
+// Update replaces the definition of a criteria set owned by the user. A new
+// version is recorded only when the definition actually changes; isActive,
+// when set, toggles activation without creating a version. The criteria row
+// stays locked from reading the current version to recording the next.
+func (bcs *BuyingCriteriaService) Update(userID, id uuid.UUID, changes *models.BuyingBoxCriteria, isActive *bool) (*models.BuyingBoxCriteria, error) {
+	err := bcs.store.Transaction(func(tx *repository.Store) error {
+		criteria, err := tx.Criteria.GetForUpdate(userID, id)
+		if err != nil {
+			if errors.Is(err, ErrNotFound) {
+				return ErrNotFound
+			}
+			return fmt.Errorf("failed to load buying criteria: %w", err)
+		}
+
+		changes.ID = criteria.ID
+		changes.UserID = criteria.UserID
+		changes.CreatedAt = criteria.CreatedAt
+		changes.IsActive = criteria.IsActive
+		changes.Version = criteria.Version
+		changes.MissingDataPolicy = changes.GetMissingDataPolicy()
+		if isActive != nil {
+			if *isActive {
+				changes.Activate()
+			} else {
+				changes.Deactivate()
+			}
+		}
+
+		changed := !criteria.SameDefinition(changes)
+		if changed {
+			changes.Version++
+		}
+
+		if err := tx.Criteria.Save(changes); err != nil {
+			return fmt.Errorf("failed to update buying criteria: %w", err)
+		}
+		if !changed {
+			return nil
+		}
+		if err := tx.Criteria.CreateVersion(models.NewCriteriaVersion(changes, userID)); err != nil {
+			if errors.Is(err, ErrConflict) {
+				return fmt.Errorf("%w: criteria changed while saving, reload and try again", ErrConflict)
+			}
+			return fmt.Errorf("failed to record criteria version: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
+
+// SetActive activates or deactivates a criteria set owned by the user
+func (bcs *BuyingCriteriaService) SetActive(userID, id uuid.UUID, active bool) (*models.BuyingBoxCriteria, error) {
+	criteria, err := bcs.Get(userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if active {
+		criteria.Activate()
+	} else {
+		criteria.Deactivate()
+	}
+	if err := bcs.store.Criteria.SetActive(criteria); err != nil {
+		return nil, fmt.Errorf("failed to update buying criteria: %w", err)
+	}
+	return criteria, nil
+}
+
+// Delete removes a criteria set owned by the user, with its versions and matches
+func (bcs *BuyingCriteriaService) Delete(userID, id uuid.UUID) error {
+	criteria, err := bcs.Get(userID, id)
+	if err != nil {
+		return err
+	}
+	if err := bcs.store.Criteria.Delete(criteria); err != nil {
+		return fmt.Errorf("failed to delete buying criteria: %w", err)
+	}
+	return nil
+}
+
+// Versions returns the version history of a criteria set owned by the user, newest first
+func (bcs *BuyingCriteriaService) Versions(userID, id uuid.UUID) ([]models.BuyingBoxCriteriaVersion, error) {
+	if _, err := bcs.Get(userID, id); err != nil {
+		return nil, err
+	}
+
+	versions, err := bcs.store.Criteria.Versions(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list criteria versions: %w", err)
+	}
+	return versions, nil
+}
+
+// GetVersion returns one version of a criteria set owned by the user
+func (bcs *BuyingCriteriaService) GetVersion(userID, id uuid.UUID, version int) (*models.BuyingBoxCriteriaVersion, error) {
+	if _, err := bcs.Get(userID, id); err != nil {
+		return nil, err
+	}
+
+	snapshot, err := bcs.store.Criteria.GetVersion(id, version)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to load criteria version: %w", err)
+	}
+	return snapshot, nil
+}
@@ -141,14 +141,15 @@ func (cs *CalculationService) calculateCapRate(noi, purchasePrice float64) float
 }
 
 // calculateCashToClose calculates total cash needed to close
-// Cash to Close = Down Payment + Closing Costs
+// Cash to Close = Down Payment + Closing Costs - Seller Credit
 func (cs *CalculationService) calculateCashToClose(property *models.Property) float64 {
 	downPaymentPercent := property.GetFinancingTerm("down_payment_percent")
 	closingCosts := property.GetFinancingTerm("closing_costs")
+	sellerCredit := property.GetFinancingTerm("seller_credit")
 
 	downPaymentAmount := property.PurchasePrice * (downPaymentPercent / 100)
 
-	return downPaymentAmount + closingCosts
+	return downPaymentAmount + closingCosts - sellerCredit
 }
 
 // calculateCashOnCashReturn calculates Cash-on-Cash Return
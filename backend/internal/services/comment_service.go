@@ -0,0 +1,436 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"rental-property-mgmt/internal/models"
+	"rental-property-mgmt/internal/repository"
+)
+
+// defaultCommentMaxDepth is the deepest reply level allowed when COMMENT_MAX_DEPTH is unset
+const defaultCommentMaxDepth = 5
+
+// CommentService manages threaded property comments. Every team member may
+// read and comment on any property; only authors edit or delete their comments.
+// Deleted comments stay in their threads as "[deleted]" placeholders, and every
+// edit keeps the prior content as a revision.
+type CommentService struct {
+	store         *repository.Store
+	maxDepth      int
+	notifications *NotificationService
+}
+
+// NewCommentService creates a comment service limited to COMMENT_MAX_DEPTH reply levels
+func NewCommentService(store *repository.Store) *CommentService {
+	maxDepth := defaultCommentMaxDepth
+	if value, err := strconv.Atoi(os.Getenv("COMMENT_MAX_DEPTH")); err == nil && value >= 0 {
+		maxDepth = value
+	}
+	return &CommentService{
+		store:         store,
+		maxDepth:      maxDepth,
+		notifications: NewNotificationService(store),
+	}
+}
+
+// MaxDepth returns the deepest reply level allowed
+func (cs *CommentService) MaxDepth() int {
+	return cs.maxDepth
+}
+
+// CommentThreads is one page of top-level comments with their reply trees
+type CommentThreads struct {
+	Comments []models.Comment `json:"comments"`
+	Total    int64            `json:"total"`
+	Limit    int              `json:"limit"`
+	Offset   int              `json:"offset"`
+	MaxDepth int              `json:"max_depth"`
+}
+
+// ListThreads returns a page of the property's top-level comments, oldest first,
+// each with its replies nested up to depth levels
+func (cs *CommentService) ListThreads(propertyID uuid.UUID, limit, offset, depth int) (*CommentThreads, error) {
+	if err := cs.ensureProperty(propertyID); err != nil {
+		return nil, err
+	}
+
+	threadIDs, total, err := cs.store.Comments.ThreadIDs(propertyID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list comments: %w", err)
+	}
+
+	threads := &CommentThreads{Comments: []models.Comment{}, Total: total, Limit: limit, Offset: offset, MaxDepth: depth}
+	if len(threadIDs) == 0 {
+		return threads, nil
+	}
+
+	comments, err := cs.loadThreads(threadIDs, depth)
+	if err != nil {
+		return nil, err
+	}
+	threads.Comments = models.BuildCommentTrees(comments, depth)
+	return threads, nil
+}
+
+// Get returns a comment with its replies nested up to depth levels
+func (cs *CommentService) Get(id uuid.UUID, depth int) (*models.Comment, error) {
+	comment, err := cs.find(id)
+	if err != nil {
+		return nil, err
+	}
+
+	thread, err := cs.loadThreads([]uuid.UUID{comment.ThreadID}, comment.Depth+depth)
+	if err != nil {
+		return nil, err
+	}
+
+	// Keep only the comment and its descendants
+	children := make(map[uuid.UUID][]models.Comment)
+	for _, c := range thread {
+		if c.ParentID != nil {
+			children[*c.ParentID] = append(children[*c.ParentID], c)
+		}
+	}
+	var subtree []models.Comment
+	queue := []uuid.UUID{comment.ID}
+	for _, c := range thread {
+		if c.ID == comment.ID {
+			subtree = append(subtree, c)
+		}
+	}
+	for len(queue) > 0 {
+		parentID := queue[0]
+		queue = queue[1:]
+		for _, child := range children[parentID] {
+			subtree = append(subtree, child)
+			queue = append(queue, child.ID)
+		}
+	}
+
+	trees := models.BuildCommentTrees(subtree, depth)
+	if len(trees) == 0 {
+		return nil, ErrNotFound
+	}
+	return &trees[0], nil
+}
+
+// Create adds a comment to the property, or a reply when parentID is set.
+// The parent must belong to the same property and replies may not nest deeper
+// than the configured maximum. Users @mentioned in the content and the author
+// of the parent comment are notified.
+func (cs *CommentService) Create(userID, propertyID uuid.UUID, content string, parentID *uuid.UUID) (*models.Comment, error) {
+	property, err := cs.loadProperty(propertyID)
+	if err != nil {
+		return nil, err
+	}
+
+	comment := &models.Comment{
+		PropertyID: propertyID,
+		UserID:     userID,
+		Content:    content,
+	}
+
+	var parent *models.Comment
+	if parentID != nil {
+		parent, err = cs.find(*parentID)
+		if errors.Is(err, ErrNotFound) {
+			return nil, fmt.Errorf("%w: parent comment not found", ErrInvalidInput)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if parent.PropertyID != propertyID {
+			return nil, fmt.Errorf("%w: parent comment belongs to a different property", ErrInvalidInput)
+		}
+		if parent.IsDeleted() {
+			return nil, fmt.Errorf("%w: cannot reply to a deleted comment", ErrInvalidInput)
+		}
+		if parent.Depth >= cs.maxDepth {
+			return nil, fmt.Errorf("%w: replies cannot nest more than %d levels deep", ErrInvalidInput, cs.maxDepth)
+		}
+		comment.ReplyTo(parent)
+	}
+
+	var created []models.Notification
+	err = cs.store.Transaction(func(tx *repository.Store) error {
+		mentioned, err := cs.notifications.ResolveMentions(tx, models.ParseMentions(content), property)
+		if err != nil {
+			return err
+		}
+		if err := tx.Comments.Create(comment); err != nil {
+			return fmt.Errorf("failed to create comment: %w", err)
+		}
+		added, err := cs.notifications.SyncMentions(tx, comment, mentioned)
+		if err != nil {
+			return err
+		}
+		created, err = cs.notifications.CommentPosted(tx, comment, parent, added)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	comment, err = cs.withAuthor(comment)
+	if err != nil {
+		return nil, err
+	}
+	cs.notifications.Deliver(comment, created)
+	return comment, nil
+}
+
+// Update changes the content of a comment written by the user, keeping the
+// previous content as a revision. Only users newly @mentioned by the edit are
+// notified.
+func (cs *CommentService) Update(userID, id uuid.UUID, content string) (*models.Comment, error) {
+	comment, err := cs.find(id)
+	if err != nil {
+		return nil, err
+	}
+	if comment.UserID != userID {
+		return nil, ErrForbidden
+	}
+	if comment.IsDeleted() {
+		return nil, fmt.Errorf("%w: comment has been deleted", ErrConflict)
+	}
+	property, err := cs.loadProperty(comment.PropertyID)
+	if err != nil {
+		return nil, err
+	}
+
+	revision := models.NewCommentRevision(comment)
+	comment.Content = content
+	var created []models.Notification
+	err = cs.store.Transaction(func(tx *repository.Store) error {
+		mentioned, err := cs.notifications.ResolveMentions(tx, models.ParseMentions(content), property)
+		if err != nil {
+			return err
+		}
+		if strings.TrimSpace(content) != revision.Content {
+			if err := tx.Comments.CreateRevision(revision); err != nil {
+				return fmt.Errorf("failed to save comment revision: %w", err)
+			}
+		}
+		if err := tx.Comments.Save(comment); err != nil {
+			return fmt.Errorf("failed to update comment: %w", err)
+		}
+		added, err := cs.notifications.SyncMentions(tx, comment, mentioned)
+		if err != nil {
+			return err
+		}
+		created, err = cs.notifications.CommentPosted(tx, comment, nil, added)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	comment, err = cs.withAuthor(comment)
+	if err != nil {
+		return nil, err
+	}
+	cs.notifications.Deliver(comment, created)
+	return comment, nil
+}
+
+// Delete soft-deletes a comment written by the user. The comment keeps its
+// place in the thread with placeholder content so its replies survive; the
+// final content is kept as a revision. Deleting a deleted comment is a no-op.
+func (cs *CommentService) Delete(userID, id uuid.UUID) error {
+	comment, err := cs.find(id)
+	if err != nil {
+		return err
+	}
+	if comment.UserID != userID {
+		return ErrForbidden
+	}
+	if comment.IsDeleted() {
+		return nil
+	}
+
+	return cs.store.Transaction(func(tx *repository.Store) error {
+		if err := tx.Comments.CreateRevision(models.NewCommentRevision(comment)); err != nil {
+			return fmt.Errorf("failed to save comment revision: %w", err)
+		}
+		if err := tx.Notifications.DeleteMentions(comment.ID, nil); err != nil {
+			return fmt.Errorf("failed to remove mentions: %w", err)
+		}
+
+		now := time.Now()
+		comment.Content = models.DeletedCommentPlaceholder
+		comment.DeletedAt = &now
+		if err := tx.Comments.Save(comment); err != nil {
+			return fmt.Errorf("failed to delete comment: %w", err)
+		}
+		return nil
+	})
+}
+
+// CommentHistory lists the prior versions of a comment's content
+type CommentHistory struct {
+	CommentID uuid.UUID                `json:"comment_id"`
+	Content   string                   `json:"content"`
+	DeletedAt *time.Time               `json:"deleted_at,omitempty"`
+	Revisions []models.CommentRevision `json:"revisions"`
+}
+
+// History returns the comment's prior versions, oldest first. Only the comment
+// author and the owner of the property may view it.
+func (cs *CommentService) History(userID, id uuid.UUID) (*CommentHistory, error) {
+	comment, err := cs.find(id)
+	if err != nil {
+		return nil, err
+	}
+	if comment.UserID != userID {
+		property, err := cs.loadProperty(comment.PropertyID)
+		if err != nil {
+			return nil, err
+		}
+		if property.UserID != userID {
+			return nil, ErrForbidden
+		}
+	}
+
+	revisions, err := cs.store.Comments.Revisions(comment.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load comment history: %w", err)
+	}
+	return &CommentHistory{
+		CommentID: comment.ID,
+		Content:   comment.Content,
+		DeletedAt: comment.DeletedAt,
+		Revisions: revisions,
+	}, nil
+}
+
+// find loads a single comment
+func (cs *CommentService) find(id uuid.UUID) (*models.Comment, error) {
+	comment, err := cs.store.Comments.Get(id)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to load comment: %w", err)
+	}
+	return comment, nil
+}
+
+// loadThreads loads every comment in the given threads down to maxDepth, with
+// author names and rendered content
+func (cs *CommentService) loadThreads(threadIDs []uuid.UUID, maxDepth int) ([]models.Comment, error) {
+	comments, err := cs.store.Comments.ListThreads(threadIDs, maxDepth+1)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load comment threads: %w", err)
+	}
+	for i := range comments {
+		setUserName(&comments[i])
+	}
+	if err := cs.renderComments(comments); err != nil {
+		return nil, err
+	}
+	return comments, nil
+}
+
+// withAuthor fills in the author name and rendered content of a freshly saved comment
+func (cs *CommentService) withAuthor(comment *models.Comment) (*models.Comment, error) {
+	user, err := cs.store.Users.Get(comment.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load comment author: %w", err)
+	}
+	comment.User = user
+	setUserName(comment)
+	comment.Replies = []models.Comment{}
+
+	rendered := []models.Comment{*comment}
+	if err := cs.renderComments(rendered); err != nil {
+		return nil, err
+	}
+	return &rendered[0], nil
+}
+
+// loadProperty loads the property a comment is posted on
+func (cs *CommentService) loadProperty(propertyID uuid.UUID) (*models.Property, error) {
+	property, err := cs.store.Properties.Get(propertyID)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to load property: %w", err)
+	}
+	return property, nil
+}
+
+// ensureProperty returns ErrNotFound when the property does not exist
+func (cs *CommentService) ensureProperty(propertyID uuid.UUID) error {
+	exists, err := cs.store.Properties.Exists(propertyID)
+	if err != nil {
+		return fmt.Errorf("failed to load property: %w", err)
+	}
+	if !exists {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// renderComments converts the comments' Markdown to sanitized HTML, expands
+// links to other properties into property cards and attaches emoji reactions.
+// Links to properties that do not exist are left as plain links.
+func (cs *CommentService) renderComments(comments []models.Comment) error {
+	if len(comments) == 0 {
+		return nil
+	}
+
+	var propertyIDs []uuid.UUID
+	commentIDs := make([]uuid.UUID, len(comments))
+	linked := make([][]uuid.UUID, len(comments))
+	for i := range comments {
+		comments[i].RenderContent()
+		commentIDs[i] = comments[i].ID
+		linked[i] = comments[i].LinkedPropertyIDs()
+		propertyIDs = append(propertyIDs, linked[i]...)
+	}
+
+	reactions, err := loadReactions(cs.store.Comments, commentIDs)
+	if err != nil {
+		return err
+	}
+	for i := range comments {
+		comments[i].Reactions = models.SummarizeReactions(reactions[comments[i].ID])
+	}
+	if len(propertyIDs) == 0 {
+		return nil
+	}
+
+	properties, err := cs.store.Properties.ListByIDs(propertyIDs)
+	if err != nil {
+		return fmt.Errorf("failed to load linked properties: %w", err)
+	}
+	cards := make(map[uuid.UUID]models.PropertyCard, len(properties))
+	for i := range properties {
+		cards[properties[i].ID] = models.NewPropertyCard(&properties[i])
+	}
+
+	for i := range comments {
+		for _, id := range linked[i] {
+			if card, ok := cards[id]; ok {
+				comments[i].PropertyCards = append(comments[i].PropertyCards, card)
+			}
+		}
+	}
+	return nil
+}
+
+// setUserName copies the preloaded author's display name onto the comment.
+// Deleted comments stay anonymous.
+func setUserName(comment *models.Comment) {
+	if comment.User != nil && !comment.IsDeleted() {
+		comment.UserName = comment.User.FirstName + " " + comment.User.LastName
+	}
+}
@@ -0,0 +1,166 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"rental-property-mgmt/internal/models"
+	"rental-property-mgmt/internal/repository"
+)
+
+// CompService manages a property's comparable sales and values the property
+// from them. Every team member can read comps; only the property owner
+// records them or stores the resulting valuation.
+type CompService struct {
+	store      *repository.Store
+	properties *PropertyService
+	valuations *ValuationService
+}
+
+// NewCompService creates a new comp service
+func NewCompService(store *repository.Store) *CompService {
+	return &CompService{
+		store:      store,
+		properties: NewPropertyService(store),
+		valuations: NewValuationService(store),
+	}
+}
+
+// List returns a property's comps, nearest first
+func (cs *CompService) List(propertyID uuid.UUID) ([]models.Comp, error) {
+	if _, err := cs.properties.Get(propertyID); err != nil {
+		return nil, err
+	}
+	return cs.load(propertyID)
+}
+
+// load reads a property's comps, nearest first
+func (cs *CompService) load(propertyID uuid.UUID) ([]models.Comp, error) {
+	comps, err := cs.store.Comps.List(propertyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list comps: %w", err)
+	}
+	models.SortCompsByDistance(comps)
+	return comps, nil
+}
+
+// Create records a comp against a property owned by the user
+func (cs *CompService) Create(userID, propertyID uuid.UUID, comp *models.Comp) error {
+	property, err := cs.properties.Get(propertyID)
+	if err != nil {
+		return err
+	}
+	if property.UserID != userID {
+		return ErrForbidden
+	}
+	if err := validateComp(comp); err != nil {
+		return err
+	}
+
+	comp.ID = uuid.Nil
+	comp.PropertyID = propertyID
+	comp.UserID = userID
+	if err := cs.store.Comps.Create(comp); err != nil {
+		return fmt.Errorf("failed to create comp: %w", err)
+	}
+	return nil
+}
+
+// Update replaces a comp's details
+func (cs *CompService) Update(userID, id uuid.UUID, changes *models.Comp) (*models.Comp, error) {
+	comp, err := cs.get(id)
+	if err != nil {
+		return nil, err
+	}
+	if comp.UserID != userID {
+		return nil, ErrForbidden
+	}
+	if err := validateComp(changes); err != nil {
+		return nil, err
+	}
+
+	changes.ID = comp.ID
+	changes.PropertyID = comp.PropertyID
+	changes.UserID = comp.UserID
+	changes.CreatedAt = comp.CreatedAt
+	if err := cs.store.Comps.Save(changes); err != nil {
+		return nil, fmt.Errorf("failed to update comp: %w", err)
+	}
+	return cs.get(id)
+}
+
+// Delete removes a comp
+func (cs *CompService) Delete(userID, id uuid.UUID) error {
+	comp, err := cs.get(id)
+	if err != nil {
+		return err
+	}
+	if comp.UserID != userID {
+		return ErrForbidden
+	}
+
+	if err := cs.store.Comps.Delete(comp); err != nil {
+		return fmt.Errorf("failed to delete comp: %w", err)
+	}
+	return nil
+}
+
+// Value adjusts the property's comps toward it as of today. With save set the
+// owner stores the adjusted range as a market value from the comps source.
+func (cs *CompService) Value(userID, propertyID uuid.UUID, settings models.CompAdjustments, save bool) (*models.CompValuation, error) {
+	property, err := cs.properties.Get(propertyID)
+	if err != nil {
+		return nil, err
+	}
+	if save && property.UserID != userID {
+		return nil, ErrForbidden
+	}
+
+	comps, err := cs.load(propertyID)
+	if err != nil {
+		return nil, err
+	}
+	if len(comps) == 0 {
+		return nil, fmt.Errorf("%w: property has no comps", ErrInvalidInput)
+	}
+
+	result := models.AdjustComps(property, comps, settings, today())
+	if !save {
+		return result, nil
+	}
+
+	valuation, err := result.ToValuation()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidInput, err)
+	}
+	if err := cs.valuations.createFromComps(userID, propertyID, valuation); err != nil {
+		return nil, err
+	}
+	result.Valuation = valuation
+	return result, nil
+}
+
+// get loads a comp by ID
+func (cs *CompService) get(id uuid.UUID) (*models.Comp, error) {
+	comp, err := cs.store.Comps.Get(id)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to load comp: %w", err)
+	}
+	return comp, nil
+}
+
+// validateComp checks the rules a request cannot express
+func validateComp(comp *models.Comp) error {
+	if comp.SaleDate.After(today()) {
+		return fmt.Errorf("%w: sale_date cannot be in the future", ErrInvalidInput)
+	}
+	if comp.YearBuilt != nil && *comp.YearBuilt > today().Year()+1 {
+		return fmt.Errorf("%w: year_built cannot be more than a year ahead", ErrInvalidInput)
+	}
+	return nil
+}
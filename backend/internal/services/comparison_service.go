@@ -0,0 +1,203 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/google/uuid"
+
+	"rental-property-mgmt/internal/models"
+	"rental-property-mgmt/internal/repository"
+)
+
+// ComparisonService evaluates properties against buying-box criteria
+type ComparisonService struct {
+	store    *repository.Store
+	criteria *BuyingCriteriaService
+}
+
+// NewComparisonService creates a new comparison service
+func NewComparisonService(store *repository.Store) *ComparisonService {
+	return &ComparisonService{store: store, criteria: NewBuyingCriteriaService(store)}
+}
+
+// RankingOptions selects the criteria and page for a portfolio ranking.
+// A nil CriteriaID ranks against every active criteria set of the user.
+type RankingOptions struct {
+	CriteriaID *uuid.UUID
+	Limit      int
+	Offset     int
+}
+
+// CriterionFailureSummary counts how often one criterion kept properties out of the buying box
+type CriterionFailureSummary struct {
+	Criterion   string  `json:"criterion"`
+	Failures    int     `json:"failures"`
+	Unknown     int     `json:"unknown"`
+	FailureRate float64 `json:"failure_rate"`
+}
+
+// RankingSummary aggregates a portfolio ranking across all pages
+type RankingSummary struct {
+	Evaluated       int                       `json:"evaluated"`
+	MeetsCriteria   int                       `json:"meets_criteria"`
+	NeedsReview     int                       `json:"needs_review"`
+	FailingCriteria []CriterionFailureSummary `json:"failing_criteria"`
+}
+
+// PortfolioRanking is one page of comparisons sorted by score, plus a summary of the full ranking
+type PortfolioRanking struct {
+	Results []*models.PropertyComparison `json:"results"`
+	Total   int                          `json:"total"`
+	Limit   int                          `json:"limit"`
+	Offset  int                          `json:"offset"`
+	Summary RankingSummary               `json:"summary"`
+}
+
+// Compare evaluates the given properties against one of the user's criteria sets,
+// returning comparisons in the order the properties were requested
+func (cs *ComparisonService) Compare(userID uuid.UUID, propertyIDs []uuid.UUID, criteriaID uuid.UUID) ([]*models.PropertyComparison, error) {
+	criteria, err := cs.criteria.Get(userID, criteriaID)
+	if err != nil {
+		return nil, err
+	}
+
+	properties, err := cs.store.Properties.ListByIDs(propertyIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load properties: %w", err)
+	}
+
+	byID := make(map[uuid.UUID]*models.Property, len(properties))
+	for i := range properties {
+		byID[properties[i].ID] = &properties[i]
+	}
+
+	comparisons := make([]*models.PropertyComparison, 0, len(propertyIDs))
+	for _, id := range propertyIDs {
+		property, ok := byID[id]
+		if !ok {
+			return nil, fmt.Errorf("property %s: %w", id, ErrNotFound)
+		}
+		comparisons = append(comparisons, criteria.CompareProperty(property, property.FinancialMetrics))
+	}
+	return comparisons, nil
+}
+
+// RankPortfolio compares every property the user owns against one or all of their
+// active criteria sets and returns the requested page of the ranking
+func (cs *ComparisonService) RankPortfolio(userID uuid.UUID, opts RankingOptions) (*PortfolioRanking, error) {
+	var criteriaSets []models.BuyingBoxCriteria
+	if opts.CriteriaID != nil {
+		criteria, err := cs.criteria.Get(userID, *opts.CriteriaID)
+		if err != nil {
+			return nil, err
+		}
+		criteriaSets = append(criteriaSets, *criteria)
+	} else {
+		var err error
+		if criteriaSets, err = cs.criteria.ListActive(userID); err != nil {
+			return nil, err
+		}
+	}
+
+	properties, err := cs.store.Properties.ListByOwner(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load properties: %w", err)
+	}
+
+	comparisons := make([]*models.PropertyComparison, 0, len(properties)*len(criteriaSets))
+	for i := range criteriaSets {
+		for j := range properties {
+			comparisons = append(comparisons, criteriaSets[i].CompareProperty(&properties[j], properties[j].FinancialMetrics))
+		}
+	}
+
+	return RankComparisons(comparisons, opts.Limit, opts.Offset), nil
+}
+
+// RankComparisons sorts comparisons best first and pages through them. Ties on
+// score go to full matches, then to more complete data, then by address.
+// The summary covers every comparison, not just the returned page.
+func RankComparisons(comparisons []*models.PropertyComparison, limit, offset int) *PortfolioRanking {
+	sort.SliceStable(comparisons, func(i, j int) bool {
+		a, b := comparisons[i], comparisons[j]
+		switch {
+		case a.Score != b.Score:
+			return a.Score > b.Score
+		case a.MeetsCriteria != b.MeetsCriteria:
+			return a.MeetsCriteria
+		case a.Completeness != b.Completeness:
+			return a.Completeness > b.Completeness
+		default:
+			return a.Property.Address < b.Property.Address
+		}
+	})
+
+	ranking := &PortfolioRanking{
+		Results: []*models.PropertyComparison{},
+		Total:   len(comparisons),
+		Limit:   limit,
+		Offset:  offset,
+		Summary: summarizeFailures(comparisons),
+	}
+	if offset < len(comparisons) {
+		end := offset + limit
+		if end > len(comparisons) {
+			end = len(comparisons)
+		}
+		ranking.Results = comparisons[offset:end]
+	}
+	return ranking
+}
+
+// summarizeFailures counts missed and unknown outcomes per criterion, most frequent first
+func summarizeFailures(comparisons []*models.PropertyComparison) RankingSummary {
+	summary := RankingSummary{
+		Evaluated:       len(comparisons),
+		FailingCriteria: []CriterionFailureSummary{},
+	}
+
+	counts := make(map[string]*CriterionFailureSummary)
+	applicable := make(map[string]int)
+	for _, comparison := range comparisons {
+		if comparison.MeetsCriteria {
+			summary.MeetsCriteria++
+		}
+		if comparison.NeedsReview {
+			summary.NeedsReview++
+		}
+
+		for _, result := range comparison.Contributions {
+			applicable[result.Criterion]++
+			if result.Outcome == models.OutcomeMet {
+				continue
+			}
+			entry, ok := counts[result.Criterion]
+			if !ok {
+				entry = &CriterionFailureSummary{Criterion: result.Criterion}
+				counts[result.Criterion] = entry
+			}
+			if result.Outcome == models.OutcomeUnknown {
+				entry.Unknown++
+			} else {
+				entry.Failures++
+			}
+		}
+	}
+
+	for name, entry := range counts {
+		entry.FailureRate = float64(entry.Failures) / float64(applicable[name]) * 100
+		summary.FailingCriteria = append(summary.FailingCriteria, *entry)
+	}
+	sort.Slice(summary.FailingCriteria, func(i, j int) bool {
+		a, b := summary.FailingCriteria[i], summary.FailingCriteria[j]
+		if a.Failures != b.Failures {
+			return a.Failures > b.Failures
+		}
+		if a.Unknown != b.Unknown {
+			return a.Unknown > b.Unknown
+		}
+		return a.Criterion < b.Criterion
+	})
+	return summary
+}
@@ -0,0 +1,24 @@
+package services
+
+import (
+	"errors"
+
+	"rental-property-mgmt/internal/repository"
+)
+
+var (
+	// ErrNotFound is returned when a requested record does not exist
+	ErrNotFound = repository.ErrNotFound
+
+	// ErrForbidden is returned when the caller may not act on a record
+	ErrForbidden = errors.New("access denied")
+
+	// ErrConflict is returned when a record would violate a uniqueness rule
+	ErrConflict = repository.ErrConflict
+
+	// ErrInvalidInput is wrapped with a reason when a request breaks a business rule
+	ErrInvalidInput = errors.New("invalid input")
+
+	// ErrInvalidCredentials is returned when an email/password pair does not match
+	ErrInvalidCredentials = errors.New("invalid email or password")
+)
@@ -0,0 +1,250 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+
+	"rental-property-mgmt/internal/models"
+	"rental-property-mgmt/internal/notifications"
+	"rental-property-mgmt/internal/repository"
+)
+
+// Notification events sent through the configured notifiers
+const (
+	EventCommentMention = "comment.mention"
+	EventCommentReply   = "comment.reply"
+)
+
+// NotificationService keeps users' in-app inboxes and emails comment mentions
+// and replies through the notifiers configured in the environment. Buying-box
+// matches reach the same inbox through the AlertService.
+type NotificationService struct {
+	store     *repository.Store
+	notifiers []notifications.Notifier
+}
+
+// NewNotificationService creates a new notification service
+func NewNotificationService(store *repository.Store) *NotificationService {
+	return &NotificationService{store: store, notifiers: notifications.FromEnv()}
+}
+
+// Inbox is one page of a user's notifications
+type Inbox struct {
+	Notifications []models.Notification `json:"notifications"`
+	Total         int64                 `json:"total"`
+	Unread        int64                 `json:"unread"`
+	Limit         int                   `json:"limit"`
+	Offset        int                   `json:"offset"`
+}
+
+// ResolveMentions looks up the users behind @handles. A handle is a full email
+// address or an email local part that identifies exactly one user. Every
+// mentioned user must have access to the property.
+func (ns *NotificationService) ResolveMentions(tx *repository.Store, handles []string, property *models.Property) ([]models.User, error) {
+	users := make([]models.User, 0, len(handles))
+	seen := make(map[uuid.UUID]bool)
+	for _, handle := range handles {
+		matches, err := tx.Users.MatchHandle(handle, 2)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve mention @%s: %w", handle, err)
+		}
+
+		switch {
+		case len(matches) == 0:
+			return nil, fmt.Errorf("%w: no user matches @%s", ErrInvalidInput, handle)
+		case len(matches) > 1:
+			return nil, fmt.Errorf("%w: @%s matches more than one user; mention them by full email", ErrInvalidInput, handle)
+		case !canAccessProperty(&matches[0], property):
+			return nil, fmt.Errorf("%w: @%s does not have access to this property", ErrInvalidInput, handle)
+		}
+
+		if !seen[matches[0].ID] {
+			seen[matches[0].ID] = true
+			users = append(users, matches[0])
+		}
+	}
+	return users, nil
+}
+
+// SyncMentions makes the comment's mention records match users and returns the
+// users who were not mentioned before
+func (ns *NotificationService) SyncMentions(tx *repository.Store, comment *models.Comment, users []models.User) ([]models.User, error) {
+	existing, err := tx.Notifications.Mentions(comment.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load mentions: %w", err)
+	}
+	previously := make(map[uuid.UUID]bool, len(existing))
+	for _, mention := range existing {
+		previously[mention.UserID] = true
+	}
+
+	current := make([]uuid.UUID, 0, len(users))
+	var added []models.User
+	for _, user := range users {
+		current = append(current, user.ID)
+		if previously[user.ID] {
+			continue
+		}
+		mention := models.CommentMention{CommentID: comment.ID, UserID: user.ID}
+		if err := tx.Notifications.CreateMention(&mention); err != nil {
+			return nil, fmt.Errorf("failed to record mention: %w", err)
+		}
+		added = append(added, user)
+	}
+
+	if err := tx.Notifications.DeleteMentions(comment.ID, current); err != nil {
+		return nil, fmt.Errorf("failed to remove mentions: %w", err)
+	}
+	return added, nil
+}
+
+// CommentPosted adds inbox entries for newly mentioned users and, for a reply,
+// the parent comment's author. Nobody is notified about their own comment and
+// each user gets at most one notification per comment.
+func (ns *NotificationService) CommentPosted(tx *repository.Store, comment *models.Comment, parent *models.Comment, mentioned []models.User) ([]models.Notification, error) {
+	var created []models.Notification
+	notified := map[uuid.UUID]bool{comment.UserID: true}
+
+	add := func(userID uuid.UUID, kind string) error {
+		if notified[userID] {
+			return nil
+		}
+		notified[userID] = true
+		notification := models.Notification{
+			UserID:     userID,
+			ActorID:    &comment.UserID,
+			Type:       kind,
+			CommentID:  &comment.ID,
+			PropertyID: comment.PropertyID,
+		}
+		if err := tx.Notifications.Create(&notification); err != nil {
+			return fmt.Errorf("failed to create notification: %w", err)
+		}
+		created = append(created, notification)
+		return nil
+	}
+
+	for _, user := range mentioned {
+		if err := add(user.ID, models.NotificationMention); err != nil {
+			return nil, err
+		}
+	}
+	if parent != nil {
+		if err := add(parent.UserID, models.NotificationReply); err != nil {
+			return nil, err
+		}
+	}
+	return created, nil
+}
+
+// Deliver hands notifications to the configured notifiers. Messages are built
+// here but sent in the background; failures are logged, and the inbox already
+// holds the notification.
+func (ns *NotificationService) Deliver(comment *models.Comment, created []models.Notification) {
+	if len(ns.notifiers) == 0 || len(created) == 0 {
+		return
+	}
+
+	property, err := ns.store.Properties.Get(comment.PropertyID)
+	if err != nil {
+		log.Printf("notifications: failed to load property %s: %v", comment.PropertyID, err)
+		return
+	}
+
+	msgs := make([]notifications.Message, 0, len(created))
+	for _, notification := range created {
+		recipient, err := ns.store.Users.Get(notification.UserID)
+		if err != nil {
+			log.Printf("notifications: failed to load recipient %s: %v", notification.UserID, err)
+			continue
+		}
+		msgs = append(msgs, CommentMessage(notification, comment, property, recipient.Email))
+	}
+	notifications.Deliver("notifications", ns.notifiers, msgs)
+}
+
+// CommentMessage builds the email for a mention or reply notification
+func CommentMessage(notification models.Notification, comment *models.Comment, property *models.Property, email string) notifications.Message {
+	actor := comment.UserName
+	if actor == "" {
+		actor = "A teammate"
+	}
+
+	subject := fmt.Sprintf("%s mentioned you on %s", actor, property.Address)
+	event := EventCommentMention
+	if notification.Type == models.NotificationReply {
+		subject = fmt.Sprintf("%s replied to your comment on %s", actor, property.Address)
+		event = EventCommentReply
+	}
+
+	var to []string
+	if email != "" {
+		to = []string{email}
+	}
+	return notifications.Message{
+		To:      to,
+		Subject: subject,
+		Body:    fmt.Sprintf("%s wrote:\n\n%s\n", actor, comment.Content),
+		Event:   event,
+		Data: map[string]interface{}{
+			"notification_id": notification.ID,
+			"comment_id":      comment.ID,
+			"property_id":     comment.PropertyID,
+			"user_id":         notification.UserID,
+		},
+	}
+}
+
+// List returns a page of the user's notifications, newest first
+func (ns *NotificationService) List(userID uuid.UUID, unreadOnly bool, limit, offset int) (*Inbox, error) {
+	items, total, err := ns.store.Notifications.List(userID, unreadOnly, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list notifications: %w", err)
+	}
+	unread, err := ns.store.Notifications.CountUnread(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count unread notifications: %w", err)
+	}
+
+	inbox := &Inbox{Notifications: items, Total: total, Unread: unread, Limit: limit, Offset: offset}
+	for i := range inbox.Notifications {
+		if actor := inbox.Notifications[i].Actor; actor != nil {
+			inbox.Notifications[i].ActorName = actor.FirstName + " " + actor.LastName
+		}
+		if comment := inbox.Notifications[i].Comment; comment != nil {
+			comment.RenderContent()
+		}
+	}
+	return inbox, nil
+}
+
+// MarkRead marks one of the user's notifications as read
+func (ns *NotificationService) MarkRead(userID, id uuid.UUID) error {
+	notification, err := ns.store.Notifications.GetForUser(userID, id)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return ErrNotFound
+		}
+		return fmt.Errorf("failed to load notification: %w", err)
+	}
+	if notification.ReadAt != nil {
+		return nil
+	}
+
+	if err := ns.store.Notifications.MarkRead(notification, time.Now()); err != nil {
+		return fmt.Errorf("failed to mark notification read: %w", err)
+	}
+	return nil
+}
+
+// MarkAllRead marks every unread notification of the user as read
+func (ns *NotificationService) MarkAllRead(userID uuid.UUID) error {
+	if err := ns.store.Notifications.MarkAllRead(userID, time.Now()); err != nil {
+		return fmt.Errorf("failed to mark notifications read: %w", err)
+	}
+	return nil
+}
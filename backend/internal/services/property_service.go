@@ -0,0 +1,285 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+
+	"rental-property-mgmt/internal/models"
+	"rental-property-mgmt/internal/repository"
+)
+
+// PropertyHook is notified after a property is created or updated
+type PropertyHook interface {
+	PropertySaved(property *models.Property) error
+}
+
+// PropertyService handles property persistence. Properties are readable by
+// every team member; only the owner may modify them.
+type PropertyService struct {
+	store       *repository.Store
+	profiles    *AssumptionProfileService
+	calculation *CalculationService
+	hooks       []PropertyHook
+}
+
+// NewPropertyService creates a new property service
+func NewPropertyService(store *repository.Store) *PropertyService {
+	return &PropertyService{
+		store:       store,
+		profiles:    NewAssumptionProfileService(store),
+		calculation: NewCalculationService(),
+		hooks:       []PropertyHook{NewAlertService(store)},
+	}
+}
+
+// Create stores a new property owned by the user. Missing operating assumptions and
+// expenses are filled from the requested profile, or from the best-matching profile for
+// the property's ZIP code/metro when profileID is nil. Metrics are calculated when the
+// property has enough data.
+func (ps *PropertyService) Create(userID uuid.UUID, property *models.Property, profileID *uuid.UUID) error {
+	property.ID = uuid.Nil
+	property.UserID = userID
+	property.FillAddressComponents()
+	if err := validateProperty(property); err != nil {
+		return err
+	}
+
+	var profile *models.AssumptionProfile
+	var err error
+	if profileID != nil {
+		profile, err = ps.profiles.Get(userID, *profileID)
+	} else {
+		profile, err = ps.profiles.FindBestMatch(userID, property.GetZipCode(), property.Metro())
+	}
+	if err != nil {
+		return err
+	}
+
+	if profile != nil {
+		property.DefaultedFields = profile.ApplyTo(property)
+		property.AssumptionProfileID = &profile.ID
+	}
+
+	err = ps.store.Transaction(func(tx *repository.Store) error {
+		if err := tx.Properties.Create(property); err != nil {
+			return fmt.Errorf("failed to create property: %w", err)
+		}
+		return ps.storeMetrics(tx, property)
+	})
+	if err != nil {
+		return err
+	}
+
+	ps.runHooks(property)
+	return nil
+}
+
+// Update applies changes to a property owned by the user and recalculates its
+// metrics. When the address changes, city, state and ZIP code are parsed from
+// the new address unless apply sets them.
+func (ps *PropertyService) Update(userID, id uuid.UUID, apply func(*models.Property)) (*models.Property, error) {
+	property, err := ps.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	if property.UserID != userID {
+		return nil, ErrForbidden
+	}
+
+	previous := *property
+	apply(property)
+	if property.Address != previous.Address {
+		// Components apply did not replace belong to the old address; parse
+		// them from the new one instead
+		if property.City == previous.City {
+			property.City = nil
+		}
+		if property.State == previous.State {
+			property.State = nil
+		}
+		if property.ZipCode == previous.ZipCode {
+			property.ZipCode = nil
+		}
+	}
+	property.FillAddressComponents()
+	if err := validateProperty(property); err != nil {
+		return nil, err
+	}
+
+	err = ps.store.Transaction(func(tx *repository.Store) error {
+		if err := tx.Properties.Save(property); err != nil {
+			return fmt.Errorf("failed to update property: %w", err)
+		}
+		if err := tx.Metrics.DeleteForProperty(property.ID); err != nil {
+			return fmt.Errorf("failed to clear financial metrics: %w", err)
+		}
+		property.FinancialMetrics = nil
+		return ps.storeMetrics(tx, property)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	ps.runHooks(property)
+	return property, nil
+}
+
+// RecalculateMetrics replaces the stored metrics of a property owned by the
+// user with freshly calculated ones
+func (ps *PropertyService) RecalculateMetrics(userID, id uuid.UUID) (*models.FinancialMetrics, error) {
+	property, err := ps.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	if property.UserID != userID {
+		return nil, ErrForbidden
+	}
+	if !property.HasRequiredFieldsForMetrics() {
+		return nil, fmt.Errorf("%w: property needs a purchase price, intended rent, operating expenses, financing terms and operating assumptions", ErrInvalidInput)
+	}
+
+	metrics, err := ps.calculation.CalculateMetrics(property)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidInput, err)
+	}
+	err = ps.store.Transaction(func(tx *repository.Store) error {
+		if err := tx.Metrics.DeleteForProperty(property.ID); err != nil {
+			return fmt.Errorf("failed to clear financial metrics: %w", err)
+		}
+		if err := tx.Metrics.Create(metrics); err != nil {
+			return fmt.Errorf("failed to store financial metrics: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return metrics, nil
+}
+
+// validateProperty checks the rules a request cannot express
+func validateProperty(property *models.Property) error {
+	if property.YearBuilt != nil && *property.YearBuilt > today().Year()+1 {
+		return fmt.Errorf("%w: year_built cannot be more than a year ahead", ErrInvalidInput)
+	}
+	return nil
+}
+
+// storeMetrics calculates and stores metrics when the property has enough data
+func (ps *PropertyService) storeMetrics(tx *repository.Store, property *models.Property) error {
+	if !property.HasRequiredFieldsForMetrics() {
+		return nil
+	}
+
+	metrics, err := ps.calculation.CalculateMetrics(property)
+	if err != nil {
+		// Incomplete financing data is allowed; metrics can be recalculated later
+		return nil
+	}
+	if err := tx.Metrics.Create(metrics); err != nil {
+		return fmt.Errorf("failed to store financial metrics: %w", err)
+	}
+	property.FinancialMetrics = metrics
+	return nil
+}
+
+// runHooks notifies property hooks after a save. Hook failures are logged and
+// never undo the save.
+func (ps *PropertyService) runHooks(property *models.Property) {
+	for _, hook := range ps.hooks {
+		if err := hook.PropertySaved(property); err != nil {
+			log.Printf("property %s: hook failed: %v", property.ID, err)
+		}
+	}
+}
+
+// canAccessProperty reports whether the user may read the property. Every
+// active team member can read every property (FR-019).
+func canAccessProperty(user *models.User, property *models.Property) bool {
+	return user.IsActive && property != nil
+}
+
+// Get returns a property with its current financial metrics
+func (ps *PropertyService) Get(id uuid.UUID) (*models.Property, error) {
+	property, err := ps.store.Properties.Get(id)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to load property: %w", err)
+	}
+	return property, nil
+}
+
+// Portfolio listing sort keys
+const (
+	PropertySortCreatedAt     = repository.PropertySortCreatedAt
+	PropertySortPurchasePrice = repository.PropertySortPurchasePrice
+	PropertySortCapRate       = repository.PropertySortCapRate
+	PropertySortPursueVotes   = repository.PropertySortPursueVotes
+)
+
+// PropertyListOptions pages, sorts and filters the portfolio listing
+type PropertyListOptions = repository.PropertyListOptions
+
+// PropertySummary is a property in the portfolio listing with its team vote tally
+type PropertySummary struct {
+	ID               uuid.UUID        `json:"id"`
+	Address          string           `json:"address"`
+	PropertyType     *string          `json:"property_type"`
+	YearBuilt        *int             `json:"year_built"`
+	PurchasePrice    float64          `json:"purchase_price"`
+	IntendedRent     *float64         `json:"intended_rent"`
+	CapRate          *float64         `json:"cap_rate"`
+	CashOnCashReturn *float64         `json:"cash_on_cash_return"`
+	CreatedAt        time.Time        `json:"created_at"`
+	Votes            models.VoteTally `json:"votes"`
+}
+
+// PropertyList is one page of the portfolio listing
+type PropertyList struct {
+	Properties []PropertySummary `json:"properties"`
+	Total      int64             `json:"total"`
+	Limit      int               `json:"limit"`
+	Offset     int               `json:"offset"`
+}
+
+// List returns a page of the user's properties with current metrics and the
+// team's vote tally, filtered and sorted by opts. Properties without a value
+// for the sort column come last.
+func (ps *PropertyService) List(userID uuid.UUID, opts PropertyListOptions) (*PropertyList, error) {
+	if !repository.IsPropertySort(opts.Sort) {
+		return nil, fmt.Errorf("%w: unknown sort %q", ErrInvalidInput, opts.Sort)
+	}
+	if opts.Decision != nil && !models.IsValidDecision(*opts.Decision) {
+		return nil, fmt.Errorf("%w: decision must be pass, maybe or pursue", ErrInvalidInput)
+	}
+
+	rows, total, err := ps.store.Properties.ListSummaries(userID, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list properties: %w", err)
+	}
+
+	list := &PropertyList{Properties: []PropertySummary{}, Total: total, Limit: opts.Limit, Offset: opts.Offset}
+	for _, row := range rows {
+		votes := models.NewVoteTally(row.PassVotes, row.MaybeVotes, row.PursueVotes)
+		votes.MyVote = row.MyVote
+		list.Properties = append(list.Properties, PropertySummary{
+			ID:               row.ID,
+			Address:          row.Address,
+			PropertyType:     row.PropertyType,
+			YearBuilt:        row.YearBuilt,
+			PurchasePrice:    row.PurchasePrice,
+			IntendedRent:     row.IntendedRent,
+			CapRate:          row.CapRate,
+			CashOnCashReturn: row.CashOnCashReturn,
+			CreatedAt:        row.CreatedAt,
+			Votes:            votes,
+		})
+	}
+	return list, nil
+}
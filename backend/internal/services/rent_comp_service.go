@@ -0,0 +1,156 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"rental-property-mgmt/internal/models"
+	"rental-property-mgmt/internal/repository"
+)
+
+// RentCompService manages a property's rental comps and estimates its rent
+// from them. Every team member can read rent comps; only the property owner
+// records them or stores the resulting rental estimate.
+type RentCompService struct {
+	store      *repository.Store
+	properties *PropertyService
+	valuations *ValuationService
+}
+
+// NewRentCompService creates a new rent comp service
+func NewRentCompService(store *repository.Store) *RentCompService {
+	return &RentCompService{
+		store:      store,
+		properties: NewPropertyService(store),
+		valuations: NewValuationService(store),
+	}
+}
+
+// List returns a property's rent comps, nearest first
+func (rs *RentCompService) List(propertyID uuid.UUID) ([]models.RentComp, error) {
+	if _, err := rs.properties.Get(propertyID); err != nil {
+		return nil, err
+	}
+	return rs.load(propertyID)
+}
+
+// load reads a property's rent comps, nearest first
+func (rs *RentCompService) load(propertyID uuid.UUID) ([]models.RentComp, error) {
+	comps, err := rs.store.RentComps.List(propertyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list rent comps: %w", err)
+	}
+	models.SortRentCompsByDistance(comps)
+	return comps, nil
+}
+
+// Create records a rent comp against a property owned by the user
+func (rs *RentCompService) Create(userID, propertyID uuid.UUID, comp *models.RentComp) error {
+	property, err := rs.properties.Get(propertyID)
+	if err != nil {
+		return err
+	}
+	if property.UserID != userID {
+		return ErrForbidden
+	}
+	if comp.ListingDate.After(today()) {
+		return fmt.Errorf("%w: listing_date cannot be in the future", ErrInvalidInput)
+	}
+
+	comp.ID = uuid.Nil
+	comp.PropertyID = propertyID
+	comp.UserID = userID
+	if err := rs.store.RentComps.Create(comp); err != nil {
+		return fmt.Errorf("failed to create rent comp: %w", err)
+	}
+	return nil
+}
+
+// Update replaces a rent comp's details
+func (rs *RentCompService) Update(userID, id uuid.UUID, changes *models.RentComp) (*models.RentComp, error) {
+	comp, err := rs.get(id)
+	if err != nil {
+		return nil, err
+	}
+	if comp.UserID != userID {
+		return nil, ErrForbidden
+	}
+	if changes.ListingDate.After(today()) {
+		return nil, fmt.Errorf("%w: listing_date cannot be in the future", ErrInvalidInput)
+	}
+
+	changes.ID = comp.ID
+	changes.PropertyID = comp.PropertyID
+	changes.UserID = comp.UserID
+	changes.CreatedAt = comp.CreatedAt
+	if err := rs.store.RentComps.Save(changes); err != nil {
+		return nil, fmt.Errorf("failed to update rent comp: %w", err)
+	}
+	return rs.get(id)
+}
+
+// Delete removes a rent comp
+func (rs *RentCompService) Delete(userID, id uuid.UUID) error {
+	comp, err := rs.get(id)
+	if err != nil {
+		return err
+	}
+	if comp.UserID != userID {
+		return ErrForbidden
+	}
+
+	if err := rs.store.RentComps.Delete(comp); err != nil {
+		return fmt.Errorf("failed to delete rent comp: %w", err)
+	}
+	return nil
+}
+
+// Estimate suggests the property's rent from its rent comps as of today and
+// compares IntendedRent with the comps' median. With save the owner stores
+// the suggested rent as a rental estimate from the comps source.
+func (rs *RentCompService) Estimate(userID, propertyID uuid.UUID, annualGrowthPercent float64, save bool) (*models.RentEstimate, error) {
+	property, err := rs.properties.Get(propertyID)
+	if err != nil {
+		return nil, err
+	}
+	if save && property.UserID != userID {
+		return nil, ErrForbidden
+	}
+
+	comps, err := rs.load(propertyID)
+	if err != nil {
+		return nil, err
+	}
+	if len(comps) == 0 {
+		return nil, fmt.Errorf("%w: property has no rent comps", ErrInvalidInput)
+	}
+
+	estimate := models.EstimateRent(property, comps, annualGrowthPercent, today())
+	if !save {
+		return estimate, nil
+	}
+
+	valuation, err := estimate.ToValuation()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidInput, err)
+	}
+	if err := rs.valuations.createFromComps(userID, propertyID, valuation); err != nil {
+		return nil, err
+	}
+	estimate.Valuation = valuation
+	return estimate, nil
+}
+
+// get loads a rent comp by ID
+func (rs *RentCompService) get(id uuid.UUID) (*models.RentComp, error) {
+	comp, err := rs.store.RentComps.Get(id)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to load rent comp: %w", err)
+	}
+	return comp, nil
+}
@@ -0,0 +1,213 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"rental-property-mgmt/internal/models"
+	"rental-property-mgmt/internal/repository"
+)
+
+// ScenarioService manages what-if scenarios for properties. Any team member
+// may add scenarios to a property; only a scenario's author may change it.
+type ScenarioService struct {
+	store       *repository.Store
+	properties  *PropertyService
+	calculation *CalculationService
+}
+
+// NewScenarioService creates a new scenario service
+func NewScenarioService(store *repository.Store) *ScenarioService {
+	return &ScenarioService{
+		store:       store,
+		properties:  NewPropertyService(store),
+		calculation: NewCalculationService(),
+	}
+}
+
+// ScenarioResult is one column of a side-by-side scenario comparison
+type ScenarioResult struct {
+	ScenarioID    *uuid.UUID               `json:"scenario_id"`
+	Name          string                   `json:"name"`
+	PurchasePrice float64                  `json:"purchase_price"`
+	IntendedRent  *float64                 `json:"intended_rent"`
+	Metrics       *models.FinancialMetrics `json:"metrics"`
+	MetricsError  string                   `json:"metrics_error,omitempty"`
+	// Deltas holds each metric's difference from the base property
+	Deltas map[string]float64 `json:"deltas,omitempty"`
+}
+
+// ScenarioComparison lines up the base property against its scenarios
+type ScenarioComparison struct {
+	PropertyID uuid.UUID        `json:"property_id"`
+	Base       ScenarioResult   `json:"base"`
+	Scenarios  []ScenarioResult `json:"scenarios"`
+}
+
+// List returns a property's scenarios with freshly computed metrics
+func (ss *ScenarioService) List(propertyID uuid.UUID) ([]models.Scenario, error) {
+	property, err := ss.properties.Get(propertyID)
+	if err != nil {
+		return nil, err
+	}
+
+	scenarios, err := ss.store.Scenarios.List(propertyID, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list scenarios: %w", err)
+	}
+
+	for i := range scenarios {
+		scenarios[i].Metrics, _ = ss.calculation.CalculateMetrics(scenarios[i].Apply(property))
+	}
+	return scenarios, nil
+}
+
+// Get returns a single scenario with freshly computed metrics
+func (ss *ScenarioService) Get(id uuid.UUID) (*models.Scenario, error) {
+	scenario, err := ss.store.Scenarios.Get(id)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to load scenario: %w", err)
+	}
+
+	property, err := ss.properties.Get(scenario.PropertyID)
+	if err != nil {
+		return nil, err
+	}
+	scenario.Metrics, _ = ss.calculation.CalculateMetrics(scenario.Apply(property))
+	return scenario, nil
+}
+
+// Create adds a scenario to a property
+func (ss *ScenarioService) Create(userID, propertyID uuid.UUID, scenario *models.Scenario) error {
+	property, err := ss.properties.Get(propertyID)
+	if err != nil {
+		return err
+	}
+
+	scenario.ID = uuid.Nil
+	scenario.PropertyID = propertyID
+	scenario.UserID = userID
+	if err := ss.store.Scenarios.Create(scenario); err != nil {
+		return fmt.Errorf("failed to create scenario: %w", err)
+	}
+
+	scenario.Metrics, _ = ss.calculation.CalculateMetrics(scenario.Apply(property))
+	return nil
+}
+
+// Update replaces a scenario's overrides
+func (ss *ScenarioService) Update(userID, id uuid.UUID, changes *models.Scenario) (*models.Scenario, error) {
+	scenario, err := ss.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	if scenario.UserID != userID {
+		return nil, ErrForbidden
+	}
+
+	changes.ID = scenario.ID
+	changes.PropertyID = scenario.PropertyID
+	changes.UserID = scenario.UserID
+	changes.CreatedAt = scenario.CreatedAt
+	if err := ss.store.Scenarios.Save(changes); err != nil {
+		return nil, fmt.Errorf("failed to update scenario: %w", err)
+	}
+
+	return ss.Get(id)
+}
+
+// Delete removes a scenario
+func (ss *ScenarioService) Delete(userID, id uuid.UUID) error {
+	scenario, err := ss.store.Scenarios.Get(id)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return ErrNotFound
+		}
+		return fmt.Errorf("failed to load scenario: %w", err)
+	}
+	if scenario.UserID != userID {
+		return ErrForbidden
+	}
+
+	if err := ss.store.Scenarios.Delete(scenario); err != nil {
+		return fmt.Errorf("failed to delete scenario: %w", err)
+	}
+	return nil
+}
+
+// Compare lines up the base property against the given scenarios, or all of
+// the property's scenarios when scenarioIDs is empty
+func (ss *ScenarioService) Compare(propertyID uuid.UUID, scenarioIDs []uuid.UUID) (*ScenarioComparison, error) {
+	property, err := ss.properties.Get(propertyID)
+	if err != nil {
+		return nil, err
+	}
+
+	scenarios, err := ss.store.Scenarios.List(propertyID, scenarioIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load scenarios: %w", err)
+	}
+	if len(scenarios) != len(scenarioIDs) && len(scenarioIDs) > 0 {
+		return nil, ErrNotFound
+	}
+
+	comparison := &ScenarioComparison{
+		PropertyID: property.ID,
+		Base:       ss.evaluate(nil, "Base", property),
+		Scenarios:  make([]ScenarioResult, 0, len(scenarios)),
+	}
+
+	for i := range scenarios {
+		result := ss.evaluate(&scenarios[i].ID, scenarios[i].Name, scenarios[i].Apply(property))
+		result.Deltas = metricDeltas(comparison.Base.Metrics, result.Metrics)
+		comparison.Scenarios = append(comparison.Scenarios, result)
+	}
+
+	return comparison, nil
+}
+
+// evaluate computes one comparison column
+func (ss *ScenarioService) evaluate(scenarioID *uuid.UUID, name string, property *models.Property) ScenarioResult {
+	result := ScenarioResult{
+		ScenarioID:    scenarioID,
+		Name:          name,
+		PurchasePrice: property.PurchasePrice,
+		IntendedRent:  property.IntendedRent,
+	}
+
+	metrics, err := ss.calculation.CalculateMetrics(property)
+	if err != nil {
+		result.MetricsError = err.Error()
+		return result
+	}
+	result.Metrics = metrics
+	return result
+}
+
+// metricDeltas returns scenario minus base for every metric both sides define
+func metricDeltas(base, scenario *models.FinancialMetrics) map[string]float64 {
+	if base == nil || scenario == nil {
+		return nil
+	}
+
+	deltas := map[string]float64{}
+	add := func(name string, b, s *float64) {
+		if b != nil && s != nil {
+			deltas[name] = *s - *b
+		}
+	}
+
+	add("monthly_mortgage_payment", base.MonthlyMortgagePayment, scenario.MonthlyMortgagePayment)
+	add("net_operating_income", base.NetOperatingIncome, scenario.NetOperatingIncome)
+	add("cap_rate", base.CapRate, scenario.CapRate)
+	add("cash_on_cash_return", base.CashOnCashReturn, scenario.CashOnCashReturn)
+	add("cash_to_close", base.CashToClose, scenario.CashToClose)
+	add("rent_to_value_ratio", base.RentToValueRatio, scenario.RentToValueRatio)
+	add("gross_rent_multiplier", base.GrossRentMultiplier, scenario.GrossRentMultiplier)
+	return deltas
+}
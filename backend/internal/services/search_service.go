@@ -0,0 +1,120 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+
+	"rental-property-mgmt/internal/repository"
+)
+
+// Search hit types
+const (
+	SearchTypeProperty = repository.SearchTypeProperty
+	SearchTypeComment  = repository.SearchTypeComment
+)
+
+// MaxSearchQueryLength bounds search queries, in characters
+const MaxSearchQueryLength = 200
+
+// SearchService runs full-text searches over properties and comments
+type SearchService struct {
+	store *repository.Store
+}
+
+// NewSearchService creates a new search service
+func NewSearchService(store *repository.Store) *SearchService {
+	return &SearchService{store: store}
+}
+
+// SearchOptions narrows a search
+type SearchOptions struct {
+	// Types limits hits to properties or comments; empty searches both
+	Types []string
+	// OwnedOnly limits hits to the caller's own properties and their comments
+	OwnedOnly bool
+	Limit     int
+	Offset    int
+}
+
+// SearchHit is a matching property or comment. Search returns Snippet as
+// HTML-escaped text with the matching terms wrapped in <mark> tags.
+type SearchHit = repository.SearchHit
+
+// SearchResults is one page of search hits, best first
+type SearchResults struct {
+	Query  string      `json:"query"`
+	Hits   []SearchHit `json:"hits"`
+	Total  int64       `json:"total"`
+	Limit  int         `json:"limit"`
+	Offset int         `json:"offset"`
+}
+
+// Search finds properties and comments matching the query, which uses web
+// search syntax: words, "quoted phrases", or and -excluded words. Every active
+// team member can read every property and its comments (FR-019); OwnedOnly
+// narrows the search to the caller's own portfolio.
+func (ss *SearchService) Search(userID uuid.UUID, query string, opts SearchOptions) (*SearchResults, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, fmt.Errorf("%w: search query is required", ErrInvalidInput)
+	}
+	if utf8.RuneCountInString(query) > MaxSearchQueryLength {
+		return nil, fmt.Errorf("%w: search query must be at most %d characters", ErrInvalidInput, MaxSearchQueryLength)
+	}
+
+	searchProperties, searchComments := len(opts.Types) == 0, len(opts.Types) == 0
+	for _, kind := range opts.Types {
+		switch kind {
+		case SearchTypeProperty:
+			searchProperties = true
+		case SearchTypeComment:
+			searchComments = true
+		default:
+			return nil, fmt.Errorf("%w: type must be property or comment", ErrInvalidInput)
+		}
+	}
+
+	user, err := ss.store.Users.Get(userID)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return nil, ErrForbidden
+		}
+		return nil, fmt.Errorf("failed to load user: %w", err)
+	}
+	if !user.IsActive {
+		return nil, ErrForbidden
+	}
+
+	search := repository.SearchQuery{
+		Text:       query,
+		Properties: searchProperties,
+		Comments:   searchComments,
+		Limit:      opts.Limit,
+		Offset:     opts.Offset,
+	}
+	if opts.OwnedOnly {
+		search.OwnerID = &userID
+	}
+	hits, total, err := ss.store.Search.Search(search)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search: %w", err)
+	}
+
+	results := &SearchResults{Query: query, Hits: hits, Total: total, Limit: opts.Limit, Offset: opts.Offset}
+	for i := range results.Hits {
+		results.Hits[i].Snippet = HighlightSnippet(results.Hits[i].Snippet)
+	}
+	return results, nil
+}
+
+// HighlightSnippet escapes a search snippet for HTML and turns its highlight
+// markers into <mark> tags
+func HighlightSnippet(snippet string) string {
+	escaped := html.EscapeString(strings.TrimSpace(snippet))
+	return strings.NewReplacer(repository.HighlightStart, "<mark>", repository.HighlightStop, "</mark>").Replace(escaped)
+}
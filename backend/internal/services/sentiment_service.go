@@ -0,0 +1,173 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"rental-property-mgmt/internal/models"
+	"rental-property-mgmt/internal/repository"
+)
+
+// SentimentService records structured team sentiment: emoji reactions on
+// comments and pass/maybe/pursue votes on properties. Like comments, any team
+// member may react and vote on any property.
+type SentimentService struct {
+	store *repository.Store
+}
+
+// NewSentimentService creates a new sentiment service
+func NewSentimentService(store *repository.Store) *SentimentService {
+	return &SentimentService{store: store}
+}
+
+// PropertyVotes is a property's vote tally with the individual votes
+type PropertyVotes struct {
+	PropertyID uuid.UUID             `json:"property_id"`
+	Tally      models.VoteTally      `json:"tally"`
+	Votes      []models.PropertyVote `json:"votes"`
+}
+
+// React adds the user's emoji reaction to a comment and returns the comment's
+// reactions. Reacting twice with the same emoji is a no-op.
+func (ss *SentimentService) React(userID, commentID uuid.UUID, emoji string) ([]models.ReactionSummary, error) {
+	if !models.IsEmoji(emoji) {
+		return nil, fmt.Errorf("%w: reaction must be a single emoji", ErrInvalidInput)
+	}
+	comment, err := ss.findComment(commentID)
+	if err != nil {
+		return nil, err
+	}
+	if comment.IsDeleted() {
+		return nil, fmt.Errorf("%w: comment has been deleted", ErrConflict)
+	}
+
+	reaction := models.CommentReaction{CommentID: commentID, UserID: userID, Emoji: emoji}
+	if err := ss.store.Comments.AddReaction(&reaction); err != nil {
+		return nil, fmt.Errorf("failed to add reaction: %w", err)
+	}
+	return ss.Reactions(commentID)
+}
+
+// Unreact removes the user's emoji reaction from a comment
+func (ss *SentimentService) Unreact(userID, commentID uuid.UUID, emoji string) error {
+	if _, err := ss.findComment(commentID); err != nil {
+		return err
+	}
+
+	removed, err := ss.store.Comments.RemoveReaction(commentID, userID, emoji)
+	if err != nil {
+		return fmt.Errorf("failed to remove reaction: %w", err)
+	}
+	if !removed {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// Reactions returns a comment's reactions grouped by emoji
+func (ss *SentimentService) Reactions(commentID uuid.UUID) ([]models.ReactionSummary, error) {
+	reactions, err := loadReactions(ss.store.Comments, []uuid.UUID{commentID})
+	if err != nil {
+		return nil, err
+	}
+	return models.SummarizeReactions(reactions[commentID]), nil
+}
+
+// Vote records the user's decision on a property, replacing any earlier vote,
+// and returns the updated tally
+func (ss *SentimentService) Vote(userID, propertyID uuid.UUID, decision string) (*models.VoteTally, error) {
+	if !models.IsValidDecision(decision) {
+		return nil, fmt.Errorf("%w: decision must be pass, maybe or pursue", ErrInvalidInput)
+	}
+	if err := ss.ensureProperty(propertyID); err != nil {
+		return nil, err
+	}
+
+	vote := models.PropertyVote{PropertyID: propertyID, UserID: userID, Decision: decision}
+	if err := ss.store.Votes.Upsert(&vote); err != nil {
+		return nil, fmt.Errorf("failed to record vote: %w", err)
+	}
+
+	votes, err := ss.Votes(userID, propertyID)
+	if err != nil {
+		return nil, err
+	}
+	return &votes.Tally, nil
+}
+
+// Unvote withdraws the user's vote on a property
+func (ss *SentimentService) Unvote(userID, propertyID uuid.UUID) error {
+	if err := ss.ensureProperty(propertyID); err != nil {
+		return err
+	}
+
+	withdrawn, err := ss.store.Votes.Delete(propertyID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to withdraw vote: %w", err)
+	}
+	if !withdrawn {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// Votes returns every team member's vote on a property, most recent first,
+// with the tally as seen by userID
+func (ss *SentimentService) Votes(userID, propertyID uuid.UUID) (*PropertyVotes, error) {
+	if err := ss.ensureProperty(propertyID); err != nil {
+		return nil, err
+	}
+
+	votes, err := ss.store.Votes.List(propertyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load votes: %w", err)
+	}
+	result := &PropertyVotes{PropertyID: propertyID, Votes: votes}
+	for i := range result.Votes {
+		if user := result.Votes[i].User; user != nil {
+			result.Votes[i].UserName = user.FirstName + " " + user.LastName
+		}
+	}
+	result.Tally = models.TallyVotes(result.Votes, userID)
+	return result, nil
+}
+
+// findComment loads a single comment
+func (ss *SentimentService) findComment(id uuid.UUID) (*models.Comment, error) {
+	comment, err := ss.store.Comments.Get(id)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to load comment: %w", err)
+	}
+	return comment, nil
+}
+
+// ensureProperty returns ErrNotFound when the property does not exist
+func (ss *SentimentService) ensureProperty(propertyID uuid.UUID) error {
+	exists, err := ss.store.Properties.Exists(propertyID)
+	if err != nil {
+		return fmt.Errorf("failed to load property: %w", err)
+	}
+	if !exists {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// loadReactions loads the reactions on the given comments, keyed by comment
+func loadReactions(comments repository.CommentRepository, commentIDs []uuid.UUID) (map[uuid.UUID][]models.CommentReaction, error) {
+	reactions, err := comments.Reactions(commentIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load reactions: %w", err)
+	}
+
+	byComment := make(map[uuid.UUID][]models.CommentReaction)
+	for _, reaction := range reactions {
+		byComment[reaction.CommentID] = append(byComment[reaction.CommentID], reaction)
+	}
+	return byComment, nil
+}
@@ -0,0 +1,90 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+
+	"rental-property-mgmt/internal/models"
+	"rental-property-mgmt/internal/repository"
+)
+
+// UserService handles user registration and authentication
+type UserService struct {
+	store *repository.Store
+}
+
+// NewUserService creates a new user service
+func NewUserService(store *repository.Store) *UserService {
+	return &UserService{store: store}
+}
+
+// Register creates a new user with a bcrypt-hashed password
+func (us *UserService) Register(email, password, firstName, lastName string) (*models.User, error) {
+	email = strings.ToLower(strings.TrimSpace(email))
+
+	taken, err := us.store.Users.EmailTaken(email)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check existing user: %w", err)
+	}
+	if taken {
+		return nil, ErrConflict
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	user := &models.User{
+		Email:        email,
+		PasswordHash: string(hash),
+		FirstName:    strings.TrimSpace(firstName),
+		LastName:     strings.TrimSpace(lastName),
+		IsActive:     true,
+	}
+
+	if err := us.store.Users.Create(user); err != nil {
+		return nil, fmt.Errorf("failed to create user: %w", err)
+	}
+
+	return user, nil
+}
+
+// Authenticate verifies credentials and returns the matching active user
+func (us *UserService) Authenticate(email, password string) (*models.User, error) {
+	email = strings.ToLower(strings.TrimSpace(email))
+
+	user, err := us.store.Users.FindByEmail(email)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return nil, ErrInvalidCredentials
+		}
+		return nil, fmt.Errorf("failed to load user: %w", err)
+	}
+
+	if !user.IsActive {
+		return nil, ErrInvalidCredentials
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)); err != nil {
+		return nil, ErrInvalidCredentials
+	}
+
+	return user, nil
+}
+
+// GetByID loads a user by ID
+func (us *UserService) GetByID(id uuid.UUID) (*models.User, error) {
+	user, err := us.store.Users.Get(id)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to load user: %w", err)
+	}
+	return user, nil
+}
@@ -0,0 +1,423 @@
+package services
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"rental-property-mgmt/internal/models"
+	"rental-property-mgmt/internal/repository"
+)
+
+// MaxValuationImportRows caps the data rows accepted in one import
+const MaxValuationImportRows = 5000
+
+// Import row statuses
+const (
+	// ImportRowMatched marks a row a dry run would create
+	ImportRowMatched   = "matched"
+	ImportRowCreated   = "created"
+	ImportRowDuplicate = "duplicate"
+	ImportRowRejected  = "rejected"
+)
+
+// ValuationImportMapping names the CSV columns holding each valuation field.
+// Columns match headers case-insensitively. Address and value default to the
+// "address" and "value" columns; city, state and ZIP columns, when mapped, are
+// appended to the address. Source and ValuationType fill rows that have no
+// source or type column, or an empty cell in it. Rows without a date are
+// dated today.
+type ValuationImportMapping struct {
+	AddressColumn string
+	CityColumn    string
+	StateColumn   string
+	ZipColumn     string
+	ValueColumn   string
+	DateColumn    string
+	SourceColumn  string
+	TypeColumn    string
+	Source        string
+	ValuationType string
+}
+
+// ValuationImportRow reports what happened to one CSV line
+type ValuationImportRow struct {
+	Line       int                       `json:"line"`
+	Status     string                    `json:"status"`
+	Address    string                    `json:"address"`
+	PropertyID *uuid.UUID                `json:"property_id,omitempty"`
+	Valuation  *models.PropertyValuation `json:"valuation,omitempty"`
+	Reason     string                    `json:"reason,omitempty"`
+}
+
+// ValuationImportReport is the outcome of a CSV import. Matched counts rows
+// whose address matched one of the user's properties, whatever became of them.
+type ValuationImportReport struct {
+	DryRun     bool                 `json:"dry_run"`
+	Rows       int                  `json:"rows"`
+	Matched    int                  `json:"matched"`
+	Created    int                  `json:"created"`
+	Duplicated int                  `json:"duplicated"`
+	Rejected   int                  `json:"rejected"`
+	Results    []ValuationImportRow `json:"results"`
+}
+
+// reject marks a row rejected for the given reason
+func (row *ValuationImportRow) reject(reason string) {
+	row.Status = ImportRowRejected
+	row.Valuation = nil
+	row.Reason = reason
+}
+
+// ParseValuationCSV reads valuation rows from CSV using the column mapping.
+// Rows with unusable cells come back rejected; the error is reserved for a
+// file that cannot be read at all or lacks a mapped column. Accepted rows
+// carry an unsaved valuation and no status yet.
+func ParseValuationCSV(r io.Reader, mapping ValuationImportMapping, asOf time.Time) ([]ValuationImportRow, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("%w: CSV file is empty", ErrInvalidInput)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid CSV: %v", ErrInvalidInput, err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		if _, ok := columns[name]; !ok {
+			columns[name] = i
+		}
+	}
+	column := func(name, fallback string, required bool) (int, error) {
+		if name == "" {
+			name = fallback
+		}
+		if name == "" {
+			return -1, nil
+		}
+		index, ok := columns[strings.ToLower(strings.TrimSpace(name))]
+		if !ok && (required || name != fallback) {
+			return -1, fmt.Errorf("%w: CSV has no %q column", ErrInvalidInput, name)
+		}
+		if !ok {
+			return -1, nil
+		}
+		return index, nil
+	}
+
+	var cols struct{ address, city, state, zip, value, date, source, kind int }
+	for _, c := range []struct {
+		dst      *int
+		name     string
+		fallback string
+		required bool
+	}{
+		{&cols.address, mapping.AddressColumn, "address", true},
+		{&cols.city, mapping.CityColumn, "", false},
+		{&cols.state, mapping.StateColumn, "", false},
+		{&cols.zip, mapping.ZipColumn, "", false},
+		{&cols.value, mapping.ValueColumn, "value", true},
+		{&cols.date, mapping.DateColumn, "", false},
+		{&cols.source, mapping.SourceColumn, "", false},
+		{&cols.kind, mapping.TypeColumn, "", false},
+	} {
+		if *c.dst, err = column(c.name, c.fallback, c.required); err != nil {
+			return nil, err
+		}
+	}
+	if cols.source < 0 && mapping.Source == "" {
+		return nil, fmt.Errorf("%w: map a source column or give a source", ErrInvalidInput)
+	}
+	if cols.kind < 0 && mapping.ValuationType == "" {
+		return nil, fmt.Errorf("%w: map a valuation type column or give a valuation_type", ErrInvalidInput)
+	}
+
+	var rows []ValuationImportRow
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid CSV: %v", ErrInvalidInput, err)
+		}
+		line, _ := reader.FieldPos(0)
+
+		cell := func(index int) string {
+			if index < 0 || index >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[index])
+		}
+		if strings.Join(record, "") == "" {
+			continue // Blank line
+		}
+		if len(rows) == MaxValuationImportRows {
+			return nil, fmt.Errorf("%w: CSV has more than %d rows", ErrInvalidInput, MaxValuationImportRows)
+		}
+
+		parts := []string{cell(cols.address)}
+		for _, index := range []int{cols.city, cols.state, cols.zip} {
+			if part := cell(index); part != "" {
+				parts = append(parts, part)
+			}
+		}
+		row := ValuationImportRow{Line: line, Address: strings.Join(parts, ", ")}
+		row.Valuation, row.Reason = parseValuationCells(cell(cols.address), cell(cols.value), cell(cols.date),
+			firstNonEmpty(cell(cols.source), mapping.Source), firstNonEmpty(cell(cols.kind), mapping.ValuationType), asOf)
+		if row.Valuation == nil {
+			row.Status = ImportRowRejected
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// parseValuationCells builds a valuation from one row's cells, or explains
+// why it cannot
+func parseValuationCells(address, value, date, source, valuationType string, asOf time.Time) (*models.PropertyValuation, string) {
+	if address == "" {
+		return nil, "address is empty"
+	}
+
+	amount, err := strconv.ParseFloat(strings.NewReplacer("$", "", ",", "", " ", "").Replace(value), 64)
+	if err != nil || amount <= 0 {
+		return nil, fmt.Sprintf("value %q is not a positive amount", value)
+	}
+
+	valuationDate := asOf
+	if date != "" {
+		valuationDate, err = parseImportDate(date)
+		if err != nil {
+			return nil, fmt.Sprintf("date %q is not a date", date)
+		}
+		if valuationDate.After(asOf) {
+			return nil, "date cannot be in the future"
+		}
+	}
+
+	if len(source) > 50 {
+		return nil, "source is longer than 50 characters"
+	}
+	valuationType = strings.NewReplacer(" ", "_", "-", "_").Replace(strings.ToLower(valuationType))
+	if valuationType != models.ValuationTypeMarketValue && valuationType != models.ValuationTypeRentalEstimate {
+		return nil, "valuation type must be market_value or rental_estimate"
+	}
+
+	return &models.PropertyValuation{
+		Source:        source,
+		ValuationType: valuationType,
+		Value:         amount,
+		ValuationDate: valuationDate,
+	}, ""
+}
+
+// importDateLayouts are the date formats accepted in import files
+var importDateLayouts = []string{"2006-01-02", "01/02/2006", "1/2/2006", "2006/01/02", time.RFC3339}
+
+// parseImportDate parses a date in any of the accepted layouts as a UTC day
+func parseImportDate(value string) (time.Time, error) {
+	for _, layout := range importDateLayouts {
+		if parsed, err := time.Parse(layout, value); err == nil {
+			return time.Date(parsed.Year(), parsed.Month(), parsed.Day(), 0, 0, 0, 0, time.UTC), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unrecognized date %q", value)
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// AddressIndex matches free-form addresses to properties by normalized address
+type AddressIndex struct {
+	full   map[string][]uuid.UUID
+	street map[string][]uuid.UUID
+}
+
+// NewAddressIndex indexes properties by their normalized full address, by
+// street plus city, state and ZIP code, and by street alone
+func NewAddressIndex(properties []models.Property) *AddressIndex {
+	index := &AddressIndex{full: make(map[string][]uuid.UUID), street: make(map[string][]uuid.UUID)}
+	add := func(keys map[string][]uuid.UUID, key string, id uuid.UUID) {
+		if key == "" {
+			return
+		}
+		for _, existing := range keys[key] {
+			if existing == id {
+				return
+			}
+		}
+		keys[key] = append(keys[key], id)
+	}
+
+	for _, property := range properties {
+		street := streetPart(property.Address)
+		add(index.full, models.NormalizeAddress(property.Address), property.ID)
+		add(index.full, models.NormalizeAddress(strings.Join([]string{
+			street, derefString(property.City), derefString(property.State), derefString(property.ZipCode),
+		}, " ")), property.ID)
+		add(index.street, models.NormalizeAddress(street), property.ID)
+	}
+	return index
+}
+
+// Match finds the property at an address. A bare street address matches
+// only when a single property is on that street address.
+func (ai *AddressIndex) Match(address string) (uuid.UUID, string) {
+	if ids := ai.full[models.NormalizeAddress(address)]; len(ids) == 1 {
+		return ids[0], ""
+	}
+	switch ids := ai.street[models.NormalizeAddress(streetPart(address))]; len(ids) {
+	case 0:
+		return uuid.Nil, "no property of yours matches the address"
+	case 1:
+		return ids[0], ""
+	default:
+		return uuid.Nil, "address matches more than one property"
+	}
+}
+
+// streetPart returns an address up to its first comma
+func streetPart(address string) string {
+	street, _, _ := strings.Cut(address, ",")
+	return street
+}
+
+func derefString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+// valuationKey identifies a valuation for duplicate detection
+type valuationKey struct {
+	propertyID    uuid.UUID
+	source        string
+	valuationType string
+	date          string
+}
+
+func keyOf(valuation *models.PropertyValuation) valuationKey {
+	return valuationKey{
+		propertyID:    valuation.PropertyID,
+		source:        valuation.Source,
+		valuationType: valuation.ValuationType,
+		date:          valuation.ValuationDate.Format("2006-01-02"),
+	}
+}
+
+// Import reads valuations from CSV and records them against the user's own
+// properties. Every line gets a result: created (or matched on a dry run),
+// duplicate when its property already has a valuation from the same source,
+// of the same type, on the same date, or rejected with a reason. Nothing is
+// stored on a dry run; otherwise all new valuations are stored together.
+func (vs *ValuationService) Import(userID uuid.UUID, r io.Reader, mapping ValuationImportMapping, dryRun bool) (*ValuationImportReport, error) {
+	rows, err := ParseValuationCSV(r, mapping, today())
+	if err != nil {
+		return nil, err
+	}
+
+	properties, err := vs.store.Properties.ListByOwner(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load properties: %w", err)
+	}
+	index := NewAddressIndex(properties)
+
+	seen := make(map[valuationKey]bool)
+	if len(properties) > 0 {
+		propertyIDs := make([]uuid.UUID, len(properties))
+		for i, property := range properties {
+			propertyIDs[i] = property.ID
+		}
+		existing, err := vs.store.Valuations.ListForProperties(propertyIDs)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load valuations: %w", err)
+		}
+		for i := range existing {
+			seen[keyOf(&existing[i])] = true
+		}
+	}
+
+	report := &ValuationImportReport{DryRun: dryRun, Rows: len(rows), Results: rows}
+	var created []*models.PropertyValuation
+	for i := range rows {
+		row := &rows[i]
+		if row.Status == ImportRowRejected {
+			continue
+		}
+
+		if !vs.providers.Has(row.Valuation.Source) {
+			row.reject(fmt.Sprintf("source %q is not one of: %s", row.Valuation.Source, strings.Join(vs.providers.Sources(), ", ")))
+			continue
+		}
+
+		propertyID, reason := index.Match(row.Address)
+		if propertyID == uuid.Nil {
+			row.reject(reason)
+			continue
+		}
+		row.PropertyID = &propertyID
+		row.Valuation.PropertyID = propertyID
+		report.Matched++
+
+		key := keyOf(row.Valuation)
+		if seen[key] {
+			row.Status = ImportRowDuplicate
+			row.Reason = "a valuation from this source, of this type and date already exists"
+			row.Valuation = nil
+			continue
+		}
+		seen[key] = true
+
+		if dryRun {
+			row.Status = ImportRowMatched
+		} else {
+			row.Status = ImportRowCreated
+			created = append(created, row.Valuation)
+		}
+	}
+
+	for _, row := range rows {
+		switch row.Status {
+		case ImportRowCreated:
+			report.Created++
+		case ImportRowDuplicate:
+			report.Duplicated++
+		case ImportRowRejected:
+			report.Rejected++
+		}
+	}
+
+	if len(created) > 0 {
+		err := vs.store.Transaction(func(tx *repository.Store) error {
+			for _, valuation := range created {
+				if err := tx.Valuations.Create(valuation); err != nil {
+					return fmt.Errorf("failed to create valuation: %w", err)
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return report, nil
+}
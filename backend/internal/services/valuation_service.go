@@ -0,0 +1,327 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"rental-property-mgmt/internal/models"
+	"rental-property-mgmt/internal/repository"
+	"rental-property-mgmt/internal/valuations"
+)
+
+// ValuationService manages third-party valuations of properties. Every team
+// member can read valuations; only the property owner adds them. The accepted
+// sources are the providers in the registry.
+type ValuationService struct {
+	store       *repository.Store
+	properties  *PropertyService
+	calculation *CalculationService
+	providers   *valuations.Registry
+}
+
+// NewValuationService creates a valuation service using the providers
+// configured in the environment
+func NewValuationService(store *repository.Store) *ValuationService {
+	return NewValuationServiceWithProviders(store, valuations.Default())
+}
+
+// NewValuationServiceWithProviders creates a valuation service using the
+// given provider registry
+func NewValuationServiceWithProviders(store *repository.Store, providers *valuations.Registry) *ValuationService {
+	return &ValuationService{
+		store:       store,
+		properties:  NewPropertyService(store),
+		calculation: NewCalculationService(),
+		providers:   providers,
+	}
+}
+
+// ValuationHistories is a property's valuation time series per type
+type ValuationHistories struct {
+	PropertyID uuid.UUID                 `json:"property_id"`
+	Sigma      float64                   `json:"sigma"`
+	Windows    []int                     `json:"windows"`
+	Histories  []models.ValuationHistory `json:"histories"`
+}
+
+// RefreshReport summarizes one refresh of provider valuations
+type RefreshReport struct {
+	Properties int `json:"properties"`
+	// Created counts the valuations appended
+	Created int `json:"created"`
+	// Unchanged counts estimates already recorded for their date
+	Unchanged int `json:"unchanged"`
+	// Missing counts provider lookups with no estimate for the address
+	Missing int      `json:"missing"`
+	Errors  []string `json:"errors,omitempty"`
+}
+
+// ValuationSummaries is the per-type summary of a property's valuations
+type ValuationSummaries struct {
+	PropertyID uuid.UUID                 `json:"property_id"`
+	AsOf       time.Time                 `json:"as_of"`
+	Summaries  []models.ValuationSummary `json:"summaries"`
+}
+
+// List returns a property's valuations, newest first, optionally of one type
+func (vs *ValuationService) List(propertyID uuid.UUID, valuationType string) ([]models.PropertyValuation, error) {
+	if _, err := vs.properties.Get(propertyID); err != nil {
+		return nil, err
+	}
+
+	valuations, err := vs.store.Valuations.List(propertyID, valuationType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list valuations: %w", err)
+	}
+	return valuations, nil
+}
+
+// Create adds a valuation from one of the registered sources to a property
+// owned by the user. A missing valuation date defaults to today.
+func (vs *ValuationService) Create(userID, propertyID uuid.UUID, valuation *models.PropertyValuation) error {
+	return vs.create(userID, propertyID, valuation, false)
+}
+
+// createFromComps stores a valuation the comps services computed, the only
+// valuations with the Comps source
+func (vs *ValuationService) createFromComps(userID, propertyID uuid.UUID, valuation *models.PropertyValuation) error {
+	valuation.Source = models.ValuationSourceComps
+	return vs.create(userID, propertyID, valuation, true)
+}
+
+// create stores a valuation for a property owned by the user; only computed
+// valuations may skip the registered sources check
+func (vs *ValuationService) create(userID, propertyID uuid.UUID, valuation *models.PropertyValuation, computed bool) error {
+	property, err := vs.properties.Get(propertyID)
+	if err != nil {
+		return err
+	}
+	if property.UserID != userID {
+		return ErrForbidden
+	}
+
+	if !computed && !vs.providers.Has(valuation.Source) {
+		return fmt.Errorf("%w: source must be one of: %s", ErrInvalidInput, strings.Join(vs.providers.Sources(), ", "))
+	}
+
+	valuation.ID = uuid.Nil
+	valuation.PropertyID = propertyID
+	if valuation.ValuationDate.IsZero() {
+		valuation.ValuationDate = today()
+	}
+	if valuation.ValuationDate.After(today()) {
+		return fmt.Errorf("%w: valuation_date cannot be in the future", ErrInvalidInput)
+	}
+
+	if err := vs.store.Valuations.Create(valuation); err != nil {
+		return fmt.Errorf("failed to create valuation: %w", err)
+	}
+	return nil
+}
+
+// Summary aggregates a property's valuations per type as of today
+func (vs *ValuationService) Summary(propertyID uuid.UUID) (*ValuationSummaries, error) {
+	valuations, err := vs.List(propertyID, "")
+	if err != nil {
+		return nil, err
+	}
+
+	asOf := today()
+	return &ValuationSummaries{
+		PropertyID: propertyID,
+		AsOf:       asOf,
+		Summaries:  models.SummarizeValuations(valuations, asOf),
+	}, nil
+}
+
+// History returns a property's per-source valuation time series, optionally
+// of one type, with trailing changes over the windows (in days) and outliers
+// flagged at sigma standard deviations from the other sources
+func (vs *ValuationService) History(propertyID uuid.UUID, valuationType string, sigma float64, windows []int) (*ValuationHistories, error) {
+	if sigma <= 0 {
+		return nil, fmt.Errorf("%w: sigma must be positive", ErrInvalidInput)
+	}
+	for _, days := range windows {
+		if days <= 0 {
+			return nil, fmt.Errorf("%w: windows must be positive numbers of days", ErrInvalidInput)
+		}
+	}
+
+	valuations, err := vs.List(propertyID, valuationType)
+	if err != nil {
+		return nil, err
+	}
+	return &ValuationHistories{
+		PropertyID: propertyID,
+		Sigma:      sigma,
+		Windows:    windows,
+		Histories:  models.BuildValuationHistory(valuations, sigma, windows),
+	}, nil
+}
+
+// Metrics returns a property's financial metrics on the given rent basis. The
+// intended basis serves the stored metrics; the market basis recalculates them
+// with the consensus rental estimate in place of IntendedRent, without storing
+// anything.
+func (vs *ValuationService) Metrics(propertyID uuid.UUID, rentBasis string) (*models.FinancialMetrics, error) {
+	property, err := vs.properties.Get(propertyID)
+	if err != nil {
+		return nil, err
+	}
+
+	switch rentBasis {
+	case models.RentBasisIntended:
+		if property.FinancialMetrics == nil {
+			return nil, ErrNotFound
+		}
+		metrics := property.FinancialMetrics
+		metrics.RentBasis = models.RentBasisIntended
+		metrics.MonthlyRent = property.IntendedRent
+		return metrics, nil
+
+	case models.RentBasisMarket:
+		summary, err := vs.Summary(propertyID)
+		if err != nil {
+			return nil, err
+		}
+		rentals := models.FindValuationSummary(summary.Summaries, models.ValuationTypeRentalEstimate)
+		if rentals == nil {
+			return nil, fmt.Errorf("%w: property has no rental estimates", ErrInvalidInput)
+		}
+
+		marketRent := rentals.Consensus()
+		variant := *property
+		variant.IntendedRent = &marketRent
+		metrics, err := vs.calculation.CalculateMetrics(&variant)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidInput, err)
+		}
+		metrics.IsCurrent = false
+		metrics.CalculatedAt = time.Now()
+		metrics.RentBasis = models.RentBasisMarket
+		metrics.MonthlyRent = &marketRent
+		return metrics, nil
+
+	default:
+		return nil, fmt.Errorf("%w: rent must be intended or market", ErrInvalidInput)
+	}
+}
+
+// Sources returns the valuation sources currently accepted
+func (vs *ValuationService) Sources() []string {
+	return vs.providers.Sources()
+}
+
+// RunRefresh refreshes provider valuations immediately and then every
+// interval until the context is cancelled
+func (vs *ValuationService) RunRefresh(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		report, err := vs.Refresh(ctx)
+		if err != nil {
+			log.Printf("valuations: refresh failed: %v", err)
+		} else {
+			log.Printf("valuations: refreshed %d properties, %d new valuations, %d errors",
+				report.Properties, report.Created, len(report.Errors))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Refresh asks every fetching provider for each property's current estimates
+// and appends a valuation for each one not yet recorded for its date. Provider
+// failures are collected in the report rather than stopping the run.
+func (vs *ValuationService) Refresh(ctx context.Context) (*RefreshReport, error) {
+	report := &RefreshReport{}
+	fetchers := vs.providers.Fetchers()
+	if len(fetchers) == 0 {
+		return report, nil
+	}
+
+	properties, err := vs.store.Properties.ListAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load properties: %w", err)
+	}
+
+	for i := range properties {
+		if err := ctx.Err(); err != nil {
+			return report, err
+		}
+		report.Properties++
+		for _, provider := range fetchers {
+			if err := vs.refreshFrom(ctx, provider, &properties[i], report); err != nil {
+				report.Errors = append(report.Errors, fmt.Sprintf("%s %s: %v", provider.Name(), properties[i].ID, err))
+			}
+		}
+	}
+	return report, nil
+}
+
+// refreshFrom records one provider's estimates for a property
+func (vs *ValuationService) refreshFrom(ctx context.Context, provider valuations.Provider, property *models.Property, report *RefreshReport) error {
+	quote, err := provider.Fetch(ctx, property.Address)
+	if errors.Is(err, valuations.ErrNoEstimate) {
+		report.Missing++
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	asOf := quote.AsOf
+	if asOf.IsZero() || asOf.After(today()) {
+		asOf = today()
+	}
+
+	estimates := map[string]*float64{
+		models.ValuationTypeMarketValue:    quote.MarketValue,
+		models.ValuationTypeRentalEstimate: quote.RentEstimate,
+	}
+	for _, valuationType := range models.ValuationTypes {
+		value := estimates[valuationType]
+		if value == nil || *value <= 0 {
+			continue
+		}
+
+		existing, err := vs.store.Valuations.Exists(property.ID, provider.Name(), valuationType, asOf)
+		if err != nil {
+			return fmt.Errorf("failed to check valuations: %w", err)
+		}
+		if existing {
+			report.Unchanged++
+			continue
+		}
+
+		valuation := models.PropertyValuation{
+			PropertyID:    property.ID,
+			Source:        provider.Name(),
+			ValuationType: valuationType,
+			Value:         *value,
+			ValuationDate: asOf,
+		}
+		if err := vs.store.Valuations.Create(&valuation); err != nil {
+			return fmt.Errorf("failed to create valuation: %w", err)
+		}
+		report.Created++
+	}
+	return nil
+}
+
+// today returns the current date at midnight UTC
+func today() time.Time {
+	now := time.Now().UTC()
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+}
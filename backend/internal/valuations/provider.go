@@ -0,0 +1,166 @@
+package valuations
+
+import (
+	"context"
+	"errors"
+	"log"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrNoEstimate is returned by a provider that has nothing for an address
+var ErrNoEstimate = errors.New("no estimate for address")
+
+// Quote is what a provider knows about one address. Either value may be
+// missing when the provider only covers sales or rents.
+type Quote struct {
+	MarketValue  *float64
+	RentEstimate *float64
+	// AsOf is the date the provider produced the estimates
+	AsOf time.Time
+}
+
+// Provider fetches valuations from one source
+type Provider interface {
+	// Name is the source recorded on each valuation, e.g. "Zillow"
+	Name() string
+	// Fetch returns the provider's current market value and rent estimate
+	// for a full street address, or ErrNoEstimate
+	Fetch(ctx context.Context, address string) (*Quote, error)
+}
+
+// ManualProvider is a source whose valuations are only ever entered by hand.
+// It is registered so the source is accepted, but it never fetches.
+type ManualProvider string
+
+// Name implements Provider
+func (p ManualProvider) Name() string {
+	return string(p)
+}
+
+// Fetch implements Provider; manual sources have nothing to fetch
+func (p ManualProvider) Fetch(ctx context.Context, address string) (*Quote, error) {
+	return nil, ErrNoEstimate
+}
+
+// IsManual reports whether a provider only accepts hand-entered valuations
+func IsManual(p Provider) bool {
+	_, ok := p.(ManualProvider)
+	return ok
+}
+
+// ManualSources are the sources accepted before any provider is configured.
+// Comps valuations are computed from a property's comps, never entered or
+// fetched, so that source is not registered.
+var ManualSources = []string{"Zillow", "Redfin", "Rentimate"}
+
+// Registry holds the providers by source name. The registered names are the
+// sources a valuation may carry.
+type Registry struct {
+	mu        sync.RWMutex
+	providers map[string]Provider
+}
+
+// NewRegistry creates a registry holding the given providers
+func NewRegistry(providers ...Provider) *Registry {
+	r := &Registry{providers: make(map[string]Provider)}
+	for _, p := range providers {
+		r.Register(p)
+	}
+	return r
+}
+
+// Register adds a provider, replacing any provider of the same name so a
+// fixture can stand in for a live source
+func (r *Registry) Register(p Provider) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.providers[p.Name()] = p
+}
+
+// Get returns the provider for a source
+func (r *Registry) Get(source string) (Provider, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	p, ok := r.providers[source]
+	return p, ok
+}
+
+// Has reports whether a source is registered
+func (r *Registry) Has(source string) bool {
+	_, ok := r.Get(source)
+	return ok
+}
+
+// Sources returns the registered source names in alphabetical order
+func (r *Registry) Sources() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	sources := make([]string, 0, len(r.providers))
+	for name := range r.providers {
+		sources = append(sources, name)
+	}
+	sort.Strings(sources)
+	return sources
+}
+
+// Fetchers returns the providers that fetch valuations, in source order
+func (r *Registry) Fetchers() []Provider {
+	var fetchers []Provider
+	for _, source := range r.Sources() {
+		if p, ok := r.Get(source); ok && !IsManual(p) {
+			fetchers = append(fetchers, p)
+		}
+	}
+	return fetchers
+}
+
+// FromEnv builds the registry configured by the environment: the manual
+// sources, plus one file provider per path in the comma-separated
+// VALUATION_FIXTURES list
+func FromEnv() (*Registry, error) {
+	registry := manualRegistry()
+	for _, path := range strings.Split(os.Getenv("VALUATION_FIXTURES"), ",") {
+		path = strings.TrimSpace(path)
+		if path == "" {
+			continue
+		}
+		provider, err := LoadFileProvider(path)
+		if err != nil {
+			return nil, err
+		}
+		registry.Register(provider)
+	}
+	return registry, nil
+}
+
+// manualRegistry returns a registry of just the manual sources
+func manualRegistry() *Registry {
+	registry := NewRegistry()
+	for _, source := range ManualSources {
+		registry.Register(ManualProvider(source))
+	}
+	return registry
+}
+
+var (
+	defaultOnce     sync.Once
+	defaultRegistry *Registry
+)
+
+// Default returns the process-wide registry, built from the environment on
+// first use. A broken fixture is logged and leaves only the manual sources.
+func Default() *Registry {
+	defaultOnce.Do(func() {
+		registry, err := FromEnv()
+		if err != nil {
+			log.Printf("valuations: %v", err)
+			registry = manualRegistry()
+		}
+		defaultRegistry = registry
+	})
+	return defaultRegistry
+}
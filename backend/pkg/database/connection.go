@@ -6,39 +6,74 @@ import (
 	"os"
 	"time"
 
+	"github.com/glebarez/sqlite"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
-
-	"rental-property-mgmt/internal/models"
 )
 
-// DB holds the database connection
-var DB *gorm.DB
+// Database drivers selectable with DB_DRIVER
+const (
+	DriverPostgres = "postgres"
+	DriverSQLite   = "sqlite"
+)
 
-// Config holds database configuration
+// Config holds database configuration. Path is the SQLite database file, or
+// ":memory:" for a private in-memory database; the other connection fields
+// apply to PostgreSQL. Schema, when set, is searched before public, which
+// lets tests work in a throwaway schema.
 type Config struct {
+	Driver   string
 	Host     string
 	Port     string
 	User     string
 	Password string
 	DBName   string
 	SSLMode  string
+	Schema   string
+	Path     string
 }
 
-// Connect establishes a connection to the PostgreSQL database
-func Connect() error {
-	config := Config{
+// ConfigFromEnv reads the database configuration from the environment
+func ConfigFromEnv() Config {
+	return Config{
+		Driver:   getEnv("DB_DRIVER", DriverPostgres),
 		Host:     getEnv("DB_HOST", "localhost"),
 		Port:     getEnv("DB_PORT", "5432"),
 		User:     getEnv("DB_USER", "rental_user"),
 		Password: getEnv("DB_PASSWORD", "rental_password"),
 		DBName:   getEnv("DB_NAME", "rental_property_mgmt"),
 		SSLMode:  getEnv("DB_SSLMODE", "disable"),
+		Path:     getEnv("DB_PATH", "rental_property_mgmt.db"),
 	}
+}
 
-	dsn := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
-		config.Host, config.Port, config.User, config.Password, config.DBName, config.SSLMode)
+// Connect opens a connection pool to the database configured in the
+// environment
+func Connect() (*gorm.DB, error) {
+	return Open(ConfigFromEnv())
+}
+
+// Open opens a connection pool to the configured database
+func Open(config Config) (*gorm.DB, error) {
+	var dialector gorm.Dialector
+	switch config.Driver {
+	case DriverPostgres, "":
+		dsn := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
+			config.Host, config.Port, config.User, config.Password, config.DBName, config.SSLMode)
+		if config.Schema != "" {
+			dsn += " search_path=" + config.Schema + ",public"
+		}
+		dialector = postgres.Open(dsn)
+	case DriverSQLite:
+		if config.Path == "" {
+			return nil, fmt.Errorf("sqlite database path is required")
+		}
+		// Foreign keys are off by default in SQLite; cascades depend on them
+		dialector = sqlite.Open(config.Path + "?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)")
+	default:
+		return nil, fmt.Errorf("unsupported database driver %q", config.Driver)
+	}
 
 	// Configure GORM logger
 	var gormLogger logger.Interface
@@ -48,62 +83,45 @@ func Connect() error {
 		gormLogger = logger.Default.LogMode(logger.Error)
 	}
 
-	var err error
-	DB, err = gorm.Open(postgres.Open(dsn), &gorm.Config{
+	db, err := gorm.Open(dialector, &gorm.Config{
 		Logger: gormLogger,
+		// Report unique violations as gorm.ErrDuplicatedKey on every driver
+		TranslateError: true,
 		NowFunc: func() time.Time {
 			return time.Now().UTC()
 		},
 	})
-
 	if err != nil {
-		return fmt.Errorf("failed to connect to database: %w", err)
+		return nil, fmt.Errorf("failed to connect to database: %w", err)
 	}
 
 	// Configure connection pool
-	sqlDB, err := DB.DB()
+	sqlDB, err := db.DB()
 	if err != nil {
-		return fmt.Errorf("failed to get database instance: %w", err)
-	}
-
-	sqlDB.SetMaxOpenConns(25)
-	sqlDB.SetMaxIdleConns(5)
-	sqlDB.SetConnMaxLifetime(5 * time.Minute)
-
-	log.Println("Database connection established")
-	return nil
-}
-
-// AutoMigrate runs database migrations
-func AutoMigrate() error {
-	if DB == nil {
-		return fmt.Errorf("database connection not established")
+		return nil, fmt.Errorf("failed to get database instance: %w", err)
 	}
-
-	err := DB.Set("gorm:table_options", "WITHOUT DROP CONSTRAINT").AutoMigrate(
-		&models.User{},
-		&models.Property{},
-		&models.PropertyValuation{},
-		&models.FinancialMetrics{},
-		&models.Comment{},
-		&models.BuyingBoxCriteria{},
-	)
-
-	if err != nil {
-		return fmt.Errorf("failed to run migrations: %w", err)
+	if config.Driver == DriverSQLite {
+		// SQLite allows one writer at a time, and every connection to
+		// ":memory:" would see its own empty database
+		sqlDB.SetMaxOpenConns(1)
+		sqlDB.SetConnMaxLifetime(0)
+	} else {
+		sqlDB.SetMaxOpenConns(25)
+		sqlDB.SetMaxIdleConns(5)
+		sqlDB.SetConnMaxLifetime(5 * time.Minute)
 	}
 
-	log.Println("Database migrations completed")
-	return nil
+	log.Println("Database connection established")
+	return db, nil
 }
 
 // Close closes the database connection
-func Close() error {
-	if DB == nil {
+func Close(db *gorm.DB) error {
+	if db == nil {
 		return nil
 	}
 
-	sqlDB, err := DB.DB()
+	sqlDB, err := db.DB()
 	if err != nil {
 		return fmt.Errorf("failed to get database instance: %w", err)
 	}
@@ -111,11 +129,6 @@ func Close() error {
 	return sqlDB.Close()
 }
 
-// GetDB returns the database instance
-func GetDB() *gorm.DB {
-	return DB
-}
-
 // getEnv gets an environment variable with a fallback default
 func getEnv(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
@@ -125,12 +138,12 @@ func getEnv(key, defaultValue string) string {
 }
 
 // TestConnection verifies the database connection
-func TestConnection() error {
-	if DB == nil {
+func TestConnection(db *gorm.DB) error {
+	if db == nil {
 		return fmt.Errorf("database connection not established")
 	}
 
-	sqlDB, err := DB.DB()
+	sqlDB, err := db.DB()
 	if err != nil {
 		return fmt.Errorf("failed to get database instance: %w", err)
 	}
@@ -0,0 +1,411 @@
+package database
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// migrationFiles holds the numbered schema migrations built into the binary,
+// one directory per driver with its own version sequence. Every version has
+// an up and a down file: NNNN_name.up.sql, NNNN_name.down.sql.
+//
+//go:embed migrations/postgres/*.sql migrations/sqlite/*.sql
+var migrationFiles embed.FS
+
+var migrationFilePattern = regexp.MustCompile(`^(\d+)_([a-z0-9_]+)\.(up|down)\.sql$`)
+
+// baselineVersion is the PostgreSQL migration holding the schema AutoMigrate
+// created before migrations were versioned. Databases created that way are
+// adopted at this version rather than migrated, and the later migrations
+// bring them up to date.
+const baselineVersion = 1
+
+var (
+	// ErrSchemaTooNew means the database was migrated by a newer build
+	ErrSchemaTooNew = errors.New("database schema is newer than this build")
+	// ErrPendingMigrations means the database is behind this build
+	ErrPendingMigrations = errors.New("database schema has pending migrations")
+	// ErrBaselineMismatch means an unversioned database lacks part of the
+	// baseline schema, so it cannot be adopted
+	ErrBaselineMismatch = errors.New("existing database schema does not match the baseline migrations")
+)
+
+// Migration is one numbered schema change
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+// MigrationStatus reports whether a migration has been applied. Unknown marks
+// a version recorded in the database that this build does not have.
+type MigrationStatus struct {
+	Version   int
+	Name      string
+	AppliedAt *time.Time
+	Unknown   bool
+}
+
+// schemaMigration is a row of the schema_migrations table
+type schemaMigration struct {
+	Version   int       `gorm:"primaryKey;autoIncrement:false"`
+	Name      string    `gorm:"size:255;not null"`
+	AppliedAt time.Time `gorm:"not null"`
+}
+
+// TableName specifies the table name for GORM
+func (schemaMigration) TableName() string {
+	return "schema_migrations"
+}
+
+// Migrations returns the embedded migrations for a driver in version order
+func Migrations(driver string) ([]Migration, error) {
+	dir := "migrations/" + driver
+	entries, err := fs.ReadDir(migrationFiles, dir)
+	if err != nil {
+		return nil, fmt.Errorf("no migrations for driver %q: %w", driver, err)
+	}
+
+	byVersion := make(map[int]*Migration)
+	for _, entry := range entries {
+		match := migrationFilePattern.FindStringSubmatch(entry.Name())
+		if match == nil {
+			return nil, fmt.Errorf("migration %s is not named NNNN_name.up.sql or NNNN_name.down.sql", entry.Name())
+		}
+		version, _ := strconv.Atoi(match[1])
+		if version <= 0 {
+			return nil, fmt.Errorf("migration %s: versions start at 1", entry.Name())
+		}
+		content, err := fs.ReadFile(migrationFiles, dir+"/"+entry.Name())
+		if err != nil {
+			return nil, fmt.Errorf("failed to read migration %s: %w", entry.Name(), err)
+		}
+
+		migration := byVersion[version]
+		if migration == nil {
+			migration = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = migration
+		}
+		if migration.Name != match[2] {
+			return nil, fmt.Errorf("migration %d is named both %s and %s", version, migration.Name, match[2])
+		}
+		if match[3] == "up" {
+			migration.Up = string(content)
+		} else {
+			migration.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.Up == "" || migration.Down == "" {
+			return nil, fmt.Errorf("migration %d_%s needs both an up and a down file", migration.Version, migration.Name)
+		}
+		migrations = append(migrations, *migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+	return migrations, nil
+}
+
+// EnsureSchema readies the database for this build at startup. It refuses a
+// schema migrated by a newer build; pending migrations are applied when apply
+// is set and are an error otherwise.
+func EnsureSchema(db *gorm.DB, apply bool) error {
+	migrations, applied, err := loadMigrationState(db)
+	if err != nil {
+		return err
+	}
+
+	pending := pendingMigrations(migrations, applied)
+	if len(pending) == 0 {
+		return nil
+	}
+	if !apply {
+		return fmt.Errorf("%w: %d to apply, run the migrate up command", ErrPendingMigrations, len(pending))
+	}
+	_, err = applyMigrations(db, pending)
+	return err
+}
+
+// MigrateUp applies every pending migration in version order and returns the
+// ones applied
+func MigrateUp(db *gorm.DB) ([]Migration, error) {
+	migrations, applied, err := loadMigrationState(db)
+	if err != nil {
+		return nil, err
+	}
+	return applyMigrations(db, pendingMigrations(migrations, applied))
+}
+
+// MigrateDown rolls back the latest applied migrations, newest first, and
+// returns the ones rolled back
+func MigrateDown(db *gorm.DB, steps int) ([]Migration, error) {
+	if steps <= 0 {
+		return nil, fmt.Errorf("steps must be positive")
+	}
+	migrations, applied, err := loadMigrationState(db)
+	if err != nil {
+		return nil, err
+	}
+
+	versions := make([]int, 0, len(applied))
+	for version := range applied {
+		versions = append(versions, version)
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(versions)))
+	if steps > len(versions) {
+		steps = len(versions)
+	}
+
+	known := make(map[int]Migration, len(migrations))
+	for _, migration := range migrations {
+		known[migration.Version] = migration
+	}
+
+	rolledBack := []Migration{}
+	for _, version := range versions[:steps] {
+		migration, ok := known[version]
+		if !ok {
+			return rolledBack, fmt.Errorf("migration %d is not part of this build", version)
+		}
+		err := db.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Exec(migration.Down).Error; err != nil {
+				return err
+			}
+			return tx.Delete(&schemaMigration{}, "version = ?", version).Error
+		})
+		if err != nil {
+			return rolledBack, fmt.Errorf("failed to roll back migration %d_%s: %w", version, migration.Name, err)
+		}
+		log.Printf("Rolled back migration %d_%s", version, migration.Name)
+		rolledBack = append(rolledBack, migration)
+	}
+	return rolledBack, nil
+}
+
+// MigrationStatuses lists this build's migrations with when each was
+// applied, followed by any applied versions the build does not know
+func MigrationStatuses(db *gorm.DB) ([]MigrationStatus, error) {
+	if db == nil {
+		return nil, fmt.Errorf("database connection not established")
+	}
+	migrations, err := Migrations(db.Dialector.Name())
+	if err != nil {
+		return nil, err
+	}
+	if err := createMigrationsTable(db); err != nil {
+		return nil, err
+	}
+	applied, err := appliedMigrations(db)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]MigrationStatus, 0, len(migrations))
+	for _, migration := range migrations {
+		status := MigrationStatus{Version: migration.Version, Name: migration.Name}
+		if row, ok := applied[migration.Version]; ok {
+			appliedAt := row.AppliedAt
+			status.AppliedAt = &appliedAt
+			delete(applied, migration.Version)
+		}
+		statuses = append(statuses, status)
+	}
+	for _, row := range applied {
+		appliedAt := row.AppliedAt
+		statuses = append(statuses, MigrationStatus{
+			Version:   row.Version,
+			Name:      row.Name,
+			AppliedAt: &appliedAt,
+			Unknown:   true,
+		})
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Version < statuses[j].Version
+	})
+	return statuses, nil
+}
+
+// loadMigrationState reads the embedded migrations and the applied versions,
+// adopting a database AutoMigrate created and refusing one that is newer
+// than this build
+func loadMigrationState(db *gorm.DB) ([]Migration, map[int]schemaMigration, error) {
+	if db == nil {
+		return nil, nil, fmt.Errorf("database connection not established")
+	}
+	migrations, err := Migrations(db.Dialector.Name())
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := createMigrationsTable(db); err != nil {
+		return nil, nil, err
+	}
+	applied, err := appliedMigrations(db)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(applied) == 0 && db.Dialector.Name() == DriverPostgres && db.Migrator().HasTable("users") {
+		if err := adoptBaseline(db, migrations); err != nil {
+			return nil, nil, err
+		}
+		if applied, err = appliedMigrations(db); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	latest := 0
+	if len(migrations) > 0 {
+		latest = migrations[len(migrations)-1].Version
+	}
+	for version := range applied {
+		if version > latest {
+			return nil, nil, fmt.Errorf("%w: database is at migration %d, this build knows up to %d", ErrSchemaTooNew, version, latest)
+		}
+	}
+	return migrations, applied, nil
+}
+
+// createMigrationsTable creates the table recording applied migrations
+func createMigrationsTable(db *gorm.DB) error {
+	timestamp := "timestamptz"
+	if db.Dialector.Name() == DriverSQLite {
+		timestamp = "datetime"
+	}
+	err := db.Exec(`CREATE TABLE IF NOT EXISTS schema_migrations (
+		version bigint PRIMARY KEY,
+		name varchar(255) NOT NULL,
+		applied_at ` + timestamp + ` NOT NULL
+	)`).Error
+	if err != nil {
+		return fmt.Errorf("failed to create schema_migrations: %w", err)
+	}
+	return nil
+}
+
+// appliedMigrations returns the applied migrations keyed by version
+func appliedMigrations(db *gorm.DB) (map[int]schemaMigration, error) {
+	var rows []schemaMigration
+	if err := db.Find(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to read schema_migrations: %w", err)
+	}
+	applied := make(map[int]schemaMigration, len(rows))
+	for _, row := range rows {
+		applied[row.Version] = row
+	}
+	return applied, nil
+}
+
+// adoptBaseline records the baseline migrations as applied to a database
+// whose tables AutoMigrate created, once it has checked that every table,
+// constraint and index they create is there
+func adoptBaseline(db *gorm.DB, migrations []Migration) error {
+	rows := []schemaMigration{}
+	for _, migration := range migrations {
+		if migration.Version > baselineVersion {
+			break
+		}
+		rows = append(rows, schemaMigration{
+			Version:   migration.Version,
+			Name:      migration.Name,
+			AppliedAt: time.Now().UTC(),
+		})
+	}
+	if len(rows) == 0 {
+		return nil
+	}
+	if missing := missingBaselineObjects(db, migrations[:len(rows)]); len(missing) > 0 {
+		return fmt.Errorf("%w: missing %s", ErrBaselineMismatch, strings.Join(missing, ", "))
+	}
+	if err := db.Create(&rows).Error; err != nil {
+		return fmt.Errorf("failed to adopt existing schema: %w", err)
+	}
+	log.Printf("Adopted existing schema at migration %d", rows[len(rows)-1].Version)
+	return nil
+}
+
+var (
+	createTableLine = regexp.MustCompile(`^CREATE TABLE (\w+) \(`)
+	constraintLine  = regexp.MustCompile(`^\s+CONSTRAINT (\w+) `)
+	createIndexLine = regexp.MustCompile(`^CREATE (?:UNIQUE )?INDEX (\w+) ON (\w+)`)
+)
+
+// missingBaselineObjects lists the tables, named constraints and indexes the
+// migrations create that the database does not have, e.g. "constraint
+// chk_comments_depth on comments". A missing table stands for everything on it.
+func missingBaselineObjects(db *gorm.DB, migrations []Migration) []string {
+	migrator := db.Migrator()
+	var missing []string
+	missingTables := make(map[string]bool)
+	for _, migration := range migrations {
+		table := ""
+		for _, line := range strings.Split(migration.Up, "\n") {
+			if match := createTableLine.FindStringSubmatch(line); match != nil {
+				table = match[1]
+				if !migrator.HasTable(table) {
+					missingTables[table] = true
+					missing = append(missing, "table "+table)
+				}
+			} else if match := constraintLine.FindStringSubmatch(line); match != nil && table != "" {
+				if !missingTables[table] && !migrator.HasConstraint(table, match[1]) {
+					missing = append(missing, "constraint "+match[1]+" on "+table)
+				}
+			} else if match := createIndexLine.FindStringSubmatch(line); match != nil {
+				if !missingTables[match[2]] && !migrator.HasIndex(match[2], match[1]) {
+					missing = append(missing, "index "+match[1]+" on "+match[2])
+				}
+			} else if strings.HasPrefix(line, ")") {
+				table = ""
+			}
+		}
+	}
+	return missing
+}
+
+// pendingMigrations returns the migrations not yet applied, in version order
+func pendingMigrations(migrations []Migration, applied map[int]schemaMigration) []Migration {
+	pending := []Migration{}
+	for _, migration := range migrations {
+		if _, ok := applied[migration.Version]; !ok {
+			pending = append(pending, migration)
+		}
+	}
+	return pending
+}
+
+// applyMigrations runs each migration with its schema_migrations row in one
+// transaction, stopping at the first failure
+func applyMigrations(db *gorm.DB, pending []Migration) ([]Migration, error) {
+	applied := []Migration{}
+	for _, migration := range pending {
+		err := db.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Exec(migration.Up).Error; err != nil {
+				return err
+			}
+			return tx.Create(&schemaMigration{
+				Version:   migration.Version,
+				Name:      migration.Name,
+				AppliedAt: time.Now().UTC(),
+			}).Error
+		})
+		if err != nil {
+			return applied, fmt.Errorf("failed to apply migration %d_%s: %w", migration.Version, migration.Name, err)
+		}
+		log.Printf("Applied migration %d_%s", migration.Version, migration.Name)
+		applied = append(applied, migration)
+	}
+	return applied, nil
+}
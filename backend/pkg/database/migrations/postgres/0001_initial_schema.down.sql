@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS buying_box_criteria;
+DROP TABLE IF EXISTS comments;
+DROP TABLE IF EXISTS financial_metrics;
+DROP TABLE IF EXISTS property_valuations;
+DROP TABLE IF EXISTS properties;
+DROP TABLE IF EXISTS users;
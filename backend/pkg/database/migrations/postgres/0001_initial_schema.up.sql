@@ -0,0 +1,118 @@
+-- Baseline schema: the tables AutoMigrate created before migrations were
+-- versioned. Databases created that way are adopted at this version and
+-- upgraded by the migrations that follow.
+
+CREATE EXTENSION IF NOT EXISTS "uuid-ossp";
+
+CREATE TABLE users (
+    id uuid DEFAULT uuid_generate_v4(),
+    email varchar(255) NOT NULL,
+    password_hash varchar(255) NOT NULL,
+    first_name varchar(50) NOT NULL,
+    last_name varchar(50) NOT NULL,
+    is_active boolean DEFAULT true,
+    created_at timestamptz,
+    updated_at timestamptz,
+    PRIMARY KEY (id),
+    CONSTRAINT uni_users_email UNIQUE (email)
+);
+
+CREATE TABLE properties (
+    id uuid DEFAULT uuid_generate_v4(),
+    user_id uuid NOT NULL,
+    address varchar(255) NOT NULL,
+    year_built bigint,
+    land_area_sqft bigint,
+    building_area_sqft bigint,
+    purchase_price decimal(12,2) NOT NULL,
+    intended_rent decimal(10,2),
+    operating_expenses jsonb DEFAULT '{}',
+    financing_terms jsonb DEFAULT '{}',
+    operating_assumptions jsonb DEFAULT '{}',
+    local_context jsonb DEFAULT '{}',
+    created_at timestamptz,
+    updated_at timestamptz,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_users_properties FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT chk_properties_year_built CHECK (year_built >= 1800 AND year_built <= EXTRACT(YEAR FROM NOW()) + 1),
+    CONSTRAINT chk_properties_land_area_sqft CHECK (land_area_sqft > 0),
+    CONSTRAINT chk_properties_building_area_sqft CHECK (building_area_sqft > 0)
+);
+CREATE INDEX idx_properties_user_id ON properties (user_id);
+
+CREATE TABLE property_valuations (
+    id uuid DEFAULT uuid_generate_v4(),
+    property_id uuid NOT NULL,
+    source varchar(50) NOT NULL,
+    valuation_type varchar(20) NOT NULL,
+    value decimal(12,2) NOT NULL,
+    valuation_date date NOT NULL,
+    created_at timestamptz,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_properties_valuations FOREIGN KEY (property_id) REFERENCES properties(id) ON DELETE CASCADE,
+    CONSTRAINT chk_property_valuations_source CHECK (source IN ('Zillow', 'Redfin', 'Rentimate')),
+    CONSTRAINT chk_property_valuations_valuation_type CHECK (valuation_type IN ('market_value', 'rental_estimate')),
+    CONSTRAINT chk_property_valuations_value CHECK (value > 0)
+);
+CREATE INDEX idx_property_valuations_property_id ON property_valuations (property_id);
+
+CREATE TABLE financial_metrics (
+    id uuid DEFAULT uuid_generate_v4(),
+    property_id uuid NOT NULL,
+    monthly_mortgage_payment decimal(10,2),
+    net_operating_income decimal(10,2),
+    cap_rate decimal(5,2),
+    cash_on_cash_return decimal(5,2),
+    cash_to_close decimal(12,2),
+    rent_to_value_ratio decimal(5,2),
+    gross_rent_multiplier decimal(5,2),
+    calculated_at timestamptz,
+    is_current boolean DEFAULT true,
+    PRIMARY KEY (id),
+    CONSTRAINT uni_financial_metrics_property_id UNIQUE (property_id),
+    CONSTRAINT fk_properties_financial_metrics FOREIGN KEY (property_id) REFERENCES properties(id) ON DELETE CASCADE
+);
+CREATE INDEX idx_financial_metrics_cash_on_cash_return ON financial_metrics (cash_on_cash_return);
+CREATE INDEX idx_financial_metrics_cap_rate ON financial_metrics (cap_rate);
+CREATE INDEX idx_financial_metrics_property_id ON financial_metrics (property_id);
+
+CREATE TABLE comments (
+    id uuid DEFAULT uuid_generate_v4(),
+    property_id uuid NOT NULL,
+    user_id uuid NOT NULL,
+    content text NOT NULL,
+    parent_id uuid,
+    created_at timestamptz,
+    updated_at timestamptz,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_comments_replies FOREIGN KEY (parent_id) REFERENCES comments(id) ON DELETE CASCADE,
+    CONSTRAINT fk_properties_comments FOREIGN KEY (property_id) REFERENCES properties(id) ON DELETE CASCADE,
+    CONSTRAINT fk_users_comments FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT chk_comments_content CHECK (LENGTH(TRIM(content)) > 0 AND LENGTH(content) <= 2000)
+);
+CREATE INDEX idx_comments_created_at ON comments (created_at);
+CREATE INDEX idx_comments_parent_id ON comments (parent_id);
+CREATE INDEX idx_comments_user_id ON comments (user_id);
+CREATE INDEX idx_comments_property_id ON comments (property_id);
+
+CREATE TABLE buying_box_criteria (
+    id uuid DEFAULT uuid_generate_v4(),
+    user_id uuid NOT NULL,
+    name varchar(100) NOT NULL,
+    min_cap_rate decimal(5,2),
+    min_cash_on_cash decimal(5,2),
+    max_purchase_price decimal(12,2),
+    min_rent_to_value decimal(5,2),
+    max_year_built bigint,
+    min_year_built bigint,
+    location_preferences jsonb DEFAULT '{}',
+    property_type_preferences jsonb DEFAULT '{}',
+    is_active boolean DEFAULT true,
+    created_at timestamptz,
+    updated_at timestamptz,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_users_buying_box_criterias FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT chk_buying_box_criteria_min_year_built CHECK (min_year_built IS NULL OR max_year_built IS NULL OR min_year_built <= max_year_built)
+);
+CREATE INDEX idx_buying_box_criteria_is_active ON buying_box_criteria (is_active);
+CREATE INDEX idx_buying_box_criteria_user_id ON buying_box_criteria (user_id);
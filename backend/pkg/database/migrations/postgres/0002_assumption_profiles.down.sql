@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_properties_assumption_profile_id;
+ALTER TABLE properties DROP COLUMN IF EXISTS defaulted_fields;
+ALTER TABLE properties DROP COLUMN IF EXISTS assumption_profile_id;
+
+DROP TABLE IF EXISTS assumption_profiles;
@@ -0,0 +1,32 @@
+-- Market assumption profiles per ZIP code or metro, used as property
+-- defaults. Properties remember the profile and the fields it filled in.
+
+CREATE TABLE assumption_profiles (
+    id uuid DEFAULT uuid_generate_v4(),
+    user_id uuid NOT NULL,
+    name varchar(100) NOT NULL,
+    scope varchar(10) NOT NULL DEFAULT 'user',
+    zip_code varchar(10),
+    metro varchar(100),
+    vacancy_rate decimal(5,4),
+    maintenance_pct decimal(5,4),
+    management_pct decimal(5,4),
+    tax_rate_pct decimal(5,3),
+    insurance_per1000 decimal(8,2),
+    rent_growth_pct decimal(5,2),
+    appreciation_pct decimal(5,2),
+    created_at timestamptz,
+    updated_at timestamptz,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_assumption_profiles_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT chk_assumption_profiles_scope CHECK (scope IN ('user', 'team'))
+);
+CREATE INDEX idx_assumption_profiles_metro ON assumption_profiles (metro);
+CREATE INDEX idx_assumption_profiles_zip_code ON assumption_profiles (zip_code);
+CREATE INDEX idx_assumption_profiles_user_id ON assumption_profiles (user_id);
+
+ALTER TABLE properties ADD COLUMN assumption_profile_id uuid;
+ALTER TABLE properties ADD COLUMN defaulted_fields jsonb DEFAULT '{}';
+ALTER TABLE properties ADD CONSTRAINT fk_properties_assumption_profile
+    FOREIGN KEY (assumption_profile_id) REFERENCES assumption_profiles(id) ON DELETE SET NULL;
+CREATE INDEX idx_properties_assumption_profile_id ON properties (assumption_profile_id);
@@ -0,0 +1,22 @@
+-- What-if versions of a deal, overriding the property's financial inputs
+
+CREATE TABLE scenarios (
+    id uuid DEFAULT uuid_generate_v4(),
+    property_id uuid NOT NULL,
+    user_id uuid NOT NULL,
+    name varchar(100) NOT NULL,
+    description text,
+    purchase_price decimal(12,2),
+    intended_rent decimal(10,2),
+    operating_expenses jsonb DEFAULT '{}',
+    financing_terms jsonb DEFAULT '{}',
+    operating_assumptions jsonb DEFAULT '{}',
+    created_at timestamptz,
+    updated_at timestamptz,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_scenarios_property FOREIGN KEY (property_id) REFERENCES properties(id) ON DELETE CASCADE,
+    CONSTRAINT fk_scenarios_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT chk_scenarios_purchase_price CHECK (purchase_price IS NULL OR purchase_price > 0)
+);
+CREATE INDEX idx_scenarios_user_id ON scenarios (user_id);
+CREATE INDEX idx_scenarios_property_id ON scenarios (property_id);
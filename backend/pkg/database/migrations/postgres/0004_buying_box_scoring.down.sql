@@ -0,0 +1,4 @@
+ALTER TABLE buying_box_criteria DROP CONSTRAINT IF EXISTS chk_buying_box_criteria_missing_data_policy;
+ALTER TABLE buying_box_criteria DROP COLUMN IF EXISTS missing_data_policy;
+ALTER TABLE buying_box_criteria DROP COLUMN IF EXISTS scoring_rules;
+ALTER TABLE buying_box_criteria DROP COLUMN IF EXISTS custom_rules;
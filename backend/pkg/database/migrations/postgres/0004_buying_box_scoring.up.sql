@@ -0,0 +1,8 @@
+-- Weighted scoring, custom rule expressions and the missing-data policy of
+-- buying-box criteria
+
+ALTER TABLE buying_box_criteria ADD COLUMN custom_rules jsonb DEFAULT '[]';
+ALTER TABLE buying_box_criteria ADD COLUMN scoring_rules jsonb DEFAULT '{}';
+ALTER TABLE buying_box_criteria ADD COLUMN missing_data_policy varchar(10) DEFAULT 'flag';
+ALTER TABLE buying_box_criteria ADD CONSTRAINT chk_buying_box_criteria_missing_data_policy
+    CHECK (missing_data_policy IN ('fail', 'ignore', 'flag'));
@@ -0,0 +1,10 @@
+DROP INDEX IF EXISTS idx_properties_city;
+DROP INDEX IF EXISTS idx_properties_state;
+DROP INDEX IF EXISTS idx_properties_zip_code;
+DROP INDEX IF EXISTS idx_properties_property_type;
+ALTER TABLE properties DROP COLUMN IF EXISTS property_type;
+ALTER TABLE properties DROP COLUMN IF EXISTS longitude;
+ALTER TABLE properties DROP COLUMN IF EXISTS latitude;
+ALTER TABLE properties DROP COLUMN IF EXISTS zip_code;
+ALTER TABLE properties DROP COLUMN IF EXISTS state;
+ALTER TABLE properties DROP COLUMN IF EXISTS city;
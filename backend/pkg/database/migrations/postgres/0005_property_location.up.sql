@@ -0,0 +1,20 @@
+-- Location and property type, matched against buying-box preferences.
+-- City, state and ZIP are parsed from the address the next time a property
+-- is saved.
+
+ALTER TABLE properties ADD COLUMN city varchar(100);
+ALTER TABLE properties ADD COLUMN state varchar(2);
+ALTER TABLE properties ADD COLUMN zip_code varchar(10);
+ALTER TABLE properties ADD COLUMN latitude decimal(9,6);
+ALTER TABLE properties ADD COLUMN longitude decimal(9,6);
+ALTER TABLE properties ADD COLUMN property_type varchar(20);
+ALTER TABLE properties ADD CONSTRAINT chk_properties_latitude
+    CHECK (latitude IS NULL OR (latitude >= -90 AND latitude <= 90));
+ALTER TABLE properties ADD CONSTRAINT chk_properties_longitude
+    CHECK (longitude IS NULL OR (longitude >= -180 AND longitude <= 180));
+ALTER TABLE properties ADD CONSTRAINT chk_properties_property_type
+    CHECK (property_type IS NULL OR property_type IN ('sfr', 'duplex', 'triplex', 'fourplex', 'multifamily', 'condo', 'townhouse'));
+CREATE INDEX idx_properties_property_type ON properties (property_type);
+CREATE INDEX idx_properties_zip_code ON properties (zip_code);
+CREATE INDEX idx_properties_state ON properties (state);
+CREATE INDEX idx_properties_city ON properties (city);
@@ -0,0 +1,21 @@
+-- Properties that matched a saved buying box, one row per criteria and
+-- property pair
+
+CREATE TABLE criteria_matches (
+    id uuid DEFAULT uuid_generate_v4(),
+    criteria_id uuid NOT NULL,
+    property_id uuid NOT NULL,
+    user_id uuid NOT NULL,
+    score decimal(5,2) NOT NULL,
+    completeness decimal(5,2) NOT NULL,
+    read_at timestamptz,
+    created_at timestamptz,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_criteria_matches_criteria FOREIGN KEY (criteria_id) REFERENCES buying_box_criteria(id) ON DELETE CASCADE,
+    CONSTRAINT fk_criteria_matches_property FOREIGN KEY (property_id) REFERENCES properties(id) ON DELETE CASCADE,
+    CONSTRAINT fk_criteria_matches_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+CREATE INDEX idx_criteria_matches_created_at ON criteria_matches (created_at);
+CREATE INDEX idx_criteria_matches_user_id ON criteria_matches (user_id);
+CREATE INDEX idx_criteria_matches_property_id ON criteria_matches (property_id);
+CREATE UNIQUE INDEX idx_criteria_match_pair ON criteria_matches (criteria_id,property_id);
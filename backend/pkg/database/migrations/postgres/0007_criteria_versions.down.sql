@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS buying_box_criteria_versions;
+ALTER TABLE criteria_matches DROP COLUMN IF EXISTS criteria_version;
+ALTER TABLE buying_box_criteria DROP COLUMN IF EXISTS version;
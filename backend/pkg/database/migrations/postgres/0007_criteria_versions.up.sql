@@ -0,0 +1,34 @@
+-- Immutable snapshots of the scoring definition of buying-box criteria.
+-- Existing criteria start at version 1, recorded from their current
+-- definition, and their matches are attributed to it.
+
+ALTER TABLE buying_box_criteria ADD COLUMN version bigint NOT NULL DEFAULT 1;
+ALTER TABLE criteria_matches ADD COLUMN criteria_version bigint NOT NULL DEFAULT 1;
+
+CREATE TABLE buying_box_criteria_versions (
+    id uuid DEFAULT uuid_generate_v4(),
+    criteria_id uuid NOT NULL,
+    version bigint NOT NULL,
+    definition jsonb NOT NULL,
+    created_by uuid NOT NULL,
+    created_at timestamptz,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_buying_box_criteria_versions_criteria FOREIGN KEY (criteria_id) REFERENCES buying_box_criteria(id) ON DELETE CASCADE
+);
+CREATE UNIQUE INDEX idx_criteria_version ON buying_box_criteria_versions (criteria_id,version);
+
+INSERT INTO buying_box_criteria_versions (criteria_id, version, definition, created_by, created_at)
+SELECT id, 1, jsonb_build_object(
+        'min_cap_rate', min_cap_rate,
+        'min_cash_on_cash', min_cash_on_cash,
+        'max_purchase_price', max_purchase_price,
+        'min_rent_to_value', min_rent_to_value,
+        'max_year_built', max_year_built,
+        'min_year_built', min_year_built,
+        'location_preferences', coalesce(location_preferences, '{}'),
+        'property_type_preferences', coalesce(property_type_preferences, '{}'),
+        'custom_rules', coalesce(custom_rules, '[]'),
+        'scoring_rules', coalesce(scoring_rules, '{}'),
+        'missing_data_policy', coalesce(missing_data_policy, 'flag')
+    ), user_id, coalesce(updated_at, created_at, now())
+FROM buying_box_criteria;
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_comments_thread_id;
+ALTER TABLE comments DROP CONSTRAINT IF EXISTS chk_comments_depth;
+ALTER TABLE comments DROP COLUMN IF EXISTS depth;
+ALTER TABLE comments DROP COLUMN IF EXISTS thread_id;
@@ -0,0 +1,18 @@
+-- Comments record their thread, the top-level comment's ID, and their depth
+-- below it. Existing replies are placed by walking up their parents.
+
+ALTER TABLE comments ADD COLUMN thread_id uuid;
+ALTER TABLE comments ADD COLUMN depth bigint NOT NULL DEFAULT 0;
+
+WITH RECURSIVE threads AS (
+    SELECT id, id AS thread_id, 0 AS depth FROM comments WHERE parent_id IS NULL
+    UNION ALL
+    SELECT comments.id, threads.thread_id, threads.depth + 1
+    FROM comments JOIN threads ON comments.parent_id = threads.id
+)
+UPDATE comments SET thread_id = threads.thread_id, depth = threads.depth
+FROM threads WHERE threads.id = comments.id;
+
+ALTER TABLE comments ALTER COLUMN thread_id SET NOT NULL;
+ALTER TABLE comments ADD CONSTRAINT chk_comments_depth CHECK (depth >= 0);
+CREATE INDEX idx_comments_thread_id ON comments (thread_id);
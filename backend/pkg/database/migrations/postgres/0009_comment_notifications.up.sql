@@ -0,0 +1,32 @@
+-- @mentions in comments and the notifications they and replies send
+
+CREATE TABLE comment_mentions (
+    id uuid DEFAULT uuid_generate_v4(),
+    comment_id uuid NOT NULL,
+    user_id uuid NOT NULL,
+    created_at timestamptz,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_comment_mentions_comment FOREIGN KEY (comment_id) REFERENCES comments(id) ON DELETE CASCADE,
+    CONSTRAINT fk_comment_mentions_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+CREATE INDEX idx_comment_mentions_user_id ON comment_mentions (user_id);
+CREATE UNIQUE INDEX idx_comment_mention ON comment_mentions (comment_id,user_id);
+
+CREATE TABLE notifications (
+    id uuid DEFAULT uuid_generate_v4(),
+    user_id uuid NOT NULL,
+    actor_id uuid NOT NULL,
+    type varchar(20) NOT NULL,
+    comment_id uuid NOT NULL,
+    property_id uuid NOT NULL,
+    read_at timestamptz,
+    created_at timestamptz,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_notifications_actor FOREIGN KEY (actor_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT fk_notifications_comment FOREIGN KEY (comment_id) REFERENCES comments(id) ON DELETE CASCADE,
+    CONSTRAINT fk_notifications_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT chk_notifications_type CHECK (type IN ('mention', 'reply'))
+);
+CREATE INDEX idx_notifications_created_at ON notifications (created_at);
+CREATE INDEX idx_notifications_comment_id ON notifications (comment_id);
+CREATE INDEX idx_notifications_user_id ON notifications (user_id);
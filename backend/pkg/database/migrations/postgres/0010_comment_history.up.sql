@@ -0,0 +1,16 @@
+-- Soft-deleted comments stay in their threads as placeholders, and edits
+-- keep the earlier content as revisions
+
+ALTER TABLE comments ADD COLUMN deleted_at timestamptz;
+CREATE INDEX idx_comments_deleted_at ON comments (deleted_at);
+
+CREATE TABLE comment_revisions (
+    id uuid DEFAULT uuid_generate_v4(),
+    comment_id uuid NOT NULL,
+    content text NOT NULL,
+    written_at timestamptz NOT NULL,
+    created_at timestamptz,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_comment_revisions_comment FOREIGN KEY (comment_id) REFERENCES comments(id) ON DELETE CASCADE
+);
+CREATE INDEX idx_comment_revisions_comment_id ON comment_revisions (comment_id);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS property_votes;
+DROP TABLE IF EXISTS comment_reactions;
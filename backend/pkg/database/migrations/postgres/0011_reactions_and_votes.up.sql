@@ -0,0 +1,29 @@
+-- Emoji reactions on comments and pass/maybe/pursue votes on properties
+
+CREATE TABLE comment_reactions (
+    id uuid DEFAULT uuid_generate_v4(),
+    comment_id uuid NOT NULL,
+    user_id uuid NOT NULL,
+    emoji varchar(64) NOT NULL,
+    created_at timestamptz,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_comment_reactions_comment FOREIGN KEY (comment_id) REFERENCES comments(id) ON DELETE CASCADE,
+    CONSTRAINT fk_comment_reactions_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+CREATE INDEX idx_comment_reactions_user_id ON comment_reactions (user_id);
+CREATE UNIQUE INDEX idx_comment_reaction ON comment_reactions (comment_id,user_id,emoji);
+
+CREATE TABLE property_votes (
+    id uuid DEFAULT uuid_generate_v4(),
+    property_id uuid NOT NULL,
+    user_id uuid NOT NULL,
+    decision varchar(10) NOT NULL,
+    created_at timestamptz,
+    updated_at timestamptz,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_property_votes_property FOREIGN KEY (property_id) REFERENCES properties(id) ON DELETE CASCADE,
+    CONSTRAINT fk_property_votes_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT chk_property_votes_decision CHECK (decision IN ('pass', 'maybe', 'pursue'))
+);
+CREATE INDEX idx_property_votes_user_id ON property_votes (user_id);
+CREATE UNIQUE INDEX idx_property_vote ON property_votes (property_id,user_id);
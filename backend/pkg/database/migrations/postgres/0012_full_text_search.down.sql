@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_comments_search;
+ALTER TABLE comments DROP COLUMN IF EXISTS search_vector;
+
+DROP INDEX IF EXISTS idx_properties_search;
+ALTER TABLE properties DROP COLUMN IF EXISTS search_vector;
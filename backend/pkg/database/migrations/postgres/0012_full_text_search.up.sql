@@ -0,0 +1,13 @@
+-- Generated tsvector columns and GIN indexes behind full-text search.
+-- Property addresses weigh more than local context notes.
+
+ALTER TABLE properties ADD COLUMN search_vector tsvector
+    GENERATED ALWAYS AS (
+        setweight(to_tsvector('english', coalesce(address, '')), 'A') ||
+        setweight(to_tsvector('english', coalesce(local_context->>'notes', '')), 'B')
+    ) STORED;
+CREATE INDEX idx_properties_search ON properties USING GIN (search_vector);
+
+ALTER TABLE comments ADD COLUMN search_vector tsvector
+    GENERATED ALWAYS AS (to_tsvector('english', content)) STORED;
+CREATE INDEX idx_comments_search ON comments USING GIN (search_vector);
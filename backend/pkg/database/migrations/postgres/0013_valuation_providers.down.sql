@@ -0,0 +1,3 @@
+DELETE FROM property_valuations WHERE source NOT IN ('Zillow', 'Redfin', 'Rentimate');
+ALTER TABLE property_valuations ADD CONSTRAINT chk_property_valuations_source
+    CHECK (source IN ('Zillow', 'Redfin', 'Rentimate'));
@@ -0,0 +1,4 @@
+-- Valuation sources are validated against the provider registry instead of
+-- a fixed list
+
+ALTER TABLE property_valuations DROP CONSTRAINT IF EXISTS chk_property_valuations_source;
@@ -0,0 +1,7 @@
+ALTER TABLE property_valuations DROP COLUMN IF EXISTS value_high;
+ALTER TABLE property_valuations DROP COLUMN IF EXISTS value_low;
+
+ALTER TABLE properties DROP COLUMN IF EXISTS bathrooms;
+ALTER TABLE properties DROP COLUMN IF EXISTS bedrooms;
+
+DROP TABLE IF EXISTS comps;
@@ -0,0 +1,36 @@
+-- Comparable sales, the bedroom and bathroom counts they are adjusted on,
+-- and the value range of the valuations they produce
+
+CREATE TABLE comps (
+    id uuid DEFAULT uuid_generate_v4(),
+    property_id uuid NOT NULL,
+    user_id uuid NOT NULL,
+    address varchar(255) NOT NULL,
+    sale_price decimal(12,2) NOT NULL,
+    sale_date date NOT NULL,
+    building_area_sqft bigint,
+    bedrooms bigint,
+    bathrooms decimal(3,1),
+    year_built bigint,
+    distance_miles decimal(6,2),
+    created_at timestamptz,
+    updated_at timestamptz,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_comps_property FOREIGN KEY (property_id) REFERENCES properties(id) ON DELETE CASCADE,
+    CONSTRAINT fk_comps_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT chk_comps_sale_price CHECK (sale_price > 0),
+    CONSTRAINT chk_comps_building_area_sqft CHECK (building_area_sqft > 0),
+    CONSTRAINT chk_comps_bedrooms CHECK (bedrooms >= 0),
+    CONSTRAINT chk_comps_bathrooms CHECK (bathrooms >= 0),
+    CONSTRAINT chk_comps_distance_miles CHECK (distance_miles >= 0)
+);
+CREATE INDEX idx_comps_user_id ON comps (user_id);
+CREATE INDEX idx_comps_property_id ON comps (property_id);
+
+ALTER TABLE properties ADD COLUMN bedrooms bigint;
+ALTER TABLE properties ADD COLUMN bathrooms decimal(3,1);
+ALTER TABLE properties ADD CONSTRAINT chk_properties_bedrooms CHECK (bedrooms >= 0);
+ALTER TABLE properties ADD CONSTRAINT chk_properties_bathrooms CHECK (bathrooms >= 0);
+
+ALTER TABLE property_valuations ADD COLUMN value_low decimal(12,2);
+ALTER TABLE property_valuations ADD COLUMN value_high decimal(12,2);
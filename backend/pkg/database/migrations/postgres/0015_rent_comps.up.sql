@@ -0,0 +1,26 @@
+-- Rental comparables behind the suggested rent
+
+CREATE TABLE rent_comps (
+    id uuid DEFAULT uuid_generate_v4(),
+    property_id uuid NOT NULL,
+    user_id uuid NOT NULL,
+    address varchar(255) NOT NULL,
+    monthly_rent decimal(10,2) NOT NULL,
+    listing_date date NOT NULL,
+    building_area_sqft bigint,
+    bedrooms bigint,
+    bathrooms decimal(3,1),
+    distance_miles decimal(6,2),
+    created_at timestamptz,
+    updated_at timestamptz,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_rent_comps_property FOREIGN KEY (property_id) REFERENCES properties(id) ON DELETE CASCADE,
+    CONSTRAINT fk_rent_comps_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT chk_rent_comps_monthly_rent CHECK (monthly_rent > 0),
+    CONSTRAINT chk_rent_comps_building_area_sqft CHECK (building_area_sqft > 0),
+    CONSTRAINT chk_rent_comps_bedrooms CHECK (bedrooms >= 0),
+    CONSTRAINT chk_rent_comps_bathrooms CHECK (bathrooms >= 0),
+    CONSTRAINT chk_rent_comps_distance_miles CHECK (distance_miles >= 0)
+);
+CREATE INDEX idx_rent_comps_user_id ON rent_comps (user_id);
+CREATE INDEX idx_rent_comps_property_id ON rent_comps (property_id);
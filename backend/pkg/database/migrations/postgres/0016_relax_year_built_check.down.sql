@@ -0,0 +1,3 @@
+ALTER TABLE properties DROP CONSTRAINT IF EXISTS chk_properties_year_built;
+ALTER TABLE properties ADD CONSTRAINT chk_properties_year_built
+    CHECK (year_built >= 1800 AND year_built <= EXTRACT(YEAR FROM NOW()) + 1);
@@ -0,0 +1,5 @@
+-- The upper bound on year_built moved to request validation: a check that
+-- reads the clock is not portable and can reject rows on restore.
+
+ALTER TABLE properties DROP CONSTRAINT IF EXISTS chk_properties_year_built;
+ALTER TABLE properties ADD CONSTRAINT chk_properties_year_built CHECK (year_built >= 1800);
@@ -0,0 +1,13 @@
+ALTER TABLE criteria_matches ADD COLUMN read_at timestamptz;
+UPDATE criteria_matches SET read_at = notifications.read_at
+FROM notifications WHERE notifications.match_id = criteria_matches.id;
+
+DELETE FROM notifications WHERE type = 'match';
+ALTER TABLE notifications DROP CONSTRAINT IF EXISTS chk_notifications_subject;
+ALTER TABLE notifications DROP CONSTRAINT IF EXISTS chk_notifications_type;
+ALTER TABLE notifications ADD CONSTRAINT chk_notifications_type CHECK (type IN ('mention', 'reply'));
+
+DROP INDEX IF EXISTS idx_notifications_match_id;
+ALTER TABLE notifications DROP COLUMN match_id;
+ALTER TABLE notifications ALTER COLUMN comment_id SET NOT NULL;
+ALTER TABLE notifications ALTER COLUMN actor_id SET NOT NULL;
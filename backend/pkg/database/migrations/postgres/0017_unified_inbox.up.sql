@@ -0,0 +1,22 @@
+-- Buying-box matches join comment mentions and replies in the notifications
+-- inbox, which holds the read state for both
+ALTER TABLE notifications ALTER COLUMN actor_id DROP NOT NULL;
+ALTER TABLE notifications ALTER COLUMN comment_id DROP NOT NULL;
+ALTER TABLE notifications ADD COLUMN match_id uuid;
+ALTER TABLE notifications ADD CONSTRAINT fk_notifications_match
+    FOREIGN KEY (match_id) REFERENCES criteria_matches(id) ON DELETE CASCADE;
+CREATE INDEX idx_notifications_match_id ON notifications (match_id);
+
+ALTER TABLE notifications DROP CONSTRAINT chk_notifications_type;
+ALTER TABLE notifications ADD CONSTRAINT chk_notifications_type
+    CHECK (type IN ('mention', 'reply', 'match'));
+ALTER TABLE notifications ADD CONSTRAINT chk_notifications_subject CHECK (
+    (type = 'match' AND match_id IS NOT NULL AND comment_id IS NULL)
+    OR (type <> 'match' AND comment_id IS NOT NULL AND actor_id IS NOT NULL AND match_id IS NULL)
+);
+
+-- Existing alerts keep their ids and read state
+INSERT INTO notifications (id, user_id, type, match_id, property_id, read_at, created_at)
+SELECT id, user_id, 'match', id, property_id, read_at, created_at FROM criteria_matches;
+
+ALTER TABLE criteria_matches DROP COLUMN read_at;
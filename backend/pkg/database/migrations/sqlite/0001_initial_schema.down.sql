@@ -0,0 +1,17 @@
+DROP TABLE IF EXISTS rent_comps;
+DROP TABLE IF EXISTS comps;
+DROP TABLE IF EXISTS property_votes;
+DROP TABLE IF EXISTS comment_reactions;
+DROP TABLE IF EXISTS notifications;
+DROP TABLE IF EXISTS comment_mentions;
+DROP TABLE IF EXISTS criteria_matches;
+DROP TABLE IF EXISTS scenarios;
+DROP TABLE IF EXISTS buying_box_criteria_versions;
+DROP TABLE IF EXISTS buying_box_criteria;
+DROP TABLE IF EXISTS comment_revisions;
+DROP TABLE IF EXISTS comments;
+DROP TABLE IF EXISTS financial_metrics;
+DROP TABLE IF EXISTS property_valuations;
+DROP TABLE IF EXISTS properties;
+DROP TABLE IF EXISTS assumption_profiles;
+DROP TABLE IF EXISTS users;
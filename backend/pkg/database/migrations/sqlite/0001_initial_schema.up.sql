@@ -0,0 +1,346 @@
+-- Baseline schema for SQLite. IDs are generated by the application, JSON
+-- columns hold text, and timestamps are stored as datetime strings.
+
+CREATE TABLE users (
+    id text NOT NULL,
+    email varchar(255) NOT NULL,
+    password_hash varchar(255) NOT NULL,
+    first_name varchar(50) NOT NULL,
+    last_name varchar(50) NOT NULL,
+    is_active boolean DEFAULT true,
+    created_at datetime,
+    updated_at datetime,
+    PRIMARY KEY (id),
+    CONSTRAINT uni_users_email UNIQUE (email)
+);
+
+CREATE TABLE assumption_profiles (
+    id text NOT NULL,
+    user_id text NOT NULL,
+    name varchar(100) NOT NULL,
+    scope varchar(10) NOT NULL DEFAULT 'user',
+    zip_code varchar(10),
+    metro varchar(100),
+    vacancy_rate decimal(5,4),
+    maintenance_pct decimal(5,4),
+    management_pct decimal(5,4),
+    tax_rate_pct decimal(5,3),
+    insurance_per1000 decimal(8,2),
+    rent_growth_pct decimal(5,2),
+    appreciation_pct decimal(5,2),
+    created_at datetime,
+    updated_at datetime,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_assumption_profiles_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT chk_assumption_profiles_scope CHECK (scope IN ('user', 'team'))
+);
+CREATE INDEX idx_assumption_profiles_metro ON assumption_profiles (metro);
+CREATE INDEX idx_assumption_profiles_zip_code ON assumption_profiles (zip_code);
+CREATE INDEX idx_assumption_profiles_user_id ON assumption_profiles (user_id);
+
+CREATE TABLE properties (
+    id text NOT NULL,
+    user_id text NOT NULL,
+    address varchar(255) NOT NULL,
+    city varchar(100),
+    state varchar(2),
+    zip_code varchar(10),
+    latitude decimal(9,6),
+    longitude decimal(9,6),
+    property_type varchar(20),
+    year_built bigint,
+    land_area_sqft bigint,
+    building_area_sqft bigint,
+    bedrooms bigint,
+    bathrooms decimal(3,1),
+    purchase_price decimal(12,2) NOT NULL,
+    intended_rent decimal(10,2),
+    operating_expenses json DEFAULT '{}',
+    financing_terms json DEFAULT '{}',
+    operating_assumptions json DEFAULT '{}',
+    local_context json DEFAULT '{}',
+    assumption_profile_id text,
+    defaulted_fields json DEFAULT '{}',
+    created_at datetime,
+    updated_at datetime,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_properties_assumption_profile FOREIGN KEY (assumption_profile_id) REFERENCES assumption_profiles(id) ON DELETE SET NULL,
+    CONSTRAINT fk_users_properties FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT chk_properties_latitude CHECK (latitude IS NULL OR (latitude >= -90 AND latitude <= 90)),
+    CONSTRAINT chk_properties_longitude CHECK (longitude IS NULL OR (longitude >= -180 AND longitude <= 180)),
+    CONSTRAINT chk_properties_property_type CHECK (property_type IS NULL OR property_type IN ('sfr', 'duplex', 'triplex', 'fourplex', 'multifamily', 'condo', 'townhouse')),
+    CONSTRAINT chk_properties_year_built CHECK (year_built >= 1800),
+    CONSTRAINT chk_properties_land_area_sqft CHECK (land_area_sqft > 0),
+    CONSTRAINT chk_properties_building_area_sqft CHECK (building_area_sqft > 0),
+    CONSTRAINT chk_properties_bedrooms CHECK (bedrooms >= 0),
+    CONSTRAINT chk_properties_bathrooms CHECK (bathrooms >= 0)
+);
+CREATE INDEX idx_properties_assumption_profile_id ON properties (assumption_profile_id);
+CREATE INDEX idx_properties_property_type ON properties (property_type);
+CREATE INDEX idx_properties_zip_code ON properties (zip_code);
+CREATE INDEX idx_properties_state ON properties (state);
+CREATE INDEX idx_properties_city ON properties (city);
+CREATE INDEX idx_properties_user_id ON properties (user_id);
+
+CREATE TABLE property_valuations (
+    id text NOT NULL,
+    property_id text NOT NULL,
+    source varchar(50) NOT NULL,
+    valuation_type varchar(20) NOT NULL,
+    value decimal(12,2) NOT NULL,
+    value_low decimal(12,2),
+    value_high decimal(12,2),
+    valuation_date date NOT NULL,
+    created_at datetime,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_properties_valuations FOREIGN KEY (property_id) REFERENCES properties(id) ON DELETE CASCADE,
+    CONSTRAINT chk_property_valuations_valuation_type CHECK (valuation_type IN ('market_value', 'rental_estimate')),
+    CONSTRAINT chk_property_valuations_value CHECK (value > 0)
+);
+CREATE INDEX idx_property_valuations_property_id ON property_valuations (property_id);
+
+CREATE TABLE financial_metrics (
+    id text NOT NULL,
+    property_id text NOT NULL,
+    monthly_mortgage_payment decimal(10,2),
+    net_operating_income decimal(10,2),
+    cap_rate decimal(5,2),
+    cash_on_cash_return decimal(5,2),
+    cash_to_close decimal(12,2),
+    rent_to_value_ratio decimal(5,2),
+    gross_rent_multiplier decimal(5,2),
+    calculated_at datetime,
+    is_current boolean DEFAULT true,
+    PRIMARY KEY (id),
+    CONSTRAINT uni_financial_metrics_property_id UNIQUE (property_id),
+    CONSTRAINT fk_properties_financial_metrics FOREIGN KEY (property_id) REFERENCES properties(id) ON DELETE CASCADE
+);
+CREATE INDEX idx_financial_metrics_cash_on_cash_return ON financial_metrics (cash_on_cash_return);
+CREATE INDEX idx_financial_metrics_cap_rate ON financial_metrics (cap_rate);
+CREATE INDEX idx_financial_metrics_property_id ON financial_metrics (property_id);
+
+CREATE TABLE comments (
+    id text NOT NULL,
+    property_id text NOT NULL,
+    user_id text NOT NULL,
+    content text NOT NULL,
+    parent_id text,
+    thread_id text NOT NULL,
+    depth bigint NOT NULL DEFAULT 0,
+    created_at datetime,
+    updated_at datetime,
+    deleted_at datetime,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_comments_replies FOREIGN KEY (parent_id) REFERENCES comments(id) ON DELETE CASCADE,
+    CONSTRAINT fk_properties_comments FOREIGN KEY (property_id) REFERENCES properties(id) ON DELETE CASCADE,
+    CONSTRAINT fk_users_comments FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT chk_comments_content CHECK (LENGTH(TRIM(content)) > 0 AND LENGTH(content) <= 2000),
+    CONSTRAINT chk_comments_depth CHECK (depth >= 0)
+);
+CREATE INDEX idx_comments_deleted_at ON comments (deleted_at);
+CREATE INDEX idx_comments_created_at ON comments (created_at);
+CREATE INDEX idx_comments_thread_id ON comments (thread_id);
+CREATE INDEX idx_comments_parent_id ON comments (parent_id);
+CREATE INDEX idx_comments_user_id ON comments (user_id);
+CREATE INDEX idx_comments_property_id ON comments (property_id);
+
+CREATE TABLE comment_revisions (
+    id text NOT NULL,
+    comment_id text NOT NULL,
+    content text NOT NULL,
+    written_at datetime NOT NULL,
+    created_at datetime,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_comment_revisions_comment FOREIGN KEY (comment_id) REFERENCES comments(id) ON DELETE CASCADE
+);
+CREATE INDEX idx_comment_revisions_comment_id ON comment_revisions (comment_id);
+
+CREATE TABLE buying_box_criteria (
+    id text NOT NULL,
+    user_id text NOT NULL,
+    name varchar(100) NOT NULL,
+    min_cap_rate decimal(5,2),
+    min_cash_on_cash decimal(5,2),
+    max_purchase_price decimal(12,2),
+    min_rent_to_value decimal(5,2),
+    max_year_built bigint,
+    min_year_built bigint,
+    location_preferences json DEFAULT '{}',
+    property_type_preferences json DEFAULT '{}',
+    custom_rules json DEFAULT '[]',
+    scoring_rules json DEFAULT '{}',
+    missing_data_policy varchar(10) DEFAULT 'flag',
+    is_active boolean DEFAULT true,
+    version bigint NOT NULL DEFAULT 1,
+    created_at datetime,
+    updated_at datetime,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_users_buying_box_criterias FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT chk_buying_box_criteria_min_year_built CHECK (min_year_built IS NULL OR max_year_built IS NULL OR min_year_built <= max_year_built),
+    CONSTRAINT chk_buying_box_criteria_missing_data_policy CHECK (missing_data_policy IN ('fail', 'ignore', 'flag'))
+);
+CREATE INDEX idx_buying_box_criteria_is_active ON buying_box_criteria (is_active);
+CREATE INDEX idx_buying_box_criteria_user_id ON buying_box_criteria (user_id);
+
+CREATE TABLE buying_box_criteria_versions (
+    id text NOT NULL,
+    criteria_id text NOT NULL,
+    version bigint NOT NULL,
+    definition json NOT NULL,
+    created_by text NOT NULL,
+    created_at datetime,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_buying_box_criteria_versions_criteria FOREIGN KEY (criteria_id) REFERENCES buying_box_criteria(id) ON DELETE CASCADE
+);
+CREATE UNIQUE INDEX idx_criteria_version ON buying_box_criteria_versions (criteria_id,version);
+
+CREATE TABLE scenarios (
+    id text NOT NULL,
+    property_id text NOT NULL,
+    user_id text NOT NULL,
+    name varchar(100) NOT NULL,
+    description text,
+    purchase_price decimal(12,2),
+    intended_rent decimal(10,2),
+    operating_expenses json DEFAULT '{}',
+    financing_terms json DEFAULT '{}',
+    operating_assumptions json DEFAULT '{}',
+    created_at datetime,
+    updated_at datetime,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_scenarios_property FOREIGN KEY (property_id) REFERENCES properties(id) ON DELETE CASCADE,
+    CONSTRAINT fk_scenarios_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT chk_scenarios_purchase_price CHECK (purchase_price IS NULL OR purchase_price > 0)
+);
+CREATE INDEX idx_scenarios_user_id ON scenarios (user_id);
+CREATE INDEX idx_scenarios_property_id ON scenarios (property_id);
+
+CREATE TABLE criteria_matches (
+    id text NOT NULL,
+    criteria_id text NOT NULL,
+    property_id text NOT NULL,
+    user_id text NOT NULL,
+    criteria_version bigint NOT NULL DEFAULT 1,
+    score decimal(5,2) NOT NULL,
+    completeness decimal(5,2) NOT NULL,
+    read_at datetime,
+    created_at datetime,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_criteria_matches_criteria FOREIGN KEY (criteria_id) REFERENCES buying_box_criteria(id) ON DELETE CASCADE,
+    CONSTRAINT fk_criteria_matches_property FOREIGN KEY (property_id) REFERENCES properties(id) ON DELETE CASCADE,
+    CONSTRAINT fk_criteria_matches_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+CREATE INDEX idx_criteria_matches_created_at ON criteria_matches (created_at);
+CREATE INDEX idx_criteria_matches_user_id ON criteria_matches (user_id);
+CREATE INDEX idx_criteria_matches_property_id ON criteria_matches (property_id);
+CREATE UNIQUE INDEX idx_criteria_match_pair ON criteria_matches (criteria_id,property_id);
+
+CREATE TABLE comment_mentions (
+    id text NOT NULL,
+    comment_id text NOT NULL,
+    user_id text NOT NULL,
+    created_at datetime,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_comment_mentions_comment FOREIGN KEY (comment_id) REFERENCES comments(id) ON DELETE CASCADE,
+    CONSTRAINT fk_comment_mentions_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+CREATE INDEX idx_comment_mentions_user_id ON comment_mentions (user_id);
+CREATE UNIQUE INDEX idx_comment_mention ON comment_mentions (comment_id,user_id);
+
+CREATE TABLE notifications (
+    id text NOT NULL,
+    user_id text NOT NULL,
+    actor_id text NOT NULL,
+    type varchar(20) NOT NULL,
+    comment_id text NOT NULL,
+    property_id text NOT NULL,
+    read_at datetime,
+    created_at datetime,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_notifications_actor FOREIGN KEY (actor_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT fk_notifications_comment FOREIGN KEY (comment_id) REFERENCES comments(id) ON DELETE CASCADE,
+    CONSTRAINT fk_notifications_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT chk_notifications_type CHECK (type IN ('mention', 'reply'))
+);
+CREATE INDEX idx_notifications_created_at ON notifications (created_at);
+CREATE INDEX idx_notifications_comment_id ON notifications (comment_id);
+CREATE INDEX idx_notifications_user_id ON notifications (user_id);
+
+CREATE TABLE comment_reactions (
+    id text NOT NULL,
+    comment_id text NOT NULL,
+    user_id text NOT NULL,
+    emoji varchar(64) NOT NULL,
+    created_at datetime,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_comment_reactions_comment FOREIGN KEY (comment_id) REFERENCES comments(id) ON DELETE CASCADE,
+    CONSTRAINT fk_comment_reactions_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+CREATE INDEX idx_comment_reactions_user_id ON comment_reactions (user_id);
+CREATE UNIQUE INDEX idx_comment_reaction ON comment_reactions (comment_id,user_id,emoji);
+
+CREATE TABLE property_votes (
+    id text NOT NULL,
+    property_id text NOT NULL,
+    user_id text NOT NULL,
+    decision varchar(10) NOT NULL,
+    created_at datetime,
+    updated_at datetime,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_property_votes_property FOREIGN KEY (property_id) REFERENCES properties(id) ON DELETE CASCADE,
+    CONSTRAINT fk_property_votes_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT chk_property_votes_decision CHECK (decision IN ('pass', 'maybe', 'pursue'))
+);
+CREATE INDEX idx_property_votes_user_id ON property_votes (user_id);
+CREATE UNIQUE INDEX idx_property_vote ON property_votes (property_id,user_id);
+
+CREATE TABLE comps (
+    id text NOT NULL,
+    property_id text NOT NULL,
+    user_id text NOT NULL,
+    address varchar(255) NOT NULL,
+    sale_price decimal(12,2) NOT NULL,
+    sale_date date NOT NULL,
+    building_area_sqft bigint,
+    bedrooms bigint,
+    bathrooms decimal(3,1),
+    year_built bigint,
+    distance_miles decimal(6,2),
+    created_at datetime,
+    updated_at datetime,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_comps_property FOREIGN KEY (property_id) REFERENCES properties(id) ON DELETE CASCADE,
+    CONSTRAINT fk_comps_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT chk_comps_sale_price CHECK (sale_price > 0),
+    CONSTRAINT chk_comps_building_area_sqft CHECK (building_area_sqft > 0),
+    CONSTRAINT chk_comps_bedrooms CHECK (bedrooms >= 0),
+    CONSTRAINT chk_comps_bathrooms CHECK (bathrooms >= 0),
+    CONSTRAINT chk_comps_distance_miles CHECK (distance_miles >= 0)
+);
+CREATE INDEX idx_comps_user_id ON comps (user_id);
+CREATE INDEX idx_comps_property_id ON comps (property_id);
+
+CREATE TABLE rent_comps (
+    id text NOT NULL,
+    property_id text NOT NULL,
+    user_id text NOT NULL,
+    address varchar(255) NOT NULL,
+    monthly_rent decimal(10,2) NOT NULL,
+    listing_date date NOT NULL,
+    building_area_sqft bigint,
+    bedrooms bigint,
+    bathrooms decimal(3,1),
+    distance_miles decimal(6,2),
+    created_at datetime,
+    updated_at datetime,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_rent_comps_property FOREIGN KEY (property_id) REFERENCES properties(id) ON DELETE CASCADE,
+    CONSTRAINT fk_rent_comps_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT chk_rent_comps_monthly_rent CHECK (monthly_rent > 0),
+    CONSTRAINT chk_rent_comps_building_area_sqft CHECK (building_area_sqft > 0),
+    CONSTRAINT chk_rent_comps_bedrooms CHECK (bedrooms >= 0),
+    CONSTRAINT chk_rent_comps_bathrooms CHECK (bathrooms >= 0),
+    CONSTRAINT chk_rent_comps_distance_miles CHECK (distance_miles >= 0)
+);
+CREATE INDEX idx_rent_comps_user_id ON rent_comps (user_id);
+CREATE INDEX idx_rent_comps_property_id ON rent_comps (property_id);
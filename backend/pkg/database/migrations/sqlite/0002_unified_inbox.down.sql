@@ -0,0 +1,30 @@
+ALTER TABLE criteria_matches ADD COLUMN read_at datetime;
+UPDATE criteria_matches SET read_at = (
+    SELECT read_at FROM notifications WHERE notifications.match_id = criteria_matches.id
+);
+
+CREATE TABLE notifications_comments (
+    id text NOT NULL,
+    user_id text NOT NULL,
+    actor_id text NOT NULL,
+    type varchar(20) NOT NULL,
+    comment_id text NOT NULL,
+    property_id text NOT NULL,
+    read_at datetime,
+    created_at datetime,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_notifications_actor FOREIGN KEY (actor_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT fk_notifications_comment FOREIGN KEY (comment_id) REFERENCES comments(id) ON DELETE CASCADE,
+    CONSTRAINT fk_notifications_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT chk_notifications_type CHECK (type IN ('mention', 'reply'))
+);
+
+INSERT INTO notifications_comments (id, user_id, actor_id, type, comment_id, property_id, read_at, created_at)
+SELECT id, user_id, actor_id, type, comment_id, property_id, read_at, created_at
+FROM notifications WHERE type <> 'match';
+
+DROP TABLE notifications;
+ALTER TABLE notifications_comments RENAME TO notifications;
+CREATE INDEX idx_notifications_created_at ON notifications (created_at);
+CREATE INDEX idx_notifications_comment_id ON notifications (comment_id);
+CREATE INDEX idx_notifications_user_id ON notifications (user_id);
@@ -0,0 +1,40 @@
+-- Buying-box matches join comment mentions and replies in the notifications
+-- inbox, which holds the read state for both. SQLite cannot alter column
+-- constraints, so the table is rebuilt.
+CREATE TABLE notifications_unified (
+    id text NOT NULL,
+    user_id text NOT NULL,
+    actor_id text,
+    type varchar(20) NOT NULL,
+    comment_id text,
+    match_id text,
+    property_id text NOT NULL,
+    read_at datetime,
+    created_at datetime,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_notifications_actor FOREIGN KEY (actor_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT fk_notifications_comment FOREIGN KEY (comment_id) REFERENCES comments(id) ON DELETE CASCADE,
+    CONSTRAINT fk_notifications_match FOREIGN KEY (match_id) REFERENCES criteria_matches(id) ON DELETE CASCADE,
+    CONSTRAINT fk_notifications_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT chk_notifications_type CHECK (type IN ('mention', 'reply', 'match')),
+    CONSTRAINT chk_notifications_subject CHECK (
+        (type = 'match' AND match_id IS NOT NULL AND comment_id IS NULL)
+        OR (type <> 'match' AND comment_id IS NOT NULL AND actor_id IS NOT NULL AND match_id IS NULL)
+    )
+);
+
+INSERT INTO notifications_unified (id, user_id, actor_id, type, comment_id, property_id, read_at, created_at)
+SELECT id, user_id, actor_id, type, comment_id, property_id, read_at, created_at FROM notifications;
+
+-- Existing alerts keep their ids and read state
+INSERT INTO notifications_unified (id, user_id, type, match_id, property_id, read_at, created_at)
+SELECT id, user_id, 'match', id, property_id, read_at, created_at FROM criteria_matches;
+
+DROP TABLE notifications;
+ALTER TABLE notifications_unified RENAME TO notifications;
+CREATE INDEX idx_notifications_created_at ON notifications (created_at);
+CREATE INDEX idx_notifications_comment_id ON notifications (comment_id);
+CREATE INDEX idx_notifications_match_id ON notifications (match_id);
+CREATE INDEX idx_notifications_user_id ON notifications (user_id);
+
+ALTER TABLE criteria_matches DROP COLUMN read_at;
@@ -0,0 +1,145 @@
+// Package markdown renders the Markdown subset allowed in comments: paragraphs,
+// emphasis, links, lists, block quotes and code. Output is sanitized HTML safe to
+// embed in a page; raw HTML in the source is dropped, never rendered.
+package markdown
+
+import (
+	"bytes"
+	"strings"
+	"unicode"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/renderer/html"
+	"github.com/yuin/goldmark/text"
+)
+
+// Ellipsis marks truncated previews
+const Ellipsis = "..."
+
+var (
+	converter = goldmark.New(
+		goldmark.WithExtensions(extension.Linkify, extension.Strikethrough),
+		goldmark.WithRendererOptions(html.WithHardWraps()),
+	)
+
+	policy = newPolicy()
+)
+
+// newPolicy allows only the tags of the supported subset. Links must use
+// http, https or mailto (or be relative) and open without referrer access.
+func newPolicy() *bluemonday.Policy {
+	p := bluemonday.NewPolicy()
+	p.AllowElements("p", "br", "strong", "em", "del", "code", "pre", "blockquote", "ul", "ol", "li")
+	p.AllowAttrs("start").Matching(bluemonday.Integer).OnElements("ol")
+	p.AllowAttrs("href").OnElements("a")
+	p.AllowURLSchemes("http", "https", "mailto")
+	p.AllowRelativeURLs(true)
+	p.RequireParseableURLs(true)
+	p.RequireNoFollowOnLinks(true)
+	p.RequireNoReferrerOnLinks(true)
+	p.AddTargetBlankToFullyQualifiedLinks(true)
+	return p
+}
+
+// ToHTML renders source to sanitized HTML
+func ToHTML(source string) string {
+	var buf bytes.Buffer
+	if err := converter.Convert([]byte(source), &buf); err != nil {
+		// Rendering only fails on writer errors, which a buffer never returns
+		return policy.Sanitize(source)
+	}
+	return strings.TrimSpace(policy.Sanitize(buf.String()))
+}
+
+// PlainText strips markup from source, keeping the text a reader would see.
+// Blocks and line breaks become single spaces.
+func PlainText(source string) string {
+	src := []byte(source)
+	doc := converter.Parser().Parse(text.NewReader(src))
+
+	var buf strings.Builder
+	_ = ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		switch node := n.(type) {
+		case *ast.Text:
+			if entering {
+				buf.Write(node.Segment.Value(src))
+				if node.SoftLineBreak() || node.HardLineBreak() {
+					buf.WriteByte(' ')
+				}
+			}
+		case *ast.String:
+			if entering {
+				buf.Write(node.Value)
+			}
+		case *ast.AutoLink:
+			if entering {
+				buf.Write(node.Label(src))
+			}
+		case *ast.CodeBlock, *ast.FencedCodeBlock:
+			if entering {
+				lines := n.Lines()
+				for i := 0; i < lines.Len(); i++ {
+					segment := lines.At(i)
+					buf.Write(segment.Value(src))
+				}
+			}
+		case *ast.RawHTML, *ast.HTMLBlock:
+			return ast.WalkSkipChildren, nil
+		}
+		if !entering && n.Type() == ast.TypeBlock {
+			buf.WriteByte(' ')
+		}
+		return ast.WalkContinue, nil
+	})
+
+	return strings.Join(strings.Fields(buf.String()), " ")
+}
+
+// Preview returns the plain text of source cut to at most maxRunes characters,
+// breaking between words where possible and ending truncated text with Ellipsis
+func Preview(source string, maxRunes int) string {
+	plain := PlainText(source)
+	runes := []rune(plain)
+	if len(runes) <= maxRunes {
+		return plain
+	}
+	if maxRunes <= 0 {
+		return Ellipsis
+	}
+
+	cut := runes[:maxRunes]
+	if !unicode.IsSpace(runes[maxRunes]) {
+		for i := len(cut) - 1; i > maxRunes/2; i-- {
+			if unicode.IsSpace(cut[i]) {
+				cut = cut[:i]
+				break
+			}
+		}
+	}
+	return strings.TrimRightFunc(string(cut), unicode.IsSpace) + Ellipsis
+}
+
+// Links returns the destinations of the links in source, including bare URLs,
+// in order of appearance
+func Links(source string) []string {
+	src := []byte(source)
+	doc := converter.Parser().Parse(text.NewReader(src))
+
+	var links []string
+	_ = ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		switch node := n.(type) {
+		case *ast.Link:
+			links = append(links, string(node.Destination))
+		case *ast.AutoLink:
+			links = append(links, string(node.URL(src)))
+		}
+		return ast.WalkContinue, nil
+	})
+	return links
+}
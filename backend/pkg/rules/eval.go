@@ -0,0 +1,211 @@
+package rules
+
+import "math"
+
+// Result is the three-valued outcome of a rule
+type Result int
+
+const (
+	// Unknown means the rule depends on a field that has no value
+	Unknown Result = iota
+	// False means the rule does not hold
+	False
+	// True means the rule holds
+	True
+)
+
+// String returns "true", "false" or "unknown"
+func (r Result) String() string {
+	switch r {
+	case True:
+		return "true"
+	case False:
+		return "false"
+	default:
+		return "unknown"
+	}
+}
+
+// Values supplies field values for evaluation; a nil value is unknown
+type Values map[string]*float64
+
+// Evaluate runs the rule against the given values. Comparisons involving an
+// unknown value are unknown, and and/or follow three-valued logic, so
+// "dscr >= 1.25 or cash_on_cash >= 10" is true whenever either side is.
+func (e *Expression) Evaluate(values Values) Result {
+	return e.root.(boolNode).eval(values)
+}
+
+// valueKind is the static type of an expression node
+type valueKind int
+
+const (
+	kindNumber valueKind = iota
+	kindBool
+)
+
+// node is a parsed expression
+type node interface {
+	kind() valueKind
+}
+
+// numeric nodes evaluate to a number, or ok=false when unknown
+type numericNode interface {
+	node
+	eval(values Values) (value float64, ok bool)
+}
+
+// boolNode nodes evaluate to a three-valued result
+type boolNode interface {
+	node
+	eval(values Values) Result
+}
+
+type numberNode struct{ value float64 }
+
+func (n *numberNode) kind() valueKind             { return kindNumber }
+func (n *numberNode) eval(Values) (float64, bool) { return n.value, true }
+
+type fieldNode struct{ name string }
+
+func (n *fieldNode) kind() valueKind { return kindNumber }
+func (n *fieldNode) eval(values Values) (float64, bool) {
+	value := values[n.name]
+	if value == nil {
+		return 0, false
+	}
+	return *value, true
+}
+
+type negateNode struct{ operand node }
+
+func (n *negateNode) kind() valueKind { return kindNumber }
+func (n *negateNode) eval(values Values) (float64, bool) {
+	value, ok := n.operand.(numericNode).eval(values)
+	return -value, ok
+}
+
+type arithmeticOp struct {
+	op          string
+	left, right node
+}
+
+func (n *arithmeticOp) kind() valueKind { return kindNumber }
+func (n *arithmeticOp) eval(values Values) (float64, bool) {
+	left, ok := n.left.(numericNode).eval(values)
+	if !ok {
+		return 0, false
+	}
+	right, ok := n.right.(numericNode).eval(values)
+	if !ok {
+		return 0, false
+	}
+
+	var result float64
+	switch n.op {
+	case "+":
+		result = left + right
+	case "-":
+		result = left - right
+	case "*":
+		result = left * right
+	case "/":
+		if right == 0 {
+			return 0, false
+		}
+		result = left / right
+	}
+	if math.IsNaN(result) || math.IsInf(result, 0) {
+		return 0, false
+	}
+	return result, true
+}
+
+type compareNode struct {
+	op          string
+	left, right node
+}
+
+func (n *compareNode) kind() valueKind { return kindBool }
+func (n *compareNode) eval(values Values) Result {
+	left, ok := n.left.(numericNode).eval(values)
+	if !ok {
+		return Unknown
+	}
+	right, ok := n.right.(numericNode).eval(values)
+	if !ok {
+		return Unknown
+	}
+
+	var holds bool
+	switch n.op {
+	case "<":
+		holds = left < right
+	case "<=":
+		holds = left <= right
+	case ">":
+		holds = left > right
+	case ">=":
+		holds = left >= right
+	case "==":
+		holds = left == right
+	case "!=":
+		holds = left != right
+	}
+	if holds {
+		return True
+	}
+	return False
+}
+
+type andNode struct{ left, right node }
+
+func (n *andNode) kind() valueKind { return kindBool }
+func (n *andNode) eval(values Values) Result {
+	left := n.left.(boolNode).eval(values)
+	if left == False {
+		return False
+	}
+	right := n.right.(boolNode).eval(values)
+	switch {
+	case right == False:
+		return False
+	case left == True && right == True:
+		return True
+	default:
+		return Unknown
+	}
+}
+
+type orNode struct{ left, right node }
+
+func (n *orNode) kind() valueKind { return kindBool }
+func (n *orNode) eval(values Values) Result {
+	left := n.left.(boolNode).eval(values)
+	if left == True {
+		return True
+	}
+	right := n.right.(boolNode).eval(values)
+	switch {
+	case right == True:
+		return True
+	case left == False && right == False:
+		return False
+	default:
+		return Unknown
+	}
+}
+
+type notNode struct{ operand node }
+
+func (n *notNode) kind() valueKind { return kindBool }
+func (n *notNode) eval(values Values) Result {
+	switch n.operand.(boolNode).eval(values) {
+	case True:
+		return False
+	case False:
+		return True
+	default:
+		return Unknown
+	}
+}
@@ -0,0 +1,131 @@
+package rules
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// tokenKind classifies a lexical token
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenNumber
+	tokenIdent
+	tokenAnd
+	tokenOr
+	tokenNot
+	tokenCompare
+	tokenPlus
+	tokenMinus
+	tokenStar
+	tokenSlash
+	tokenLParen
+	tokenRParen
+)
+
+// token is a lexical token with its 1-based column in the source
+type token struct {
+	kind   tokenKind
+	text   string
+	column int
+}
+
+// describe renders a token for error messages
+func (t token) describe() string {
+	if t.kind == tokenEOF {
+		return "end of rule"
+	}
+	return fmt.Sprintf("%q", t.text)
+}
+
+// keywords maps case-insensitive word operators to their token kinds
+var keywords = map[string]tokenKind{
+	"and": tokenAnd,
+	"or":  tokenOr,
+	"not": tokenNot,
+}
+
+// tokenize splits a rule into tokens
+func tokenize(source string) ([]token, error) {
+	var tokens []token
+	runes := []rune(source)
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		column := i + 1
+
+		switch {
+		case unicode.IsSpace(r):
+			i++
+
+		case unicode.IsDigit(r) || (r == '.' && i+1 < len(runes) && unicode.IsDigit(runes[i+1])):
+			start := i
+			seenDot := false
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || (runes[i] == '.' && !seenDot)) {
+				if runes[i] == '.' {
+					seenDot = true
+				}
+				i++
+			}
+			// Allow thousands separators written as underscores: 1_000_000
+			for i < len(runes) && runes[i] == '_' && i+1 < len(runes) && unicode.IsDigit(runes[i+1]) {
+				i++
+				for i < len(runes) && unicode.IsDigit(runes[i]) {
+					i++
+				}
+			}
+			if i < len(runes) && (unicode.IsLetter(runes[i]) || runes[i] == '.') {
+				return nil, &SyntaxError{Column: i + 1, Message: fmt.Sprintf("unexpected %q after number", runes[i])}
+			}
+			tokens = append(tokens, token{kind: tokenNumber, text: string(runes[start:i]), column: column})
+
+		case unicode.IsLetter(r) || r == '_':
+			start := i
+			for i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || runes[i] == '_') {
+				i++
+			}
+			word := string(runes[start:i])
+			kind, ok := keywords[strings.ToLower(word)]
+			if !ok {
+				kind = tokenIdent
+			}
+			tokens = append(tokens, token{kind: kind, text: word, column: column})
+
+		default:
+			two := ""
+			if i+1 < len(runes) {
+				two = string(runes[i : i+2])
+			}
+			switch two {
+			case "<=", ">=", "==", "!=":
+				tokens = append(tokens, token{kind: tokenCompare, text: two, column: column})
+				i += 2
+				continue
+			case "&&":
+				tokens = append(tokens, token{kind: tokenAnd, text: two, column: column})
+				i += 2
+				continue
+			case "||":
+				tokens = append(tokens, token{kind: tokenOr, text: two, column: column})
+				i += 2
+				continue
+			}
+
+			kinds := map[rune]tokenKind{
+				'<': tokenCompare, '>': tokenCompare, '=': tokenCompare, '!': tokenNot,
+				'+': tokenPlus, '-': tokenMinus, '*': tokenStar, '/': tokenSlash,
+				'(': tokenLParen, ')': tokenRParen,
+			}
+			kind, ok := kinds[r]
+			if !ok {
+				return nil, &SyntaxError{Column: column, Message: fmt.Sprintf("unexpected character %q", r)}
+			}
+			tokens = append(tokens, token{kind: kind, text: string(r), column: column})
+			i++
+		}
+	}
+
+	return append(tokens, token{kind: tokenEOF, column: len(runes) + 1}), nil
+}
@@ -0,0 +1,319 @@
+package rules
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Limits that keep rules cheap to evaluate
+const (
+	// MaxLength is the longest rule source accepted, in characters
+	MaxLength = 500
+	// maxDepth bounds operator and parenthesis nesting
+	maxDepth = 32
+)
+
+// SyntaxError reports why a rule could not be parsed, with the 1-based column of the problem
+type SyntaxError struct {
+	Column  int
+	Message string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("column %d: %s", e.Column, e.Message)
+}
+
+// Expression is a parsed, type-checked rule
+type Expression struct {
+	source string
+	root   node
+	fields []string
+}
+
+// Parse parses a rule such as "cap_rate >= 8 and price_per_sqft < 150".
+// Field names are case-insensitive and must be one of fields; consecutive words
+// are joined with underscores, so "price per sqft" reads as price_per_sqft.
+// The rule must evaluate to true or false.
+func Parse(source string, fields []string) (*Expression, error) {
+	if strings.TrimSpace(source) == "" {
+		return nil, &SyntaxError{Column: 1, Message: "rule is empty"}
+	}
+	if len([]rune(source)) > MaxLength {
+		return nil, &SyntaxError{Column: MaxLength + 1, Message: fmt.Sprintf("rule is longer than %d characters", MaxLength)}
+	}
+
+	tokens, err := tokenize(source)
+	if err != nil {
+		return nil, err
+	}
+
+	known := make(map[string]bool, len(fields))
+	for _, field := range fields {
+		known[strings.ToLower(field)] = true
+	}
+
+	p := &parser{tokens: tokens, known: known, fields: fields, used: make(map[string]bool)}
+	root, err := p.parseOr(0)
+	if err != nil {
+		return nil, err
+	}
+	if next := p.peek(); next.kind != tokenEOF {
+		return nil, &SyntaxError{Column: next.column, Message: fmt.Sprintf("unexpected %s; expected and, or, or end of rule", next.describe())}
+	}
+	if root.kind() != kindBool {
+		return nil, &SyntaxError{Column: 1, Message: "rule must be a comparison such as cap_rate >= 8"}
+	}
+
+	used := make([]string, 0, len(p.used))
+	for field := range p.used {
+		used = append(used, field)
+	}
+	sort.Strings(used)
+
+	return &Expression{source: source, root: root, fields: used}, nil
+}
+
+// String returns the rule source
+func (e *Expression) String() string {
+	return e.source
+}
+
+// Fields returns the field names the rule reads, sorted
+func (e *Expression) Fields() []string {
+	return append([]string(nil), e.fields...)
+}
+
+// parser is a recursive-descent parser over the token stream
+type parser struct {
+	tokens []token
+	pos    int
+	known  map[string]bool
+	fields []string
+	used   map[string]bool
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+// enter guards against deeply nested rules
+func (p *parser) enter(depth int) error {
+	if depth > maxDepth {
+		return &SyntaxError{Column: p.peek().column, Message: "rule is nested too deeply"}
+	}
+	return nil
+}
+
+// parseOr: and ("or" and)*
+func (p *parser) parseOr(depth int) (node, error) {
+	if err := p.enter(depth); err != nil {
+		return nil, err
+	}
+	left, err := p.parseAnd(depth + 1)
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().kind == tokenOr {
+		op := p.next()
+		right, err := p.parseAnd(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		if left, err = logicalNode(op, left, right); err != nil {
+			return nil, err
+		}
+	}
+	return left, nil
+}
+
+// parseAnd: not ("and" not)*
+func (p *parser) parseAnd(depth int) (node, error) {
+	left, err := p.parseNot(depth + 1)
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().kind == tokenAnd {
+		op := p.next()
+		right, err := p.parseNot(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		if left, err = logicalNode(op, left, right); err != nil {
+			return nil, err
+		}
+	}
+	return left, nil
+}
+
+// parseNot: "not" not | comparison
+func (p *parser) parseNot(depth int) (node, error) {
+	if err := p.enter(depth); err != nil {
+		return nil, err
+	}
+	if p.peek().kind != tokenNot {
+		return p.parseComparison(depth + 1)
+	}
+	op := p.next()
+	operand, err := p.parseNot(depth + 1)
+	if err != nil {
+		return nil, err
+	}
+	if operand.kind() != kindBool {
+		return nil, &SyntaxError{Column: op.column, Message: "not must be followed by a comparison"}
+	}
+	return &notNode{operand: operand}, nil
+}
+
+// parseComparison: sum (compare sum)?
+func (p *parser) parseComparison(depth int) (node, error) {
+	left, err := p.parseSum(depth + 1)
+	if err != nil {
+		return nil, err
+	}
+	if p.peek().kind != tokenCompare {
+		return left, nil
+	}
+
+	op := p.next()
+	right, err := p.parseSum(depth + 1)
+	if err != nil {
+		return nil, err
+	}
+	if left.kind() != kindNumber || right.kind() != kindNumber {
+		return nil, &SyntaxError{Column: op.column, Message: fmt.Sprintf("%s compares numbers; wrap logical expressions in parentheses", op.text)}
+	}
+	if p.peek().kind == tokenCompare {
+		next := p.peek()
+		return nil, &SyntaxError{Column: next.column, Message: "comparisons cannot be chained; combine them with and"}
+	}
+
+	operator := op.text
+	if operator == "=" {
+		operator = "=="
+	}
+	return &compareNode{op: operator, left: left, right: right}, nil
+}
+
+// parseSum: term (("+" | "-") term)*
+func (p *parser) parseSum(depth int) (node, error) {
+	left, err := p.parseTerm(depth + 1)
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().kind == tokenPlus || p.peek().kind == tokenMinus {
+		op := p.next()
+		right, err := p.parseTerm(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		if left, err = arithmeticNode(op, left, right); err != nil {
+			return nil, err
+		}
+	}
+	return left, nil
+}
+
+// parseTerm: unary (("*" | "/") unary)*
+func (p *parser) parseTerm(depth int) (node, error) {
+	left, err := p.parseUnary(depth + 1)
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().kind == tokenStar || p.peek().kind == tokenSlash {
+		op := p.next()
+		right, err := p.parseUnary(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		if left, err = arithmeticNode(op, left, right); err != nil {
+			return nil, err
+		}
+	}
+	return left, nil
+}
+
+// parseUnary: "-" unary | primary
+func (p *parser) parseUnary(depth int) (node, error) {
+	if err := p.enter(depth); err != nil {
+		return nil, err
+	}
+	if p.peek().kind != tokenMinus {
+		return p.parsePrimary(depth + 1)
+	}
+	op := p.next()
+	operand, err := p.parseUnary(depth + 1)
+	if err != nil {
+		return nil, err
+	}
+	if operand.kind() != kindNumber {
+		return nil, &SyntaxError{Column: op.column, Message: "- must be followed by a number or field"}
+	}
+	return &negateNode{operand: operand}, nil
+}
+
+// parsePrimary: number | field | "(" or ")"
+func (p *parser) parsePrimary(depth int) (node, error) {
+	t := p.next()
+	switch t.kind {
+	case tokenNumber:
+		value, err := strconv.ParseFloat(strings.ReplaceAll(t.text, "_", ""), 64)
+		if err != nil {
+			return nil, &SyntaxError{Column: t.column, Message: fmt.Sprintf("invalid number %q", t.text)}
+		}
+		return &numberNode{value: value}, nil
+
+	case tokenIdent:
+		words := []string{t.text}
+		for p.peek().kind == tokenIdent {
+			words = append(words, p.next().text)
+		}
+		name := strings.ToLower(strings.Join(words, "_"))
+		if !p.known[name] {
+			return nil, &SyntaxError{Column: t.column, Message: fmt.Sprintf("unknown field %q; available fields: %s", name, strings.Join(p.fields, ", "))}
+		}
+		p.used[name] = true
+		return &fieldNode{name: name}, nil
+
+	case tokenLParen:
+		inner, err := p.parseOr(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.next(); closing.kind != tokenRParen {
+			return nil, &SyntaxError{Column: closing.column, Message: fmt.Sprintf("expected ) but found %s", closing.describe())}
+		}
+		return inner, nil
+
+	default:
+		return nil, &SyntaxError{Column: t.column, Message: fmt.Sprintf("expected a number, field or ( but found %s", t.describe())}
+	}
+}
+
+// logicalNode builds an and/or node, checking both sides are comparisons
+func logicalNode(op token, left, right node) (node, error) {
+	if left.kind() != kindBool || right.kind() != kindBool {
+		return nil, &SyntaxError{Column: op.column, Message: fmt.Sprintf("both sides of %s must be comparisons", strings.ToLower(op.text))}
+	}
+	if op.kind == tokenAnd {
+		return &andNode{left: left, right: right}, nil
+	}
+	return &orNode{left: left, right: right}, nil
+}
+
+// arithmeticNode builds a +, -, * or / node, checking both sides are numbers
+func arithmeticNode(op token, left, right node) (node, error) {
+	if left.kind() != kindNumber || right.kind() != kindNumber {
+		return nil, &SyntaxError{Column: op.column, Message: fmt.Sprintf("%s needs numbers on both sides", op.text)}
+	}
+	return &arithmeticOp{op: op.text, left: left, right: right}, nil
+}
@@ -0,0 +1,99 @@
+package contract
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAssumptionProfilesContract(t *testing.T) {
+	app := setupTestApp(t)
+
+	testUser := map[string]interface{}{
+		"email":      "profiles@example.com",
+		"password":   "testpass123",
+		"first_name": "Profile",
+		"last_name":  "Owner",
+	}
+	createTestUser(t, app, testUser)
+	token := getAuthToken(t, app, "profiles@example.com", "testpass123")
+
+	t.Run("invalid zip code", func(t *testing.T) {
+		status, response := doJSON(t, app, token, http.MethodPost, "/api/v1/assumption-profiles", map[string]interface{}{
+			"name":     "Bad ZIP",
+			"zip_code": "ABCDE",
+		})
+		assert.Equal(t, 400, status)
+		assert.Contains(t, response, "error")
+	})
+
+	status, profile := doJSON(t, app, token, http.MethodPost, "/api/v1/assumption-profiles", map[string]interface{}{
+		"name":               "Anytown 12345",
+		"zip_code":           "12345",
+		"vacancy_rate":       0.06,
+		"management_pct":     0.09,
+		"tax_rate_pct":       1.5,
+		"insurance_per_1000": 4,
+	})
+	require.Equal(t, 201, status)
+	assert.NotEmpty(t, profile["id"])
+	assert.Equal(t, "user", profile["scope"])
+
+	t.Run("property creation fills missing values from matching profile", func(t *testing.T) {
+		status, property := doJSON(t, app, token, http.MethodPost, "/api/v1/properties", map[string]interface{}{
+			"address":        "123 Main St, Anytown, ST 12345",
+			"purchase_price": 200000,
+			"intended_rent":  1800,
+			"operating_assumptions": map[string]interface{}{
+				"vacancy_rate": 0.04,
+			},
+		})
+		require.Equal(t, 201, status)
+
+		assert.Equal(t, profile["id"], property["assumption_profile_id"])
+
+		assumptions := property["operating_assumptions"].(map[string]interface{})
+		assert.Equal(t, 0.04, assumptions["vacancy_rate"], "explicit values are kept")
+		assert.Equal(t, 0.09, assumptions["management_pct"])
+
+		expenses := property["operating_expenses"].(map[string]interface{})
+		assert.Equal(t, 3000.0, expenses["property_taxes"])
+		assert.Equal(t, 800.0, expenses["insurance"])
+
+		defaulted := property["defaulted_fields"].(map[string]interface{})
+		assert.Contains(t, defaulted, "operating_assumptions.management_pct")
+		assert.Contains(t, defaulted, "operating_expenses.property_taxes")
+		assert.NotContains(t, defaulted, "operating_assumptions.vacancy_rate")
+	})
+
+	t.Run("property outside profile ZIP is not defaulted", func(t *testing.T) {
+		status, property := doJSON(t, app, token, http.MethodPost, "/api/v1/properties", map[string]interface{}{
+			"address":        "9 Elm St, Othertown, ST 54321",
+			"purchase_price": 150000,
+		})
+		require.Equal(t, 201, status)
+		assert.Nil(t, property["assumption_profile_id"])
+	})
+
+	t.Run("profile with ZIP and metro applies across its metro", func(t *testing.T) {
+		status, metroProfile := doJSON(t, app, token, http.MethodPost, "/api/v1/assumption-profiles", map[string]interface{}{
+			"name":         "Springfield 11111",
+			"zip_code":     "11111",
+			"metro":        "Springfield",
+			"vacancy_rate": 0.07,
+		})
+		require.Equal(t, 201, status)
+
+		status, property := doJSON(t, app, token, http.MethodPost, "/api/v1/properties", map[string]interface{}{
+			"address":        "4 Oak Ave, Springfield, ST 22222",
+			"purchase_price": 180000,
+			"local_context": map[string]interface{}{
+				"metro": "Springfield",
+			},
+		})
+		require.Equal(t, 201, status)
+		assert.Equal(t, metroProfile["id"], property["assumption_profile_id"])
+	})
+}
@@ -12,10 +12,9 @@ import (
 )
 
 func TestAuthLoginContract(t *testing.T) {
-	// This test MUST fail initially - no implementation exists yet
 	app := setupTestApp(t)
 
-	// First create a test user (this will also fail until registration is implemented)
+	// First create a test user
 	testUser := map[string]interface{}{
 		"email":      "login@example.com",
 		"password":   "testpass123",
@@ -12,8 +12,9 @@ import (
 )
 
 func TestAuthRegisterContract(t *testing.T) {
-	// This test MUST fail initially - no implementation exists yet
-	app := setupTestApp(t)
+	env := setupTestEnv(t)
+	app := env.App
+	env.User(t, "duplicate@example.com")
 
 	tests := []struct {
 		name           string
@@ -0,0 +1,308 @@
+package contract
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"rental-property-mgmt/internal/models"
+)
+
+func TestBuyingCriteriaContract(t *testing.T) {
+	app := setupTestApp(t)
+
+	testUser := map[string]interface{}{
+		"email":      "criteria@example.com",
+		"password":   "testpass123",
+		"first_name": "Criteria",
+		"last_name":  "Owner",
+	}
+	createTestUser(t, app, testUser)
+	token := getAuthToken(t, app, "criteria@example.com", "testpass123")
+
+	t.Run("rejects invalid custom rule", func(t *testing.T) {
+		status, response := doJSONValue(t, app, token, http.MethodPost, "/api/v1/buying-criteria", map[string]interface{}{
+			"name": "Broken",
+			"custom_rules": []map[string]interface{}{
+				{"name": "per foot", "expression": "price per sqft <"},
+			},
+		})
+		assert.Equal(t, 400, status)
+		assert.Contains(t, response.(map[string]interface{})["error"], "column")
+	})
+
+	status, response := doJSONValue(t, app, token, http.MethodPost, "/api/v1/buying-criteria", map[string]interface{}{
+		"name":               "Cash flow under 300k",
+		"max_purchase_price": 300000,
+		"min_cap_rate":       5,
+	})
+	require.Equal(t, 201, status)
+	criteria := response.(map[string]interface{})
+	criteriaID := criteria["id"].(string)
+	assert.Equal(t, 1.0, criteria["version"])
+	assert.Equal(t, true, criteria["is_active"])
+	assert.Equal(t, "flag", criteria["missing_data_policy"])
+
+	t.Run("list and get", func(t *testing.T) {
+		status, list := doJSONValue(t, app, token, http.MethodGet, "/api/v1/buying-criteria", nil)
+		require.Equal(t, 200, status)
+		assert.Len(t, list, 1)
+
+		status, fetched := doJSONValue(t, app, token, http.MethodGet, "/api/v1/buying-criteria/"+criteriaID, nil)
+		require.Equal(t, 200, status)
+		assert.Equal(t, "Cash flow under 300k", fetched.(map[string]interface{})["name"])
+	})
+
+	t.Run("deactivate and activate", func(t *testing.T) {
+		status, toggled := doJSONValue(t, app, token, http.MethodPost, "/api/v1/buying-criteria/"+criteriaID+"/deactivate", nil)
+		require.Equal(t, 200, status)
+		assert.Equal(t, false, toggled.(map[string]interface{})["is_active"])
+		assert.Equal(t, 1.0, toggled.(map[string]interface{})["version"], "activation does not create a version")
+
+		status, toggled = doJSONValue(t, app, token, http.MethodPost, "/api/v1/buying-criteria/"+criteriaID+"/activate", nil)
+		require.Equal(t, 200, status)
+		assert.Equal(t, true, toggled.(map[string]interface{})["is_active"])
+	})
+
+	status, property := doJSONValue(t, app, token, http.MethodPost, "/api/v1/properties", map[string]interface{}{
+		"address":        "123 Main St, Anytown, ST 12345",
+		"purchase_price": 250000,
+		"intended_rent":  2100,
+		"operating_expenses": map[string]interface{}{
+			"insurance":      1200,
+			"property_taxes": 3600,
+		},
+		"financing_terms": map[string]interface{}{
+			"interest_rate":        7.5,
+			"loan_term":            30,
+			"down_payment_percent": 25,
+			"closing_costs":        5000,
+		},
+	})
+	require.Equal(t, 201, status)
+	propertyID := property.(map[string]interface{})["id"].(string)
+
+	t.Run("new matching property lands in the notifications inbox", func(t *testing.T) {
+		status, inbox := doJSONValue(t, app, token, http.MethodGet, "/api/v1/notifications?unread=true", nil)
+		require.Equal(t, 200, status)
+		items := inbox.(map[string]interface{})["notifications"].([]interface{})
+		require.Len(t, items, 1)
+		notification := items[0].(map[string]interface{})
+		assert.Equal(t, "match", notification["type"])
+		assert.Equal(t, propertyID, notification["property_id"])
+		match := notification["match"].(map[string]interface{})
+		assert.Equal(t, 1.0, match["criteria_version"])
+		assert.Equal(t, "Cash flow under 300k", match["criteria"].(map[string]interface{})["name"])
+
+		status, _ = doJSONValue(t, app, token, http.MethodPost, "/api/v1/notifications/"+notification["id"].(string)+"/read", nil)
+		require.Equal(t, 204, status)
+
+		_, inbox = doJSONValue(t, app, token, http.MethodGet, "/api/v1/notifications?unread=true", nil)
+		assert.Equal(t, 0.0, inbox.(map[string]interface{})["unread"])
+	})
+
+	t.Run("editing the definition records a new version", func(t *testing.T) {
+		status, updated := doJSONValue(t, app, token, http.MethodPut, "/api/v1/buying-criteria/"+criteriaID, map[string]interface{}{
+			"name":               "Cash flow under 200k",
+			"max_purchase_price": 200000,
+			"min_cap_rate":       5,
+		})
+		require.Equal(t, 200, status)
+		assert.Equal(t, 2.0, updated.(map[string]interface{})["version"])
+
+		status, versions := doJSONValue(t, app, token, http.MethodGet, "/api/v1/buying-criteria/"+criteriaID+"/versions", nil)
+		require.Equal(t, 200, status)
+		require.Len(t, versions, 2)
+		latest := versions.([]interface{})[0].(map[string]interface{})
+		assert.Equal(t, 2.0, latest["version"])
+
+		status, first := doJSONValue(t, app, token, http.MethodGet, "/api/v1/buying-criteria/"+criteriaID+"/versions/1", nil)
+		require.Equal(t, 200, status)
+		definition := first.(map[string]interface{})["definition"].(map[string]interface{})
+		assert.Equal(t, 300000.0, definition["max_purchase_price"])
+	})
+
+	t.Run("renaming does not record a version", func(t *testing.T) {
+		status, renamed := doJSONValue(t, app, token, http.MethodPut, "/api/v1/buying-criteria/"+criteriaID, map[string]interface{}{
+			"name":               "Cash flow, small deals",
+			"max_purchase_price": 200000,
+			"min_cap_rate":       5,
+		})
+		require.Equal(t, 200, status)
+		assert.Equal(t, "Cash flow, small deals", renamed.(map[string]interface{})["name"])
+		assert.Equal(t, 2.0, renamed.(map[string]interface{})["version"])
+
+		status, versions := doJSONValue(t, app, token, http.MethodGet, "/api/v1/buying-criteria/"+criteriaID+"/versions", nil)
+		require.Equal(t, 200, status)
+		assert.Len(t, versions, 2)
+	})
+
+	t.Run("comparison states the criteria version", func(t *testing.T) {
+		status, results := doJSONValue(t, app, token, http.MethodPost, "/api/v1/properties/compare", map[string]interface{}{
+			"property_ids": []string{propertyID},
+			"criteria_id":  criteriaID,
+		})
+		require.Equal(t, 200, status)
+		require.Len(t, results, 1)
+		result := results.([]interface{})[0].(map[string]interface{})
+		assert.Equal(t, 2.0, result["criteria_version"])
+		assert.Equal(t, false, result["meets_criteria"], "price is above the new 200k maximum")
+	})
+
+	t.Run("portfolio ranking", func(t *testing.T) {
+		status, ranking := doJSONValue(t, app, token, http.MethodGet, "/api/v1/properties/rankings?limit=10", nil)
+		require.Equal(t, 200, status)
+		body := ranking.(map[string]interface{})
+		assert.Equal(t, 1.0, body["total"])
+		summary := body["summary"].(map[string]interface{})
+		failing := summary["failing_criteria"].([]interface{})
+		require.NotEmpty(t, failing)
+		assert.Equal(t, "purchase_price", failing[0].(map[string]interface{})["criterion"])
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		status, _ := doJSONValue(t, app, token, http.MethodDelete, "/api/v1/buying-criteria/"+criteriaID, nil)
+		require.Equal(t, 204, status)
+
+		status, _ = doJSONValue(t, app, token, http.MethodGet, "/api/v1/buying-criteria/"+criteriaID, nil)
+		assert.Equal(t, 404, status)
+	})
+}
+
+func TestBuyingCriteriaConcurrentEdits(t *testing.T) {
+	env := setupTestEnv(t)
+	owner := env.User(t, "owner@example.com")
+	criteria := env.Criteria(t, owner)
+	token := env.Token(t, owner)
+	path := "/api/v1/buying-criteria/" + criteria.ID.String()
+
+	const edits = 10
+	statuses := make(chan int, edits)
+	var wg sync.WaitGroup
+	for i := 1; i <= edits; i++ {
+		wg.Add(1)
+		go func(minCapRate int) {
+			defer wg.Done()
+			payload, _ := json.Marshal(map[string]interface{}{
+				"name":         criteria.Name,
+				"min_cap_rate": minCapRate,
+			})
+			req := httptest.NewRequest(http.MethodPut, path, bytes.NewBuffer(payload))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("Authorization", "Bearer "+token)
+			resp, err := env.App.Test(req, -1)
+			if err != nil {
+				statuses <- 0
+				return
+			}
+			statuses <- resp.StatusCode
+		}(i)
+	}
+	wg.Wait()
+	close(statuses)
+
+	for status := range statuses {
+		assert.Equal(t, 200, status, "each edit records its own version")
+	}
+	status, versions := doJSONValue(t, env.App, token, http.MethodGet, path+"/versions", nil)
+	require.Equal(t, 200, status)
+	assert.Len(t, versions, edits+1)
+}
+
+func TestBuyingCriteriaVersionConflict(t *testing.T) {
+	env := setupTestEnv(t)
+	owner := env.User(t, "owner@example.com")
+	criteria := env.Criteria(t, owner)
+
+	// Another writer has already recorded the next version
+	next := *criteria
+	next.Version = 2
+	require.NoError(t, env.Store.Criteria.CreateVersion(models.NewCriteriaVersion(&next, owner.ID)))
+
+	status, response := doJSON(t, env.App, env.Token(t, owner), http.MethodPut, "/api/v1/buying-criteria/"+criteria.ID.String(), map[string]interface{}{
+		"name":         criteria.Name,
+		"min_cap_rate": 6,
+	})
+	assert.Equal(t, 409, status)
+	assert.Contains(t, response["error"], "reload and try again")
+}
+
+func TestAlertDeliveryDoesNotHoldUpSave(t *testing.T) {
+	cases := []struct {
+		name    string
+		webhook func(w http.ResponseWriter, release <-chan struct{})
+	}{
+		{"blocking webhook", func(w http.ResponseWriter, release <-chan struct{}) {
+			<-release
+		}},
+		{"failing webhook", func(w http.ResponseWriter, release <-chan struct{}) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			received := make(chan struct{}, 1)
+			release := make(chan struct{})
+			webhook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				received <- struct{}{}
+				tc.webhook(w, release)
+			}))
+			t.Cleanup(webhook.Close)
+			t.Cleanup(func() { close(release) })
+			t.Setenv("NOTIFY_WEBHOOK_URL", webhook.URL)
+
+			env := setupTestEnv(t)
+			owner := env.User(t, "criteria@example.com")
+			minCapRate := 5.0
+			env.Criteria(t, owner, func(c *models.BuyingBoxCriteria) {
+				c.MinCapRate = &minCapRate
+			})
+
+			payload, err := json.Marshal(map[string]interface{}{
+				"address":        "123 Main St, Anytown, ST 12345",
+				"purchase_price": 250000,
+				"intended_rent":  2100,
+				"operating_expenses": map[string]interface{}{
+					"insurance":      1200,
+					"property_taxes": 3600,
+				},
+				"financing_terms": map[string]interface{}{
+					"interest_rate":        7.5,
+					"loan_term":            30,
+					"down_payment_percent": 20,
+					"closing_costs":        5000,
+				},
+			})
+			require.NoError(t, err)
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/properties", bytes.NewBuffer(payload))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("Authorization", "Bearer "+env.Token(t, owner))
+
+			started := time.Now()
+			resp, err := env.App.Test(req, 5000)
+			require.NoError(t, err)
+			assert.Equal(t, 201, resp.StatusCode, "delivery problems never fail the save")
+			assert.Less(t, time.Since(started), 2*time.Second, "the save does not wait for delivery")
+
+			var matches, inbox int64
+			require.NoError(t, env.DB.Table("criteria_matches").Count(&matches).Error)
+			require.NoError(t, env.DB.Table("notifications").Count(&inbox).Error)
+			assert.Equal(t, int64(1), matches, "the match is recorded with the save")
+			assert.Equal(t, int64(1), inbox, "the inbox entry is recorded with the save")
+
+			select {
+			case <-received:
+			case <-time.After(5 * time.Second):
+				t.Fatal("the notification was never sent")
+			}
+		})
+	}
+}
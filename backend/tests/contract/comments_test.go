@@ -0,0 +1,170 @@
+package contract
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommentsContract(t *testing.T) {
+	app := setupTestApp(t)
+
+	createTestUser(t, app, map[string]interface{}{
+		"email":      "author@example.com",
+		"password":   "testpass123",
+		"first_name": "Ann",
+		"last_name":  "Author",
+	})
+	createTestUser(t, app, map[string]interface{}{
+		"email":      "teammate@example.com",
+		"password":   "testpass123",
+		"first_name": "Tom",
+		"last_name":  "Mate",
+	})
+	authorToken := getAuthToken(t, app, "author@example.com", "testpass123")
+	teammateToken := getAuthToken(t, app, "teammate@example.com", "testpass123")
+
+	createProperty := func(address string) string {
+		status, property := doJSON(t, app, authorToken, http.MethodPost, "/api/v1/properties", map[string]interface{}{
+			"address":        address,
+			"purchase_price": 200000,
+		})
+		require.Equal(t, 201, status)
+		return property["id"].(string)
+	}
+	propertyID := createProperty("1 Thread St, Anytown, ST 12345")
+	otherPropertyID := createProperty("2 Other St, Anytown, ST 12345")
+	commentsPath := "/api/v1/properties/" + propertyID + "/comments"
+
+	status, root := doJSON(t, app, authorToken, http.MethodPost, commentsPath, map[string]interface{}{
+		"content": "Roof looks old, budget for replacement",
+	})
+	require.Equal(t, 201, status)
+	rootID := root["id"].(string)
+	assert.Equal(t, "Ann Author", root["user_name"])
+	assert.Equal(t, 0.0, root["depth"])
+
+	status, reply := doJSON(t, app, teammateToken, http.MethodPost, commentsPath, map[string]interface{}{
+		"content":   "Seller might credit it",
+		"parent_id": rootID,
+	})
+	require.Equal(t, 201, status)
+	replyID := reply["id"].(string)
+	assert.Equal(t, rootID, reply["thread_id"])
+	assert.Equal(t, 1.0, reply["depth"])
+
+	t.Run("blank content", func(t *testing.T) {
+		status, _ := doJSON(t, app, authorToken, http.MethodPost, commentsPath, map[string]interface{}{"content": "   "})
+		assert.Equal(t, 400, status)
+	})
+
+	t.Run("markdown is rendered and property links become cards", func(t *testing.T) {
+		status, comment := doJSON(t, app, authorToken, http.MethodPost, "/api/v1/properties/"+otherPropertyID+"/comments", map[string]interface{}{
+			"content": "**Compare** with [this one](/properties/" + propertyID + ") <script>alert(1)</script>",
+		})
+		require.Equal(t, 201, status)
+		html := comment["content_html"].(string)
+		assert.Contains(t, html, "<strong>Compare</strong>")
+		assert.NotContains(t, html, "<script>")
+
+		cards := comment["property_cards"].([]interface{})
+		require.Len(t, cards, 1)
+		card := cards[0].(map[string]interface{})
+		assert.Equal(t, propertyID, card["id"])
+		assert.Equal(t, "1 Thread St, Anytown, ST 12345", card["address"])
+	})
+
+	t.Run("parent must belong to the same property", func(t *testing.T) {
+		status, response := doJSON(t, app, authorToken, http.MethodPost, "/api/v1/properties/"+otherPropertyID+"/comments", map[string]interface{}{
+			"content":   "Wrong thread",
+			"parent_id": rootID,
+		})
+		assert.Equal(t, 400, status)
+		assert.Contains(t, response["error"], "different property")
+	})
+
+	t.Run("tree retrieval", func(t *testing.T) {
+		status, response := doJSON(t, app, authorToken, http.MethodGet, commentsPath+"?limit=10", nil)
+		require.Equal(t, 200, status)
+		assert.Equal(t, 1.0, response["total"])
+
+		threads := response["comments"].([]interface{})
+		require.Len(t, threads, 1)
+		thread := threads[0].(map[string]interface{})
+		assert.Equal(t, 1.0, thread["reply_count"])
+		replies := thread["replies"].([]interface{})
+		require.Len(t, replies, 1)
+		assert.Equal(t, replyID, replies[0].(map[string]interface{})["id"])
+
+		status, response = doJSON(t, app, authorToken, http.MethodGet, commentsPath+"?depth=0", nil)
+		require.Equal(t, 200, status)
+		thread = response["comments"].([]interface{})[0].(map[string]interface{})
+		assert.Empty(t, thread["replies"])
+		assert.Equal(t, 1.0, thread["reply_count"])
+	})
+
+	t.Run("only the author edits or deletes", func(t *testing.T) {
+		status, _ := doJSON(t, app, teammateToken, http.MethodPut, "/api/v1/comments/"+rootID, map[string]interface{}{"content": "Hijacked"})
+		assert.Equal(t, 403, status)
+		status, _ = doJSON(t, app, teammateToken, http.MethodDelete, "/api/v1/comments/"+rootID, nil)
+		assert.Equal(t, 403, status)
+
+		status, updated := doJSON(t, app, authorToken, http.MethodPut, "/api/v1/comments/"+rootID, map[string]interface{}{"content": "Roof is 20 years old"})
+		require.Equal(t, 200, status)
+		assert.Equal(t, "Roof is 20 years old", updated["content"])
+
+	})
+
+	t.Run("edit history is visible to the author and property owner", func(t *testing.T) {
+		status, nested := doJSON(t, app, authorToken, http.MethodPost, commentsPath, map[string]interface{}{
+			"content":   "Ask for the inspection report",
+			"parent_id": replyID,
+		})
+		require.Equal(t, 201, status)
+		assert.Equal(t, 2.0, nested["depth"])
+
+		status, _ = doJSON(t, app, teammateToken, http.MethodPut, "/api/v1/comments/"+replyID, map[string]interface{}{"content": "Seller will credit $5k"})
+		require.Equal(t, 200, status)
+
+		status, history := doJSON(t, app, teammateToken, http.MethodGet, "/api/v1/comments/"+replyID+"/history", nil)
+		require.Equal(t, 200, status)
+		assert.Equal(t, "Seller will credit $5k", history["content"])
+		revisions := history["revisions"].([]interface{})
+		require.Len(t, revisions, 1)
+		assert.Equal(t, "Seller might credit it", revisions[0].(map[string]interface{})["content"])
+		assert.NotEmpty(t, revisions[0].(map[string]interface{})["written_at"])
+
+		status, _ = doJSON(t, app, authorToken, http.MethodGet, "/api/v1/comments/"+replyID+"/history", nil)
+		assert.Equal(t, 200, status, "property owner sees the history of comments on their property")
+
+		status, _ = doJSON(t, app, teammateToken, http.MethodGet, "/api/v1/comments/"+rootID+"/history", nil)
+		assert.Equal(t, 403, status)
+	})
+
+	t.Run("deleted comments keep their replies", func(t *testing.T) {
+		status, _ := doJSON(t, app, teammateToken, http.MethodDelete, "/api/v1/comments/"+replyID, nil)
+		assert.Equal(t, 204, status)
+
+		status, deleted := doJSON(t, app, teammateToken, http.MethodGet, "/api/v1/comments/"+replyID, nil)
+		require.Equal(t, 200, status)
+		assert.Equal(t, "[deleted]", deleted["content"])
+		assert.NotEmpty(t, deleted["deleted_at"])
+		assert.Empty(t, deleted["user_name"])
+		require.Len(t, deleted["replies"], 1)
+		assert.Equal(t, "Ask for the inspection report", deleted["replies"].([]interface{})[0].(map[string]interface{})["content"])
+
+		status, _ = doJSON(t, app, teammateToken, http.MethodPut, "/api/v1/comments/"+replyID, map[string]interface{}{"content": "Back again"})
+		assert.Equal(t, 409, status)
+		status, response := doJSON(t, app, authorToken, http.MethodPost, commentsPath, map[string]interface{}{
+			"content":   "Replying to nothing",
+			"parent_id": replyID,
+		})
+		assert.Equal(t, 400, status)
+		assert.Contains(t, response["error"], "deleted")
+
+		_, history := doJSON(t, app, teammateToken, http.MethodGet, "/api/v1/comments/"+replyID+"/history", nil)
+		assert.Len(t, history["revisions"], 2, "the final content is kept as a revision")
+	})
+}
@@ -0,0 +1,135 @@
+package contract
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompsContract(t *testing.T) {
+	app := setupTestApp(t)
+
+	createTestUser(t, app, map[string]interface{}{
+		"email":      "comper@example.com",
+		"password":   "testpass123",
+		"first_name": "Cam",
+		"last_name":  "Comper",
+	})
+	createTestUser(t, app, map[string]interface{}{
+		"email":      "onlooker@example.com",
+		"password":   "testpass123",
+		"first_name": "Ona",
+		"last_name":  "Looker",
+	})
+	ownerToken := getAuthToken(t, app, "comper@example.com", "testpass123")
+	viewerToken := getAuthToken(t, app, "onlooker@example.com", "testpass123")
+
+	status, created := doJSONValue(t, app, ownerToken, http.MethodPost, "/api/v1/properties", map[string]interface{}{
+		"address":            "15 Subject Way, Anytown, ST 12345",
+		"purchase_price":     280000,
+		"building_area_sqft": 1500,
+		"year_built":         2000,
+		"bedrooms":           3,
+		"bathrooms":          2,
+	})
+	require.Equal(t, 201, status)
+	property := created.(map[string]interface{})
+	assert.Equal(t, 3.0, property["bedrooms"])
+	assert.Equal(t, 2.0, property["bathrooms"])
+	compsPath := "/api/v1/properties/" + property["id"].(string) + "/comps"
+	today := time.Now().UTC().Format("2006-01-02")
+
+	var compID string
+	t.Run("Create", func(t *testing.T) {
+		comp := map[string]interface{}{
+			"address": "17 Subject Way", "sale_price": 300000, "sale_date": today,
+			"building_area_sqft": 1400, "year_built": 1990, "bedrooms": 2, "bathrooms": 2, "distance_miles": 0.1,
+		}
+		status, _ := doJSONValue(t, app, viewerToken, http.MethodPost, compsPath, comp)
+		assert.Equal(t, 403, status, "only the owner records comps")
+
+		tomorrow := time.Now().UTC().AddDate(0, 0, 1).Format("2006-01-02")
+		status, _ = doJSONValue(t, app, ownerToken, http.MethodPost, compsPath, map[string]interface{}{
+			"address": "19 Subject Way", "sale_price": 300000, "sale_date": tomorrow,
+		})
+		assert.Equal(t, 400, status, "a sale cannot be in the future")
+
+		status, _ = doJSONValue(t, app, ownerToken, http.MethodPost, compsPath, map[string]interface{}{
+			"address": "19 Subject Way", "sale_price": 0, "sale_date": today,
+		})
+		assert.Equal(t, 400, status)
+
+		status, response := doJSONValue(t, app, ownerToken, http.MethodPost, compsPath, comp)
+		require.Equal(t, 201, status)
+		compID = response.(map[string]interface{})["id"].(string)
+
+		status, _ = doJSONValue(t, app, ownerToken, http.MethodPost, compsPath, map[string]interface{}{
+			"address": "3 Twin Ct", "sale_price": 280000, "sale_date": today, "bedrooms": 3, "bathrooms": 2,
+		})
+		require.Equal(t, 201, status)
+
+		status, response = doJSONValue(t, app, viewerToken, http.MethodGet, compsPath, nil)
+		require.Equal(t, 200, status)
+		comps := response.([]interface{})
+		require.Len(t, comps, 2)
+		assert.Equal(t, "17 Subject Way", comps[0].(map[string]interface{})["address"], "nearest comps come first")
+	})
+
+	t.Run("Value", func(t *testing.T) {
+		status, response := doJSONValue(t, app, viewerToken, http.MethodPost, compsPath+"/valuation", nil)
+		require.Equal(t, 200, status, "any team member can preview the valuation")
+		valuation := response.(map[string]interface{})
+		assert.Equal(t, 2.0, valuation["count"])
+		assert.Equal(t, 280000.0, valuation["low"])
+		assert.Equal(t, 331428.57, valuation["high"])
+		assert.Equal(t, 500.0, valuation["adjustments"].(map[string]interface{})["per_year_built"])
+		assert.Nil(t, valuation["valuation"])
+
+		status, response = doJSONValue(t, app, viewerToken, http.MethodPost, compsPath+"/valuation", map[string]interface{}{
+			"adjustments": map[string]interface{}{"price_per_sqft": 100, "per_bedroom": 0},
+		})
+		require.Equal(t, 200, status)
+		valuation = response.(map[string]interface{})
+		assert.Equal(t, 315000.0, valuation["high"], "10000 for size and 5000 for age; other defaults stay")
+
+		status, _ = doJSONValue(t, app, viewerToken, http.MethodPost, compsPath+"/valuation", map[string]interface{}{"save": true})
+		assert.Equal(t, 403, status, "only the owner stores the valuation")
+
+		status, response = doJSONValue(t, app, ownerToken, http.MethodPost, compsPath+"/valuation", map[string]interface{}{"save": true})
+		require.Equal(t, 201, status)
+		stored := response.(map[string]interface{})["valuation"].(map[string]interface{})
+		assert.Equal(t, "Comps", stored["source"])
+		assert.Equal(t, "market_value", stored["valuation_type"])
+		assert.Equal(t, 305714.29, stored["value"], "the median of the two adjusted values, rounded to cents")
+		assert.Equal(t, 280000.0, stored["value_low"])
+		assert.Equal(t, 331428.57, stored["value_high"])
+
+		status, response = doJSONValue(t, app, viewerToken, http.MethodGet, "/api/v1/properties/"+property["id"].(string)+"/valuations", nil)
+		require.Equal(t, 200, status)
+		assert.Len(t, response, 1)
+	})
+
+	t.Run("Update and delete", func(t *testing.T) {
+		status, _ := doJSONValue(t, app, viewerToken, http.MethodPut, "/api/v1/comps/"+compID, map[string]interface{}{
+			"address": "17 Subject Way", "sale_price": 310000, "sale_date": today,
+		})
+		assert.Equal(t, 403, status)
+
+		status, response := doJSONValue(t, app, ownerToken, http.MethodPut, "/api/v1/comps/"+compID, map[string]interface{}{
+			"address": "17 Subject Way", "sale_price": 310000, "sale_date": today,
+		})
+		require.Equal(t, 200, status)
+		assert.Equal(t, 310000.0, response.(map[string]interface{})["sale_price"])
+		assert.Nil(t, response.(map[string]interface{})["bedrooms"], "updates replace the comp's details")
+
+		status, _ = doJSONValue(t, app, viewerToken, http.MethodDelete, "/api/v1/comps/"+compID, nil)
+		assert.Equal(t, 403, status)
+		status, _ = doJSONValue(t, app, ownerToken, http.MethodDelete, "/api/v1/comps/"+compID, nil)
+		assert.Equal(t, 204, status)
+		status, _ = doJSONValue(t, app, ownerToken, http.MethodDelete, "/api/v1/comps/"+compID, nil)
+		assert.Equal(t, 404, status)
+	})
+}
@@ -3,22 +3,28 @@ package contract
 import (
 	"bytes"
 	"encoding/json"
+	"net/http"
 	"net/http/httptest"
 	"testing"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/require"
+
+	"rental-property-mgmt/tests/testutil"
 )
 
-// setupTestApp creates a test instance of the Fiber app
-// This will fail until we implement the actual app setup
+// setupTestEnv creates the app on a fresh database that lives as long as the
+// test, for tests that also need fixtures
+func setupTestEnv(t *testing.T) *testutil.Env {
+	return testutil.New(t)
+}
+
+// setupTestApp creates a test instance of the Fiber app on a fresh database
 func setupTestApp(t *testing.T) *fiber.App {
-	// TODO: This will be implemented in the main implementation phase
-	// For now, this will panic to ensure tests fail during RED phase
-	panic("setupTestApp not implemented - this ensures tests fail in RED phase")
+	return setupTestEnv(t).App
 }
 
 // createTestUser is a helper to create users for testing
-// This will fail until we implement user registration
 func createTestUser(t *testing.T, app *fiber.App, userData map[string]interface{}) map[string]interface{} {
 	jsonPayload, err := json.Marshal(userData)
 	if err != nil {
@@ -83,3 +89,41 @@ func getAuthToken(t *testing.T, app *fiber.App, email, password string) string {
 
 	return token
 }
+
+// doJSONValue sends payload, unless nil, as the JSON body of a request
+// authorized with token and returns the status with the decoded response;
+// a 204 No Content response decodes to nil
+func doJSONValue(t *testing.T, app *fiber.App, token, method, path string, payload interface{}) (int, interface{}) {
+	t.Helper()
+	body := bytes.NewBuffer(nil)
+	if payload != nil {
+		jsonPayload, err := json.Marshal(payload)
+		require.NoError(t, err)
+		body = bytes.NewBuffer(jsonPayload)
+	}
+
+	req := httptest.NewRequest(method, path, body)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+
+	var response interface{}
+	if resp.StatusCode != http.StatusNoContent {
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&response))
+	}
+	return resp.StatusCode, response
+}
+
+// doJSON is doJSONValue for endpoints that respond with a JSON object
+func doJSON(t *testing.T, app *fiber.App, token, method, path string, payload interface{}) (int, map[string]interface{}) {
+	t.Helper()
+	status, response := doJSONValue(t, app, token, method, path, payload)
+	if response == nil {
+		return status, nil
+	}
+	object, ok := response.(map[string]interface{})
+	require.True(t, ok, "%s %s responded with %v, not a JSON object", method, path, response)
+	return status, object
+}
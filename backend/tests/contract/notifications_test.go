@@ -0,0 +1,162 @@
+package contract
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommentNotificationsContract(t *testing.T) {
+	app := setupTestApp(t)
+
+	createTestUser(t, app, map[string]interface{}{
+		"email":      "owner@example.com",
+		"password":   "testpass123",
+		"first_name": "Olive",
+		"last_name":  "Owner",
+	})
+	createTestUser(t, app, map[string]interface{}{
+		"email":      "analyst@example.com",
+		"password":   "testpass123",
+		"first_name": "Andy",
+		"last_name":  "Analyst",
+	})
+	ownerToken := getAuthToken(t, app, "owner@example.com", "testpass123")
+	analystToken := getAuthToken(t, app, "analyst@example.com", "testpass123")
+
+	status, property := doJSON(t, app, ownerToken, http.MethodPost, "/api/v1/properties", map[string]interface{}{
+		"address":        "5 Mention Way, Anytown, ST 12345",
+		"purchase_price": 180000,
+	})
+	require.Equal(t, 201, status)
+	commentsPath := "/api/v1/properties/" + property["id"].(string) + "/comments"
+
+	t.Run("Mentioning an unknown user is rejected", func(t *testing.T) {
+		status, body := doJSON(t, app, ownerToken, http.MethodPost, commentsPath, map[string]interface{}{
+			"content": "@nobody can you check this?",
+		})
+		assert.Equal(t, 400, status)
+		assert.Contains(t, body["error"], "@nobody")
+	})
+
+	status, root := doJSON(t, app, ownerToken, http.MethodPost, commentsPath, map[string]interface{}{
+		"content": "@analyst please run the numbers",
+	})
+	require.Equal(t, 201, status)
+	rootID := root["id"].(string)
+
+	t.Run("Mentioned user gets an unread notification", func(t *testing.T) {
+		status, inbox := doJSON(t, app, analystToken, http.MethodGet, "/api/v1/notifications?unread=true", nil)
+		require.Equal(t, 200, status)
+		assert.Equal(t, 1.0, inbox["unread"])
+
+		items := inbox["notifications"].([]interface{})
+		require.Len(t, items, 1)
+		notification := items[0].(map[string]interface{})
+		assert.Equal(t, "mention", notification["type"])
+		assert.Equal(t, rootID, notification["comment_id"])
+		assert.Equal(t, "Olive Owner", notification["actor_name"])
+		assert.Nil(t, notification["read_at"])
+	})
+
+	t.Run("Comment author is notified of replies", func(t *testing.T) {
+		status, _ := doJSON(t, app, analystToken, http.MethodPost, commentsPath, map[string]interface{}{
+			"content":   "Cap rate comes out at 7.2%",
+			"parent_id": rootID,
+		})
+		require.Equal(t, 201, status)
+
+		status, inbox := doJSON(t, app, ownerToken, http.MethodGet, "/api/v1/notifications", nil)
+		require.Equal(t, 200, status)
+		items := inbox["notifications"].([]interface{})
+		require.Len(t, items, 1)
+		assert.Equal(t, "reply", items[0].(map[string]interface{})["type"])
+	})
+
+	t.Run("Replying to your own comment does not notify you", func(t *testing.T) {
+		status, _ := doJSON(t, app, ownerToken, http.MethodPost, commentsPath, map[string]interface{}{
+			"content":   "Following up on my own note",
+			"parent_id": rootID,
+		})
+		require.Equal(t, 201, status)
+
+		_, inbox := doJSON(t, app, ownerToken, http.MethodGet, "/api/v1/notifications", nil)
+		assert.Equal(t, 1.0, inbox["total"])
+	})
+
+	t.Run("Mark read", func(t *testing.T) {
+		_, inbox := doJSON(t, app, analystToken, http.MethodGet, "/api/v1/notifications", nil)
+		id := inbox["notifications"].([]interface{})[0].(map[string]interface{})["id"].(string)
+
+		status, _ := doJSON(t, app, ownerToken, http.MethodPost, "/api/v1/notifications/"+id+"/read", nil)
+		assert.Equal(t, 404, status, "users cannot mark another user's notifications")
+
+		status, _ = doJSON(t, app, analystToken, http.MethodPost, "/api/v1/notifications/"+id+"/read", nil)
+		assert.Equal(t, 204, status)
+
+		_, inbox = doJSON(t, app, analystToken, http.MethodGet, "/api/v1/notifications?unread=true", nil)
+		assert.Equal(t, 0.0, inbox["unread"])
+		assert.Empty(t, inbox["notifications"])
+
+		status, _ = doJSON(t, app, ownerToken, http.MethodPost, "/api/v1/notifications/read-all", nil)
+		assert.Equal(t, 204, status)
+		_, inbox = doJSON(t, app, ownerToken, http.MethodGet, "/api/v1/notifications?unread=true", nil)
+		assert.Equal(t, 0.0, inbox["unread"])
+	})
+
+	t.Run("Editing a comment notifies only newly mentioned users", func(t *testing.T) {
+		status, _ := doJSON(t, app, ownerToken, http.MethodPut, "/api/v1/comments/"+rootID, map[string]interface{}{
+			"content": "@analyst please run the numbers (updated with taxes)",
+		})
+		require.Equal(t, 200, status)
+
+		_, inbox := doJSON(t, app, analystToken, http.MethodGet, "/api/v1/notifications?unread=true", nil)
+		assert.Equal(t, 0.0, inbox["unread"])
+	})
+}
+
+func TestCommentNotificationDeliveryDoesNotHoldUpComment(t *testing.T) {
+	received := make(chan struct{}, 1)
+	release := make(chan struct{})
+	webhook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received <- struct{}{}
+		<-release
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	t.Cleanup(webhook.Close)
+	t.Cleanup(func() { close(release) })
+	t.Setenv("NOTIFY_WEBHOOK_URL", webhook.URL)
+
+	env := setupTestEnv(t)
+	owner := env.User(t, "owner@example.com")
+	env.User(t, "analyst@example.com")
+	property := env.Property(t, owner)
+
+	payload, err := json.Marshal(map[string]interface{}{"content": "@analyst please run the numbers"})
+	require.NoError(t, err)
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/properties/"+property.ID.String()+"/comments", bytes.NewBuffer(payload))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+env.Token(t, owner))
+
+	started := time.Now()
+	resp, err := env.App.Test(req, 5000)
+	require.NoError(t, err)
+	assert.Equal(t, 201, resp.StatusCode, "delivery problems never fail the comment")
+	assert.Less(t, time.Since(started), 2*time.Second, "the comment does not wait for delivery")
+
+	var inbox int64
+	require.NoError(t, env.DB.Table("notifications").Where("type = ?", "mention").Count(&inbox).Error)
+	assert.Equal(t, int64(1), inbox, "the inbox entry is recorded with the comment")
+
+	select {
+	case <-received:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the notification was never sent")
+	}
+}
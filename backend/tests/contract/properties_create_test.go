@@ -12,7 +12,6 @@ import (
 )
 
 func TestPropertiesCreateContract(t *testing.T) {
-	// This test MUST fail initially - no implementation exists yet
 	app := setupTestApp(t)
 
 	// Create test user and get auth token
@@ -140,8 +139,8 @@ func TestPropertiesCreateContract(t *testing.T) {
 			// Additional validation for successful creation
 			if tt.expectedStatus == 201 {
 				assert.Equal(t, tt.payload["address"], response["address"])
-				assert.Equal(t, tt.payload["purchase_price"], response["purchase_price"])
-				assert.Equal(t, tt.payload["intended_rent"], response["intended_rent"])
+				assert.EqualValues(t, tt.payload["purchase_price"], response["purchase_price"], "JSON numbers decode as float64")
+				assert.EqualValues(t, tt.payload["intended_rent"], response["intended_rent"])
 				assert.NotEmpty(t, response["id"])
 				assert.NotEmpty(t, response["user_id"])
 				assert.NotEmpty(t, response["created_at"])
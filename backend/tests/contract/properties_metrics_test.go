@@ -0,0 +1,50 @@
+package contract
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"rental-property-mgmt/internal/models"
+)
+
+func TestPropertiesRecalculateMetricsContract(t *testing.T) {
+	env := setupTestEnv(t)
+	owner := env.User(t, "owner@example.com")
+	colleague := env.User(t, "colleague@example.com")
+	property := env.Property(t, owner)
+	bare := env.Property(t, owner, func(p *models.Property) {
+		p.FinancingTerms = nil
+	})
+
+	recalculate := func(token, id string) (int, map[string]interface{}) {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/properties/"+id+"/metrics", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		resp, err := env.App.Test(req)
+		require.NoError(t, err)
+
+		var response map[string]interface{}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&response))
+		return resp.StatusCode, response
+	}
+
+	status, metrics := recalculate(env.Token(t, owner), property.ID.String())
+	require.Equal(t, 200, status)
+	assert.Equal(t, property.ID.String(), metrics["property_id"])
+	assert.InDelta(t, 5.84, metrics["cap_rate"], 0.01)
+
+	status, response := recalculate(env.Token(t, colleague), property.ID.String())
+	assert.Equal(t, 403, status, "only the owner recalculates")
+	assert.Contains(t, response, "error")
+
+	status, response = recalculate(env.Token(t, owner), bare.ID.String())
+	assert.Equal(t, 400, status, "financing terms are required")
+	assert.Contains(t, response, "error")
+
+	status, _ = recalculate(env.Token(t, owner), "00000000-0000-0000-0000-000000000000")
+	assert.Equal(t, 404, status)
+}
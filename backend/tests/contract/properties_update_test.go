@@ -0,0 +1,78 @@
+package contract
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPropertiesUpdateAddressContract(t *testing.T) {
+	env := setupTestEnv(t)
+	owner := env.User(t, "owner@example.com")
+	token := env.Token(t, owner)
+
+	update := func(id string, payload map[string]interface{}) map[string]interface{} {
+		jsonPayload, err := json.Marshal(payload)
+		require.NoError(t, err)
+		req := httptest.NewRequest(http.MethodPut, "/api/v1/properties/"+id, bytes.NewBuffer(jsonPayload))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+token)
+
+		resp, err := env.App.Test(req)
+		require.NoError(t, err)
+		require.Equal(t, 200, resp.StatusCode)
+
+		var property map[string]interface{}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&property))
+		return property
+	}
+
+	t.Run("address-only update re-parses city, state and ZIP", func(t *testing.T) {
+		property := env.Property(t, owner)
+		require.Equal(t, "Anytown", *property.City)
+
+		updated := update(property.ID.String(), map[string]interface{}{
+			"address": "9 Elm Rd, Springfield, IL 62704",
+		})
+		assert.Equal(t, "Springfield", updated["city"])
+		assert.Equal(t, "IL", updated["state"])
+		assert.Equal(t, "62704", updated["zip_code"])
+	})
+
+	t.Run("components in the request win over the new address", func(t *testing.T) {
+		property := env.Property(t, owner)
+
+		updated := update(property.ID.String(), map[string]interface{}{
+			"address": "9 Elm Rd, Springfield, IL 62704",
+			"city":    "Springfield Township",
+		})
+		assert.Equal(t, "Springfield Township", updated["city"])
+		assert.Equal(t, "IL", updated["state"])
+		assert.Equal(t, "62704", updated["zip_code"])
+	})
+
+	t.Run("an address that does not parse clears the old components", func(t *testing.T) {
+		property := env.Property(t, owner)
+
+		updated := update(property.ID.String(), map[string]interface{}{
+			"address": "Lot 7, Rural Route 2",
+		})
+		assert.Nil(t, updated["city"])
+		assert.Nil(t, updated["state"])
+		assert.Nil(t, updated["zip_code"])
+	})
+
+	t.Run("other updates keep the components", func(t *testing.T) {
+		property := env.Property(t, owner)
+		update(property.ID.String(), map[string]interface{}{"city": "Anyville"})
+
+		updated := update(property.ID.String(), map[string]interface{}{"purchase_price": 240000})
+		assert.Equal(t, "Anyville", updated["city"])
+		assert.Equal(t, "ST", updated["state"])
+	})
+}
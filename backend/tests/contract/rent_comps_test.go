@@ -0,0 +1,127 @@
+package contract
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRentCompsContract(t *testing.T) {
+	app := setupTestApp(t)
+
+	createTestUser(t, app, map[string]interface{}{
+		"email":      "renter@example.com",
+		"password":   "testpass123",
+		"first_name": "Reese",
+		"last_name":  "Renter",
+	})
+	createTestUser(t, app, map[string]interface{}{
+		"email":      "bystander@example.com",
+		"password":   "testpass123",
+		"first_name": "Bea",
+		"last_name":  "Stander",
+	})
+	ownerToken := getAuthToken(t, app, "renter@example.com", "testpass123")
+	viewerToken := getAuthToken(t, app, "bystander@example.com", "testpass123")
+
+	status, created := doJSONValue(t, app, ownerToken, http.MethodPost, "/api/v1/properties", map[string]interface{}{
+		"address":            "21 Lease Ln, Anytown, ST 12345",
+		"purchase_price":     300000,
+		"intended_rent":      2500,
+		"building_area_sqft": 1200,
+		"bedrooms":           3,
+		"bathrooms":          2,
+	})
+	require.Equal(t, 201, status)
+	propertyID := created.(map[string]interface{})["id"].(string)
+	rentCompsPath := "/api/v1/properties/" + propertyID + "/rent-comps"
+	today := time.Now().UTC().Format("2006-01-02")
+
+	var compID string
+	t.Run("Create", func(t *testing.T) {
+		rentComp := func(rent float64, sqft, beds int, baths float64) map[string]interface{} {
+			return map[string]interface{}{
+				"address": "Nearby", "monthly_rent": rent, "listing_date": today,
+				"building_area_sqft": sqft, "bedrooms": beds, "bathrooms": baths,
+			}
+		}
+
+		status, _ := doJSONValue(t, app, viewerToken, http.MethodPost, rentCompsPath, rentComp(1800, 900, 2, 1))
+		assert.Equal(t, 403, status, "only the owner records rent comps")
+
+		tomorrow := time.Now().UTC().AddDate(0, 0, 1).Format("2006-01-02")
+		future := rentComp(1800, 900, 2, 1)
+		future["listing_date"] = tomorrow
+		status, _ = doJSONValue(t, app, ownerToken, http.MethodPost, rentCompsPath, future)
+		assert.Equal(t, 400, status, "a listing cannot be in the future")
+
+		// Rents follow 1000 + 300 per bedroom + 200 per bathroom at $2/sqft
+		for _, comp := range []map[string]interface{}{
+			rentComp(1800, 900, 2, 1),
+			rentComp(2300, 1150, 3, 2),
+			rentComp(2100, 1050, 3, 1),
+			rentComp(2600, 1300, 4, 2),
+		} {
+			status, response := doJSONValue(t, app, ownerToken, http.MethodPost, rentCompsPath, comp)
+			require.Equal(t, 201, status)
+			compID = response.(map[string]interface{})["id"].(string)
+		}
+
+		status, response := doJSONValue(t, app, viewerToken, http.MethodGet, rentCompsPath, nil)
+		require.Equal(t, 200, status)
+		assert.Len(t, response, 4)
+	})
+
+	t.Run("Estimate", func(t *testing.T) {
+		status, response := doJSONValue(t, app, viewerToken, http.MethodPost, rentCompsPath+"/estimate", nil)
+		require.Equal(t, 200, status, "any team member can preview the estimate")
+		estimate := response.(map[string]interface{})
+		assert.Equal(t, 2200.0, estimate["median_rent"])
+		assert.Equal(t, 2400.0, estimate["per_sqft"].(map[string]interface{})["rent"])
+		assert.Equal(t, 2300.0, estimate["regression"].(map[string]interface{})["rent"])
+		assert.Equal(t, 2350.0, estimate["suggested_rent"])
+		assert.Equal(t, 300.0, estimate["intended_deviation"])
+		assert.Equal(t, 13.64, estimate["intended_deviation_percent"])
+
+		status, _ = doJSONValue(t, app, ownerToken, http.MethodPost, rentCompsPath+"/estimate", map[string]interface{}{
+			"annual_rent_growth_percent": 80,
+		})
+		assert.Equal(t, 400, status)
+
+		status, _ = doJSONValue(t, app, viewerToken, http.MethodPost, rentCompsPath+"/estimate", map[string]interface{}{"save": true})
+		assert.Equal(t, 403, status, "only the owner stores the estimate")
+
+		status, response = doJSONValue(t, app, ownerToken, http.MethodPost, rentCompsPath+"/estimate", map[string]interface{}{"save": true})
+		require.Equal(t, 201, status)
+		stored := response.(map[string]interface{})["valuation"].(map[string]interface{})
+		assert.Equal(t, "Comps", stored["source"])
+		assert.Equal(t, "rental_estimate", stored["valuation_type"])
+		assert.Equal(t, 2350.0, stored["value"])
+		assert.Equal(t, 2300.0, stored["value_low"])
+		assert.Equal(t, 2400.0, stored["value_high"])
+
+		status, response = doJSONValue(t, app, viewerToken, http.MethodGet, "/api/v1/properties/"+propertyID+"/valuations?type=rental_estimate", nil)
+		require.Equal(t, 200, status)
+		assert.Len(t, response, 1)
+	})
+
+	t.Run("Update and delete", func(t *testing.T) {
+		update := map[string]interface{}{"address": "Nearby", "monthly_rent": 2700, "listing_date": today}
+		status, _ := doJSONValue(t, app, viewerToken, http.MethodPut, "/api/v1/rent-comps/"+compID, update)
+		assert.Equal(t, 403, status)
+
+		status, response := doJSONValue(t, app, ownerToken, http.MethodPut, "/api/v1/rent-comps/"+compID, update)
+		require.Equal(t, 200, status)
+		assert.Equal(t, 2700.0, response.(map[string]interface{})["monthly_rent"])
+
+		status, _ = doJSONValue(t, app, viewerToken, http.MethodDelete, "/api/v1/rent-comps/"+compID, nil)
+		assert.Equal(t, 403, status)
+		status, _ = doJSONValue(t, app, ownerToken, http.MethodDelete, "/api/v1/rent-comps/"+compID, nil)
+		assert.Equal(t, 204, status)
+		status, _ = doJSONValue(t, app, ownerToken, http.MethodGet, rentCompsPath, nil)
+		assert.Equal(t, 200, status)
+	})
+}
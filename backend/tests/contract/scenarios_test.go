@@ -0,0 +1,92 @@
+package contract
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScenariosContract(t *testing.T) {
+	app := setupTestApp(t)
+
+	testUser := map[string]interface{}{
+		"email":      "scenarios@example.com",
+		"password":   "testpass123",
+		"first_name": "Scenario",
+		"last_name":  "Planner",
+	}
+	createTestUser(t, app, testUser)
+	token := getAuthToken(t, app, "scenarios@example.com", "testpass123")
+
+	status, property := doJSON(t, app, token, http.MethodPost, "/api/v1/properties", map[string]interface{}{
+		"address":        "123 Main St, Anytown, ST 12345",
+		"purchase_price": 250000,
+		"intended_rent":  2100,
+		"operating_expenses": map[string]interface{}{
+			"insurance":      1200,
+			"property_taxes": 3600,
+		},
+		"financing_terms": map[string]interface{}{
+			"interest_rate":        7.5,
+			"loan_term":            30,
+			"down_payment_percent": 25,
+			"closing_costs":        5000,
+		},
+		"operating_assumptions": map[string]interface{}{
+			"vacancy_rate": 0.05,
+		},
+	})
+	require.Equal(t, 201, status)
+	propertyID := property["id"].(string)
+
+	t.Run("missing name", func(t *testing.T) {
+		status, response := doJSON(t, app, token, http.MethodPost, "/api/v1/properties/"+propertyID+"/scenarios", map[string]interface{}{
+			"purchase_price": 230000,
+		})
+		assert.Equal(t, 400, status)
+		assert.Contains(t, response, "error")
+	})
+
+	status, scenario := doJSON(t, app, token, http.MethodPost, "/api/v1/properties/"+propertyID+"/scenarios", map[string]interface{}{
+		"name":           "Offer price, 20% down, seller credit",
+		"purchase_price": 230000,
+		"financing_terms": map[string]interface{}{
+			"down_payment_percent": 20,
+			"seller_credit":        3000,
+		},
+	})
+	require.Equal(t, 201, status)
+	assert.NotEmpty(t, scenario["id"])
+	require.Contains(t, scenario, "metrics")
+
+	metrics := scenario["metrics"].(map[string]interface{})
+	assert.Equal(t, 48000.0, metrics["cash_to_close"], "20% of 230000 + 5000 closing - 3000 credit")
+
+	t.Run("side-by-side comparison", func(t *testing.T) {
+		status, comparison := doJSON(t, app, token, http.MethodGet, "/api/v1/properties/"+propertyID+"/scenarios/compare", nil)
+		require.Equal(t, 200, status)
+
+		base := comparison["base"].(map[string]interface{})
+		assert.Equal(t, 250000.0, base["purchase_price"])
+		baseMetrics := base["metrics"].(map[string]interface{})
+		assert.Equal(t, 67500.0, baseMetrics["cash_to_close"])
+
+		scenarios := comparison["scenarios"].([]interface{})
+		require.Len(t, scenarios, 1)
+		result := scenarios[0].(map[string]interface{})
+		assert.Equal(t, scenario["id"], result["scenario_id"])
+		deltas := result["deltas"].(map[string]interface{})
+		assert.Equal(t, -19500.0, deltas["cash_to_close"])
+	})
+
+	t.Run("base property is untouched", func(t *testing.T) {
+		status, reloaded := doJSON(t, app, token, http.MethodGet, "/api/v1/properties/"+propertyID, nil)
+		require.Equal(t, 200, status)
+		assert.Equal(t, 250000.0, reloaded["purchase_price"])
+		terms := reloaded["financing_terms"].(map[string]interface{})
+		assert.Equal(t, 25.0, terms["down_payment_percent"])
+		assert.NotContains(t, terms, "seller_credit")
+	})
+}
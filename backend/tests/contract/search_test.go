@@ -0,0 +1,101 @@
+package contract
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearchContract(t *testing.T) {
+	app := setupTestApp(t)
+
+	createTestUser(t, app, map[string]interface{}{
+		"email":      "seeker@example.com",
+		"password":   "testpass123",
+		"first_name": "Sam",
+		"last_name":  "Seeker",
+	})
+	createTestUser(t, app, map[string]interface{}{
+		"email":      "colleague@example.com",
+		"password":   "testpass123",
+		"first_name": "Cal",
+		"last_name":  "Colleague",
+	})
+	seekerToken := getAuthToken(t, app, "seeker@example.com", "testpass123")
+	colleagueToken := getAuthToken(t, app, "colleague@example.com", "testpass123")
+
+	status, mine := doJSON(t, app, seekerToken, http.MethodPost, "/api/v1/properties", map[string]interface{}{
+		"address":        "12 Maple Street, Anytown, ST 12345",
+		"purchase_price": 210000,
+		"local_context":  map[string]interface{}{"notes": "Quiet street near the park"},
+	})
+	require.Equal(t, 201, status)
+	mineID := mine["id"].(string)
+
+	status, theirs := doJSON(t, app, colleagueToken, http.MethodPost, "/api/v1/properties", map[string]interface{}{
+		"address":        "98 Oak Avenue, Anytown, ST 12345",
+		"purchase_price": 185000,
+	})
+	require.Equal(t, 201, status)
+	theirsID := theirs["id"].(string)
+
+	status, _ = doJSON(t, app, colleagueToken, http.MethodPost, "/api/v1/properties/"+theirsID+"/comments", map[string]interface{}{
+		"content": "Inspector found a <b>foundation</b> issue in the basement",
+	})
+	require.Equal(t, 201, status)
+	status, deleted := doJSON(t, app, seekerToken, http.MethodPost, "/api/v1/properties/"+mineID+"/comments", map[string]interface{}{
+		"content": "Foundation looked fine to me",
+	})
+	require.Equal(t, 201, status)
+	status, _ = doJSON(t, app, seekerToken, http.MethodDelete, "/api/v1/comments/"+deleted["id"].(string), nil)
+	require.Equal(t, 204, status)
+
+	search := func(token, query string) (int, map[string]interface{}) {
+		return doJSON(t, app, token, http.MethodGet, "/api/v1/search?"+query, nil)
+	}
+
+	t.Run("Finds comments with highlighted snippets", func(t *testing.T) {
+		status, results := search(seekerToken, "q="+url.QueryEscape("foundation issues"))
+		require.Equal(t, 200, status)
+		assert.Equal(t, 1.0, results["total"], "deleted comments are not searchable")
+
+		hit := results["hits"].([]interface{})[0].(map[string]interface{})
+		assert.Equal(t, "comment", hit["type"])
+		assert.Equal(t, theirsID, hit["property_id"])
+		assert.Equal(t, "98 Oak Avenue, Anytown, ST 12345", hit["address"])
+		assert.Contains(t, hit["snippet"], "<mark>foundation</mark>")
+		assert.NotContains(t, hit["snippet"], "<b>", "stored markup is escaped")
+	})
+
+	t.Run("Finds properties by address and notes", func(t *testing.T) {
+		status, results := search(seekerToken, "q=maple")
+		require.Equal(t, 200, status)
+		require.Equal(t, 1.0, results["total"])
+		hit := results["hits"].([]interface{})[0].(map[string]interface{})
+		assert.Equal(t, "property", hit["type"])
+		assert.Equal(t, mineID, hit["id"])
+		assert.Contains(t, hit["snippet"], "<mark>Maple</mark>")
+
+		status, results = search(colleagueToken, "q=park")
+		require.Equal(t, 200, status)
+		assert.Equal(t, 1.0, results["total"], "team members can find each other's properties")
+	})
+
+	t.Run("Filters", func(t *testing.T) {
+		status, results := search(colleagueToken, "q=foundation&type=property")
+		require.Equal(t, 200, status)
+		assert.Equal(t, 0.0, results["total"])
+
+		status, results = search(seekerToken, "q=foundation&mine=true")
+		require.Equal(t, 200, status)
+		assert.Equal(t, 0.0, results["total"])
+
+		status, _ = search(seekerToken, "q=foundation&type=scenario")
+		assert.Equal(t, 400, status)
+		status, _ = search(seekerToken, "q=+")
+		assert.Equal(t, 400, status)
+	})
+}
@@ -0,0 +1,139 @@
+package contract
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSentimentContract(t *testing.T) {
+	app := setupTestApp(t)
+
+	createTestUser(t, app, map[string]interface{}{
+		"email":      "investor@example.com",
+		"password":   "testpass123",
+		"first_name": "Ivy",
+		"last_name":  "Investor",
+	})
+	createTestUser(t, app, map[string]interface{}{
+		"email":      "partner@example.com",
+		"password":   "testpass123",
+		"first_name": "Pat",
+		"last_name":  "Partner",
+	})
+	investorToken := getAuthToken(t, app, "investor@example.com", "testpass123")
+	partnerToken := getAuthToken(t, app, "partner@example.com", "testpass123")
+
+	createProperty := func(address string, price float64) string {
+		status, property := doJSON(t, app, investorToken, http.MethodPost, "/api/v1/properties", map[string]interface{}{
+			"address":        address,
+			"purchase_price": price,
+		})
+		require.Equal(t, 201, status)
+		return property["id"].(string)
+	}
+	favoriteID := createProperty("10 Favorite Ave, Anytown, ST 12345", 150000)
+	splitID := createProperty("20 Split Ln, Anytown, ST 12345", 250000)
+	createProperty("30 Quiet Ct, Anytown, ST 12345", 350000)
+
+	t.Run("Reactions", func(t *testing.T) {
+		status, comment := doJSON(t, app, investorToken, http.MethodPost, "/api/v1/properties/"+favoriteID+"/comments", map[string]interface{}{
+			"content": "Great rent-to-value",
+		})
+		require.Equal(t, 201, status)
+		reactionsPath := "/api/v1/comments/" + comment["id"].(string) + "/reactions"
+
+		status, _ = doJSON(t, app, partnerToken, http.MethodPost, reactionsPath, map[string]interface{}{"emoji": "not an emoji"})
+		assert.Equal(t, 400, status)
+
+		for _, token := range []string{partnerToken, investorToken, partnerToken} {
+			status, _ = doJSON(t, app, token, http.MethodPost, reactionsPath, map[string]interface{}{"emoji": "👍"})
+			require.Equal(t, 200, status)
+		}
+		status, response := doJSON(t, app, partnerToken, http.MethodPost, reactionsPath, map[string]interface{}{"emoji": "🔥"})
+		require.Equal(t, 200, status)
+		reactions := response["reactions"].([]interface{})
+		require.Len(t, reactions, 2)
+		assert.Equal(t, "👍", reactions[0].(map[string]interface{})["emoji"])
+		assert.Equal(t, 2.0, reactions[0].(map[string]interface{})["count"], "reacting twice with the same emoji counts once")
+
+		status, fetched := doJSON(t, app, investorToken, http.MethodGet, "/api/v1/comments/"+comment["id"].(string), nil)
+		require.Equal(t, 200, status)
+		assert.Len(t, fetched["reactions"], 2)
+
+		status, _ = doJSON(t, app, partnerToken, http.MethodDelete, reactionsPath+"?emoji="+url.QueryEscape("🔥"), nil)
+		assert.Equal(t, 204, status)
+		status, _ = doJSON(t, app, partnerToken, http.MethodDelete, reactionsPath+"?emoji="+url.QueryEscape("🔥"), nil)
+		assert.Equal(t, 404, status)
+	})
+
+	t.Run("Votes", func(t *testing.T) {
+		status, _ := doJSON(t, app, partnerToken, http.MethodPut, "/api/v1/properties/"+favoriteID+"/vote", map[string]interface{}{"decision": "buy"})
+		assert.Equal(t, 400, status)
+
+		status, tally := doJSON(t, app, partnerToken, http.MethodPut, "/api/v1/properties/"+favoriteID+"/vote", map[string]interface{}{"decision": "maybe"})
+		require.Equal(t, 200, status)
+		assert.Equal(t, "maybe", tally["my_vote"])
+
+		status, tally = doJSON(t, app, partnerToken, http.MethodPut, "/api/v1/properties/"+favoriteID+"/vote", map[string]interface{}{"decision": "pursue"})
+		require.Equal(t, 200, status)
+		assert.Equal(t, 1.0, tally["total"], "voting again replaces the earlier vote")
+		assert.Equal(t, 1.0, tally["pursue"])
+
+		status, _ = doJSON(t, app, investorToken, http.MethodPut, "/api/v1/properties/"+favoriteID+"/vote", map[string]interface{}{"decision": "pursue"})
+		require.Equal(t, 200, status)
+		status, _ = doJSON(t, app, investorToken, http.MethodPut, "/api/v1/properties/"+splitID+"/vote", map[string]interface{}{"decision": "pursue"})
+		require.Equal(t, 200, status)
+		status, _ = doJSON(t, app, partnerToken, http.MethodPut, "/api/v1/properties/"+splitID+"/vote", map[string]interface{}{"decision": "pass"})
+		require.Equal(t, 200, status)
+
+		status, votes := doJSON(t, app, investorToken, http.MethodGet, "/api/v1/properties/"+favoriteID+"/votes", nil)
+		require.Equal(t, 200, status)
+		summary := votes["tally"].(map[string]interface{})
+		assert.Equal(t, 2.0, summary["pursue"])
+		assert.Equal(t, "pursue", summary["leading"])
+		assert.Equal(t, "pursue", summary["my_vote"])
+		assert.Len(t, votes["votes"], 2)
+
+		status, votes = doJSON(t, app, investorToken, http.MethodGet, "/api/v1/properties/"+splitID+"/votes", nil)
+		require.Equal(t, 200, status)
+		assert.Nil(t, votes["tally"].(map[string]interface{})["leading"], "tied votes have no leading decision")
+	})
+
+	t.Run("Portfolio listing filters by tally", func(t *testing.T) {
+		ids := func(path string) []string {
+			status, response := doJSON(t, app, investorToken, http.MethodGet, path, nil)
+			require.Equal(t, 200, status)
+			var result []string
+			for _, item := range response["properties"].([]interface{}) {
+				result = append(result, item.(map[string]interface{})["id"].(string))
+			}
+			return result
+		}
+
+		assert.Len(t, ids("/api/v1/properties"), 3)
+		assert.Equal(t, []string{favoriteID}, ids("/api/v1/properties?decision=pursue"))
+		assert.Equal(t, []string{favoriteID}, ids("/api/v1/properties?min_pursue=2"))
+		assert.Len(t, ids("/api/v1/properties?max_pass=0"), 2)
+		assert.Equal(t, []string{favoriteID, splitID}, ids("/api/v1/properties?sort=pursue_votes&min_pursue=1&order=desc")[:2])
+
+		status, response := doJSON(t, app, investorToken, http.MethodGet, "/api/v1/properties?decision=pursue", nil)
+		require.Equal(t, 200, status)
+		assert.Equal(t, 1.0, response["total"])
+		votes := response["properties"].([]interface{})[0].(map[string]interface{})["votes"].(map[string]interface{})
+		assert.Equal(t, 2.0, votes["pursue"])
+		assert.Equal(t, "pursue", votes["my_vote"])
+
+		status, _ = doJSON(t, app, investorToken, http.MethodGet, "/api/v1/properties?decision=buy", nil)
+		assert.Equal(t, 400, status)
+		status, _ = doJSON(t, app, investorToken, http.MethodGet, "/api/v1/properties?min_pursue=-1", nil)
+		assert.Equal(t, 400, status)
+
+		status, _ = doJSON(t, app, partnerToken, http.MethodDelete, "/api/v1/properties/"+splitID+"/vote", nil)
+		assert.Equal(t, 204, status)
+		assert.Equal(t, []string{splitID}, ids("/api/v1/properties?decision=pursue&max_pass=0&sort=purchase_price&order=desc")[:1])
+	})
+}
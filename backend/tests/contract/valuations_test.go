@@ -0,0 +1,261 @@
+package contract
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValuationsContract(t *testing.T) {
+	app := setupTestApp(t)
+
+	createTestUser(t, app, map[string]interface{}{
+		"email":      "valuer@example.com",
+		"password":   "testpass123",
+		"first_name": "Val",
+		"last_name":  "Uer",
+	})
+	createTestUser(t, app, map[string]interface{}{
+		"email":      "viewer@example.com",
+		"password":   "testpass123",
+		"first_name": "Vic",
+		"last_name":  "Viewer",
+	})
+	ownerToken := getAuthToken(t, app, "valuer@example.com", "testpass123")
+	viewerToken := getAuthToken(t, app, "viewer@example.com", "testpass123")
+
+	status, created := doJSONValue(t, app, ownerToken, http.MethodPost, "/api/v1/properties", map[string]interface{}{
+		"address":            "7 Appraisal Rd, Anytown, ST 12345",
+		"purchase_price":     250000,
+		"intended_rent":      2000,
+		"operating_expenses": map[string]interface{}{"insurance": 1200, "property_taxes": 3600},
+		"financing_terms": map[string]interface{}{
+			"interest_rate":        7.5,
+			"loan_term":            30,
+			"down_payment_percent": 20,
+			"closing_costs":        5000,
+		},
+		"operating_assumptions": map[string]interface{}{"vacancy_rate": 0.05, "maintenance_pct": 0.10},
+	})
+	require.Equal(t, 201, status)
+	valuationsPath := "/api/v1/properties/" + created.(map[string]interface{})["id"].(string) + "/valuations"
+	metricsPath := "/api/v1/properties/" + created.(map[string]interface{})["id"].(string) + "/metrics"
+
+	t.Run("Sources", func(t *testing.T) {
+		status, response := doJSONValue(t, app, viewerToken, http.MethodGet, "/api/v1/valuations/sources", nil)
+		require.Equal(t, 200, status)
+		assert.Subset(t, response.(map[string]interface{})["sources"], []interface{}{"Redfin", "Rentimate", "Zillow"})
+		assert.NotContains(t, response.(map[string]interface{})["sources"], "Comps", "comps valuations are computed, not entered")
+	})
+
+	t.Run("Market rent metrics need rental estimates", func(t *testing.T) {
+		status, _ := doJSONValue(t, app, ownerToken, http.MethodGet, metricsPath+"?rent=market", nil)
+		assert.Equal(t, 400, status)
+	})
+
+	t.Run("Create", func(t *testing.T) {
+		status, _ := doJSONValue(t, app, viewerToken, http.MethodPost, valuationsPath, map[string]interface{}{
+			"source": "Zillow", "valuation_type": "market_value", "value": 255000,
+		})
+		assert.Equal(t, 403, status, "only the owner adds valuations")
+
+		status, _ = doJSONValue(t, app, ownerToken, http.MethodPost, valuationsPath, map[string]interface{}{
+			"source": "Zestimate", "valuation_type": "market_value", "value": 255000,
+		})
+		assert.Equal(t, 400, status)
+
+		status, _ = doJSONValue(t, app, ownerToken, http.MethodPost, valuationsPath, map[string]interface{}{
+			"source": "Comps", "valuation_type": "market_value", "value": 255000,
+		})
+		assert.Equal(t, 400, status, "only the comps endpoints store Comps valuations")
+
+		tomorrow := time.Now().UTC().AddDate(0, 0, 1).Format("2006-01-02")
+		status, _ = doJSONValue(t, app, ownerToken, http.MethodPost, valuationsPath, map[string]interface{}{
+			"source": "Zillow", "valuation_type": "market_value", "value": 255000, "valuation_date": tomorrow,
+		})
+		assert.Equal(t, 400, status)
+
+		monthAgo := time.Now().UTC().AddDate(0, -1, 0).Format("2006-01-02")
+		for _, valuation := range []map[string]interface{}{
+			{"source": "Zillow", "valuation_type": "market_value", "value": 240000, "valuation_date": monthAgo},
+			{"source": "Zillow", "valuation_type": "market_value", "value": 250000},
+			{"source": "Redfin", "valuation_type": "market_value", "value": 260000},
+			{"source": "Zillow", "valuation_type": "rental_estimate", "value": 2100},
+			{"source": "Rentimate", "valuation_type": "rental_estimate", "value": 2300},
+		} {
+			status, response := doJSONValue(t, app, ownerToken, http.MethodPost, valuationsPath, valuation)
+			require.Equal(t, 201, status)
+			assert.NotEmpty(t, response.(map[string]interface{})["valuation_date"])
+		}
+	})
+
+	t.Run("List", func(t *testing.T) {
+		status, response := doJSONValue(t, app, viewerToken, http.MethodGet, valuationsPath, nil)
+		require.Equal(t, 200, status)
+		assert.Len(t, response, 5)
+
+		status, response = doJSONValue(t, app, viewerToken, http.MethodGet, valuationsPath+"?type=rental_estimate", nil)
+		require.Equal(t, 200, status)
+		assert.Len(t, response, 2)
+	})
+
+	t.Run("Summary", func(t *testing.T) {
+		status, response := doJSONValue(t, app, viewerToken, http.MethodGet, valuationsPath+"/summary", nil)
+		require.Equal(t, 200, status)
+		summaries := response.(map[string]interface{})["summaries"].([]interface{})
+		require.Len(t, summaries, 2)
+
+		market := summaries[0].(map[string]interface{})
+		assert.Equal(t, "market_value", market["valuation_type"])
+		assert.Equal(t, 2.0, market["sources"])
+		assert.Equal(t, 255000.0, market["mean"])
+		assert.Equal(t, 10000.0, market["spread"])
+		assert.Equal(t, 0.0, market["newest_age_days"])
+
+		rent := summaries[1].(map[string]interface{})
+		assert.Equal(t, 2200.0, rent["median"])
+	})
+
+	t.Run("Metrics on intended and market rent", func(t *testing.T) {
+		status, response := doJSONValue(t, app, viewerToken, http.MethodGet, metricsPath, nil)
+		require.Equal(t, 200, status)
+		intended := response.(map[string]interface{})
+		assert.Equal(t, "intended", intended["rent_basis"])
+		assert.Equal(t, 2000.0, intended["monthly_rent"])
+
+		status, response = doJSONValue(t, app, viewerToken, http.MethodGet, metricsPath+"?rent=market", nil)
+		require.Equal(t, 200, status)
+		market := response.(map[string]interface{})
+		assert.Equal(t, "market", market["rent_basis"])
+		assert.Equal(t, 2200.0, market["monthly_rent"])
+		assert.Equal(t, false, market["is_current"])
+		assert.Greater(t, market["cap_rate"], intended["cap_rate"], "higher market rent raises the cap rate")
+
+		status, _ = doJSONValue(t, app, viewerToken, http.MethodGet, metricsPath+"?rent=asking", nil)
+		assert.Equal(t, 400, status)
+	})
+
+	t.Run("Import CSV", func(t *testing.T) {
+		twoMonthsAgo := time.Now().UTC().AddDate(0, -2, 0).Format("01/02/2006")
+		csvData := "Property,Estimate,Provider,Date\n" +
+			"\"7 Appraisal Road, Anytown, ST 12345\",\"$265,000\",Redfin,\n" +
+			"7 Appraisal Rd,262000,Zillow," + twoMonthsAgo + "\n" +
+			"99 Unknown Rd,100000,Zillow,\n" +
+			"7 Appraisal Rd,262000,Zestimate,\n"
+
+		upload := func(token string, fields map[string]string) (int, map[string]interface{}) {
+			body := bytes.NewBuffer(nil)
+			form := multipart.NewWriter(body)
+			part, err := form.CreateFormFile("file", "estimates.csv")
+			require.NoError(t, err)
+			_, err = part.Write([]byte(csvData))
+			require.NoError(t, err)
+			for name, value := range fields {
+				require.NoError(t, form.WriteField(name, value))
+			}
+			require.NoError(t, form.Close())
+
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/valuations/import", body)
+			req.Header.Set("Content-Type", form.FormDataContentType())
+			req.Header.Set("Authorization", "Bearer "+token)
+			resp, err := app.Test(req)
+			require.NoError(t, err)
+
+			var response map[string]interface{}
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(&response))
+			return resp.StatusCode, response
+		}
+		mapping := map[string]string{
+			"address_column": "property",
+			"value_column":   "estimate",
+			"source_column":  "provider",
+			"date_column":    "date",
+			"valuation_type": "market_value",
+		}
+
+		status, _ := upload(ownerToken, map[string]string{"address_column": "property"})
+		assert.Equal(t, 400, status, "the value column must be mapped or named value")
+
+		dryRun := map[string]string{"dry_run": "true"}
+		for name, value := range mapping {
+			dryRun[name] = value
+		}
+		status, report := upload(ownerToken, dryRun)
+		require.Equal(t, 200, status)
+		assert.Equal(t, true, report["dry_run"])
+		assert.Equal(t, 4.0, report["rows"])
+		assert.Equal(t, 0.0, report["created"])
+		results := report["results"].([]interface{})
+		require.Len(t, results, 4)
+		assert.Equal(t, "duplicate", results[0].(map[string]interface{})["status"], "Redfin already valued the property today")
+		assert.Equal(t, "matched", results[1].(map[string]interface{})["status"])
+		assert.Equal(t, "rejected", results[2].(map[string]interface{})["status"])
+		assert.Equal(t, "rejected", results[3].(map[string]interface{})["status"])
+		assert.Equal(t, 5.0, results[3].(map[string]interface{})["line"])
+
+		status, report = upload(viewerToken, mapping)
+		require.Equal(t, 200, status)
+		assert.Equal(t, 0.0, report["matched"], "only the importer's own properties match")
+		assert.Equal(t, 4.0, report["rejected"])
+
+		status, report = upload(ownerToken, mapping)
+		require.Equal(t, 200, status)
+		assert.Equal(t, 2.0, report["matched"])
+		assert.Equal(t, 1.0, report["created"])
+		assert.Equal(t, 1.0, report["duplicated"])
+		assert.Equal(t, 2.0, report["rejected"])
+		created := report["results"].([]interface{})[1].(map[string]interface{})
+		assert.Equal(t, "created", created["status"])
+		assert.Equal(t, 262000.0, created["valuation"].(map[string]interface{})["value"])
+
+		status, response := doJSONValue(t, app, viewerToken, http.MethodGet, valuationsPath+"?type=market_value", nil)
+		require.Equal(t, 200, status)
+		assert.Len(t, response, 4)
+
+		// A raw CSV body takes its mapping from the query string; importing
+		// the same file again only finds duplicates
+		req := httptest.NewRequest(http.MethodPost,
+			"/api/v1/valuations/import?address_column=property&value_column=estimate&source_column=provider&date_column=date&valuation_type=market_value",
+			bytes.NewBufferString(csvData))
+		req.Header.Set("Content-Type", "text/csv")
+		req.Header.Set("Authorization", "Bearer "+ownerToken)
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		require.Equal(t, 200, resp.StatusCode)
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&report))
+		assert.Equal(t, 0.0, report["created"])
+		assert.Equal(t, 2.0, report["duplicated"])
+	})
+
+	t.Run("History", func(t *testing.T) {
+		status, response := doJSONValue(t, app, viewerToken, http.MethodGet, valuationsPath+"/history?type=market_value&windows=45", nil)
+		require.Equal(t, 200, status)
+		body := response.(map[string]interface{})
+		assert.Equal(t, 2.0, body["sigma"])
+		histories := body["histories"].([]interface{})
+		require.Len(t, histories, 1)
+
+		series := histories[0].(map[string]interface{})["series"].([]interface{})
+		require.Len(t, series, 2)
+		zillow := series[1].(map[string]interface{})
+		assert.Equal(t, "Zillow", zillow["source"])
+		assert.Len(t, zillow["points"], 3)
+		assert.Equal(t, 250000.0, zillow["latest"].(map[string]interface{})["value"])
+		trailing := zillow["trailing"].([]interface{})[0].(map[string]interface{})
+		assert.Equal(t, 262000.0, trailing["base_value"])
+		assert.Equal(t, -4.58, trailing["change_percent"])
+		assert.Equal(t, 0.0, histories[0].(map[string]interface{})["outliers"], "two sources are too few to judge")
+
+		status, _ = doJSONValue(t, app, viewerToken, http.MethodGet, valuationsPath+"/history?sigma=0", nil)
+		assert.Equal(t, 400, status)
+		status, _ = doJSONValue(t, app, viewerToken, http.MethodGet, valuationsPath+"/history?windows=30,soon", nil)
+		assert.Equal(t, 400, status)
+	})
+}
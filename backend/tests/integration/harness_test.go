@@ -0,0 +1,53 @@
+package integration
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"rental-property-mgmt/internal/models"
+)
+
+func TestFixturesAndCleanup(t *testing.T) {
+	app := setupIntegrationApp(t)
+
+	owner := env.User(t, "owner@example.com")
+	colleague := env.User(t, "colleague@example.com")
+	property := env.Property(t, owner, func(p *models.Property) {
+		p.Address = "9 Fixture Lane, Anytown, ST 12345"
+	})
+	minCapRate := 5.0
+	env.Criteria(t, owner, func(c *models.BuyingBoxCriteria) {
+		c.MinCapRate = &minCapRate
+	})
+	env.Comment(t, colleague, property, "Fixtures show up through the API")
+
+	req := httptest.NewRequest("GET", "/api/v1/properties/"+property.ID.String()+"/comments", nil)
+	req.Header.Set("Authorization", "Bearer "+env.Token(t, owner))
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	require.Equal(t, 200, resp.StatusCode)
+
+	var threads map[string]interface{}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&threads))
+	assert.Equal(t, 1.0, threads["total"])
+
+	getAuthToken(t, app, "colleague@example.com", "testpass123")
+
+	require.NotNil(t, property.FinancialMetrics, "fixture properties have metrics")
+	assert.InDelta(t, 5.84, *property.FinancialMetrics.CapRate, 0.01)
+
+	cleanupIntegrationApp(t, app)
+
+	for _, table := range []string{"users", "properties", "buying_box_criteria", "comments", "financial_metrics"} {
+		var count int64
+		require.NoError(t, env.DB.Table(table).Count(&count).Error)
+		assert.Zero(t, count, table)
+	}
+	var applied int64
+	require.NoError(t, env.DB.Table("schema_migrations").Count(&applied).Error)
+	assert.NotZero(t, applied, "cleanup keeps the schema")
+}
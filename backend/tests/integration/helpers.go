@@ -7,20 +7,27 @@ import (
 	"testing"
 
 	"github.com/gofiber/fiber/v2"
+
+	"rental-property-mgmt/tests/testutil"
 )
 
-// setupIntegrationApp creates a test app with real database for integration tests
-// This will fail until we implement the actual app and database setup
+// env is the database shared by the integration tests, opened in TestMain;
+// each test starts from empty tables
+var env *testutil.Env
+
+// setupIntegrationApp returns the app on the shared integration database
 func setupIntegrationApp(t *testing.T) *fiber.App {
-	// TODO: This will be implemented in the main implementation phase
-	// For now, this will panic to ensure tests fail during RED phase
-	panic("setupIntegrationApp not implemented - this ensures tests fail in RED phase")
+	if err := env.Reset(); err != nil {
+		t.Fatalf("Failed to clear integration database: %v", err)
+	}
+	return env.App
 }
 
-// cleanupIntegrationApp cleans up test data and closes connections
+// cleanupIntegrationApp deletes the test's data so the next test starts clean
 func cleanupIntegrationApp(t *testing.T, app *fiber.App) {
-	// TODO: Cleanup database connections and test data
-	// For now, this is a no-op since setupIntegrationApp panics
+	if err := env.Reset(); err != nil {
+		t.Errorf("Failed to clean up integration database: %v", err)
+	}
 }
 
 // createTestUser creates a user for integration testing
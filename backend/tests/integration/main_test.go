@@ -0,0 +1,23 @@
+package integration
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"rental-property-mgmt/tests/testutil"
+)
+
+func TestMain(m *testing.M) {
+	var err error
+	env, err = testutil.Open()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Failed to set up integration database:", err)
+		os.Exit(1)
+	}
+	code := m.Run()
+	if err := env.Close(); err != nil {
+		fmt.Fprintln(os.Stderr, "Failed to tear down integration database:", err)
+	}
+	os.Exit(code)
+}
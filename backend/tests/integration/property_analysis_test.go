@@ -3,6 +3,7 @@ package integration
 import (
 	"bytes"
 	"encoding/json"
+	"math"
 	"net/http/httptest"
 	"testing"
 
@@ -10,6 +11,23 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
+// quickstartMetrics works the quickstart property's metrics out by hand from
+// the calculation formulas in data-model.md, independently of the
+// calculation service: $250,000 purchase, $1,200 insurance, $3,600 taxes,
+// a 30-year loan with 20% down and $5,000 closing costs, 5% vacancy and 10%
+// maintenance and 8% management of rent.
+func quickstartMetrics(monthlyRent, interestRate float64) (payment, noi, capRate, cashOnCash float64) {
+	const price = 250000.0
+	annualRent := monthlyRent * 12
+	noi = annualRent - (1200 + 3600 + 0 + annualRent*0.05 + annualRent*0.10 + annualRent*0.08 + 0)
+	capRate = noi / price * 100
+
+	loan, c, n := price*0.8, interestRate/100/12, 360.0
+	payment = loan * (c * math.Pow(1+c, n)) / (math.Pow(1+c, n) - 1)
+	cashOnCash = (noi - payment*12) / (price*0.2 + 5000) * 100
+	return payment, noi, capRate, cashOnCash
+}
+
 func TestPropertyAnalysisIntegration(t *testing.T) {
 	// This integration test covers the full property creation and metric calculation workflow
 	// from quickstart scenario 2: Property Creation and Basic Analysis
@@ -87,22 +105,29 @@ func TestPropertyAnalysisIntegration(t *testing.T) {
 	err = json.NewDecoder(resp.Body).Decode(&metrics)
 	require.NoError(t, err)
 
-	// Verify expected metrics calculations (from quickstart.md expected results)
+	// Verify expected metrics calculations against the data-model.md formulas
+	// and the quickstart.md expected results
+	wantPayment, wantNOI, wantCapRate, wantCoC := quickstartMetrics(2100, 7.5)
+
 	// Monthly Mortgage Payment: ~$1,398
 	monthlyPayment := metrics["monthly_mortgage_payment"].(float64)
-	assert.InDelta(t, 1398, monthlyPayment, 50, "Monthly mortgage payment should be around $1,398")
+	assert.InDelta(t, wantPayment, monthlyPayment, 0.01)
+	assert.InDelta(t, 1398, monthlyPayment, 1, "Monthly mortgage payment should be around $1,398")
 
-	// Net Operating Income: ~$19,392
+	// Net Operating Income: $14,604
 	noi := metrics["net_operating_income"].(float64)
-	assert.InDelta(t, 19392, noi, 500, "NOI should be around $19,392")
+	assert.InDelta(t, wantNOI, noi, 0.01)
+	assert.InDelta(t, 14604, noi, 1, "NOI should be around $14,604")
 
-	// Cap Rate: ~7.76%
+	// Cap Rate: ~5.84%
 	capRate := metrics["cap_rate"].(float64)
-	assert.InDelta(t, 7.76, capRate, 0.5, "Cap rate should be around 7.76%")
+	assert.InDelta(t, wantCapRate, capRate, 0.01)
+	assert.InDelta(t, 5.84, capRate, 0.01, "Cap rate should be around 5.84%")
 
-	// Cash-on-Cash Return: ~15.2%
+	// Cash-on-Cash Return: ~-3.96%
 	cocReturn := metrics["cash_on_cash_return"].(float64)
-	assert.InDelta(t, 15.2, cocReturn, 1.0, "Cash-on-Cash return should be around 15.2%")
+	assert.InDelta(t, wantCoC, cocReturn, 0.01)
+	assert.InDelta(t, -3.96, cocReturn, 0.01, "Cash-on-Cash return should be around -3.96%")
 
 	// Cash to Close: $55,000
 	cashToClose := metrics["cash_to_close"].(float64)
@@ -137,6 +162,12 @@ func TestPropertyAnalysisIntegration(t *testing.T) {
 	// Step 5: Test metric recalculation when property data changes
 	updateData := map[string]interface{}{
 		"intended_rent": 2300, // Increase from 2100 to 2300
+		"financing_terms": map[string]interface{}{
+			"interest_rate":        6.8, // Lower from 7.5 to 6.8
+			"loan_term":            30,
+			"down_payment_percent": 20,
+			"closing_costs":        5000,
+		},
 	}
 
 	jsonPayload, err = json.Marshal(updateData)
@@ -158,6 +189,10 @@ func TestPropertyAnalysisIntegration(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, 200, resp.StatusCode)
 
+	var recalculated map[string]interface{}
+	err = json.NewDecoder(resp.Body).Decode(&recalculated)
+	require.NoError(t, err)
+
 	// Verify updated metrics
 	req = httptest.NewRequest("GET", "/api/v1/properties/"+propertyID+"/metrics", nil)
 	req.Header.Set("Authorization", "Bearer "+token)
@@ -170,13 +205,24 @@ func TestPropertyAnalysisIntegration(t *testing.T) {
 	err = json.NewDecoder(resp.Body).Decode(&updatedMetrics)
 	require.NoError(t, err)
 
-	// With rent increased from 2100 to 2300, Cap Rate should increase to ~8.44%
+	assert.Equal(t, recalculated["cap_rate"], updatedMetrics["cap_rate"], "recalculated metrics are stored")
+	wantPayment, _, wantCapRate, wantCoC = quickstartMetrics(2300, 6.8)
+
+	// At 6.8% the Monthly Mortgage Payment falls to ~$1,304
+	newPayment := updatedMetrics["monthly_mortgage_payment"].(float64)
+	assert.Less(t, newPayment, monthlyPayment, "Payment should fall with a lower interest rate")
+	assert.InDelta(t, wantPayment, newPayment, 0.01)
+	assert.InDelta(t, 1304, newPayment, 1, "New monthly mortgage payment should be around $1,304")
+
+	// With rent increased from 2100 to 2300, Cap Rate should increase to ~6.58%
 	newCapRate := updatedMetrics["cap_rate"].(float64)
 	assert.Greater(t, newCapRate, capRate, "Cap rate should increase with higher rent")
-	assert.InDelta(t, 8.44, newCapRate, 0.5, "New cap rate should be around 8.44%")
+	assert.InDelta(t, wantCapRate, newCapRate, 0.01)
+	assert.InDelta(t, 6.58, newCapRate, 0.01, "New cap rate should be around 6.58%")
 
-	// Cash-on-Cash Return should increase to ~18.1%
+	// Cash-on-Cash Return should increase to ~1.47% with higher rent and a lower payment
 	newCocReturn := updatedMetrics["cash_on_cash_return"].(float64)
-	assert.Greater(t, newCocReturn, cocReturn, "CoC return should increase with higher rent")
-	assert.InDelta(t, 18.1, newCocReturn, 1.0, "New CoC return should be around 18.1%")
+	assert.Greater(t, newCocReturn, cocReturn, "CoC return should increase with higher rent and a lower rate")
+	assert.InDelta(t, wantCoC, newCocReturn, 0.01)
+	assert.InDelta(t, 1.47, newCocReturn, 0.01, "New CoC return should be around 1.47%")
 }
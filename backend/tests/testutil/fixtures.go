@@ -0,0 +1,93 @@
+package testutil
+
+import (
+	"testing"
+
+	"rental-property-mgmt/internal/middleware"
+	"rental-property-mgmt/internal/models"
+	"rental-property-mgmt/internal/services"
+)
+
+// Password is the password of every user the fixtures create
+const Password = "testpass123"
+
+// User registers a user with Password, as the register endpoint would
+func (e *Env) User(t testing.TB, email string) *models.User {
+	t.Helper()
+	user, err := services.NewUserService(e.Store).Register(email, Password, "Test", "User")
+	if err != nil {
+		t.Fatalf("Failed to create user %s: %v", email, err)
+	}
+	return user
+}
+
+// Token returns a bearer token for the user
+func (e *Env) Token(t testing.TB, user *models.User) string {
+	t.Helper()
+	token, err := middleware.GenerateToken(user.ID, user.Email)
+	if err != nil {
+		t.Fatalf("Failed to issue token for %s: %v", user.Email, err)
+	}
+	return token
+}
+
+// Property creates a property owned by owner, with metrics calculated when
+// it has the data. It starts as the quickstart property: a $250,000 purchase
+// renting for $2,100 a month, financed at 7.5% over 30 years with 20% down.
+// Build functions adjust it before it is saved; JSON numbers are float64,
+// as when decoded from a request.
+func (e *Env) Property(t testing.TB, owner *models.User, build ...func(*models.Property)) *models.Property {
+	t.Helper()
+	rent := 2100.0
+	property := &models.Property{
+		Address:       "123 Main St, Anytown, ST 12345",
+		PurchasePrice: 250000,
+		IntendedRent:  &rent,
+		OperatingExpenses: models.JSONB{
+			"insurance":      1200.0,
+			"property_taxes": 3600.0,
+		},
+		FinancingTerms: models.JSONB{
+			"interest_rate":        7.5,
+			"loan_term":            30.0,
+			"down_payment_percent": 20.0,
+			"closing_costs":        5000.0,
+		},
+		OperatingAssumptions: models.JSONB{
+			"vacancy_rate":    0.05,
+			"maintenance_pct": 0.10,
+			"management_pct":  0.08,
+		},
+	}
+	for _, fn := range build {
+		fn(property)
+	}
+	if err := services.NewPropertyService(e.Store).Create(owner.ID, property, nil); err != nil {
+		t.Fatalf("Failed to create property: %v", err)
+	}
+	return property
+}
+
+// Criteria creates an active buying criteria set owned by owner; build
+// functions set its thresholds before it is saved
+func (e *Env) Criteria(t testing.TB, owner *models.User, build ...func(*models.BuyingBoxCriteria)) *models.BuyingBoxCriteria {
+	t.Helper()
+	criteria := &models.BuyingBoxCriteria{Name: "Test criteria", IsActive: true}
+	for _, fn := range build {
+		fn(criteria)
+	}
+	if err := services.NewBuyingCriteriaService(e.Store).Create(owner.ID, criteria); err != nil {
+		t.Fatalf("Failed to create buying criteria: %v", err)
+	}
+	return criteria
+}
+
+// Comment posts a top-level comment by author on the property
+func (e *Env) Comment(t testing.TB, author *models.User, property *models.Property, content string) *models.Comment {
+	t.Helper()
+	comment, err := services.NewCommentService(e.Store).Create(author.ID, property.ID, content, nil)
+	if err != nil {
+		t.Fatalf("Failed to create comment: %v", err)
+	}
+	return comment
+}
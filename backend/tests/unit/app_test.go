@@ -0,0 +1,104 @@
+package unit
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"rental-property-mgmt/internal/app"
+	"rental-property-mgmt/internal/models"
+	"rental-property-mgmt/internal/repository"
+)
+
+// memoryUsers is an in-memory UserRepository
+type memoryUsers struct {
+	users []models.User
+}
+
+func (m *memoryUsers) Create(user *models.User) error {
+	user.ID = uuid.New()
+	m.users = append(m.users, *user)
+	return nil
+}
+
+func (m *memoryUsers) Get(id uuid.UUID) (*models.User, error) {
+	for i := range m.users {
+		if m.users[i].ID == id {
+			return &m.users[i], nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+
+func (m *memoryUsers) FindByEmail(email string) (*models.User, error) {
+	for i := range m.users {
+		if m.users[i].Email == email {
+			return &m.users[i], nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+
+func (m *memoryUsers) EmailTaken(email string) (bool, error) {
+	_, err := m.FindByEmail(email)
+	return err == nil, nil
+}
+
+func (m *memoryUsers) ListByIDs(ids []uuid.UUID) ([]models.User, error) {
+	var users []models.User
+	for _, id := range ids {
+		if user, err := m.Get(id); err == nil {
+			users = append(users, *user)
+		}
+	}
+	return users, nil
+}
+
+func (m *memoryUsers) MatchHandle(handle string, limit int) ([]models.User, error) {
+	var users []models.User
+	for _, user := range m.users {
+		if len(users) < limit && (user.Email == handle || strings.HasPrefix(user.Email, handle+"@")) {
+			users = append(users, user)
+		}
+	}
+	return users, nil
+}
+
+func TestAppRunsOnInjectedRepositories(t *testing.T) {
+	users := &memoryUsers{}
+	server := app.New(&repository.Store{Users: users})
+
+	post := func(path, body string) int {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := server.Test(req)
+		require.NoError(t, err)
+		return resp.StatusCode
+	}
+
+	registration := `{"email":"Ana@Example.com","password":"password123","first_name":"Ana","last_name":"Lee"}`
+	assert.Equal(t, http.StatusCreated, post("/api/v1/auth/register", registration))
+	assert.Equal(t, http.StatusConflict, post("/api/v1/auth/register", registration))
+	require.Len(t, users.users, 1)
+	assert.Equal(t, "ana@example.com", users.users[0].Email)
+
+	assert.Equal(t, http.StatusOK, post("/api/v1/auth/login", `{"email":"ana@example.com","password":"password123"}`))
+	assert.Equal(t, http.StatusUnauthorized, post("/api/v1/auth/login", `{"email":"ana@example.com","password":"wrong-password"}`))
+}
+
+func TestStoreWithoutDatabaseRunsTransactionsInPlace(t *testing.T) {
+	store := &repository.Store{Users: &memoryUsers{}}
+
+	var inner *repository.Store
+	err := store.Transaction(func(tx *repository.Store) error {
+		inner = tx
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Same(t, store, inner)
+}
@@ -0,0 +1,29 @@
+package unit
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"rental-property-mgmt/internal/middleware"
+)
+
+func TestJWTSecretRequiredOutsideDevelopment(t *testing.T) {
+	t.Setenv("JWT_SECRET", "")
+
+	t.Setenv("ENV", "production")
+	assert.ErrorIs(t, middleware.CheckJWTSecret(), middleware.ErrNoJWTSecret)
+	_, err := middleware.GenerateToken(uuid.New(), "ana@example.com")
+	assert.ErrorIs(t, err, middleware.ErrNoJWTSecret, "no token is signed with the development key")
+
+	t.Setenv("ENV", "development")
+	require.NoError(t, middleware.CheckJWTSecret())
+	_, err = middleware.GenerateToken(uuid.New(), "ana@example.com")
+	assert.NoError(t, err)
+
+	t.Setenv("ENV", "production")
+	t.Setenv("JWT_SECRET", "a-production-secret")
+	assert.NoError(t, middleware.CheckJWTSecret())
+}
@@ -0,0 +1,254 @@
+package unit
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"rental-property-mgmt/internal/models"
+)
+
+func float64Ptr(v float64) *float64 { return &v }
+
+func intPtr(v int) *int { return &v }
+
+func stringPtr(v string) *string { return &v }
+
+func TestCompareProperty_WeightedPartialCredit(t *testing.T) {
+	criteria := &models.BuyingBoxCriteria{
+		MinCapRate:       float64Ptr(8),
+		MaxPurchasePrice: float64Ptr(300000),
+		ScoringRules: models.CriterionRules{
+			"cap_rate":       {Weight: float64Ptr(3)},
+			"purchase_price": {Weight: float64Ptr(1)},
+		},
+	}
+	property := &models.Property{PurchasePrice: 250000}
+	metrics := &models.FinancialMetrics{CapRate: float64Ptr(7)}
+
+	comparison := criteria.CompareProperty(property, metrics)
+
+	// Cap rate misses by 1 point with the default 2-point tolerance: 50% credit, weight 3
+	// Purchase price is met: full credit, weight 1
+	assert.InDelta(t, (1.5+1)/4*100, comparison.Score, 0.001)
+	assert.False(t, comparison.HardFailure)
+	assert.False(t, comparison.Matches["cap_rate"])
+	assert.True(t, comparison.Matches["purchase_price"])
+	require.Len(t, comparison.Contributions, 2)
+	assert.InDelta(t, 0.5, comparison.Contributions[0].Credit, 0.001)
+	assert.NotEmpty(t, comparison.Contributions[0].Explanation)
+}
+
+func TestCompareProperty_SmallMissScoresAboveLargeMiss(t *testing.T) {
+	criteria := &models.BuyingBoxCriteria{
+		MinCapRate:       float64Ptr(8),
+		MaxPurchasePrice: float64Ptr(300000),
+	}
+
+	smallMiss := criteria.CompareProperty(
+		&models.Property{PurchasePrice: 250000},
+		&models.FinancialMetrics{CapRate: float64Ptr(7.9)},
+	)
+	largeMiss := criteria.CompareProperty(
+		&models.Property{PurchasePrice: 500000},
+		&models.FinancialMetrics{CapRate: float64Ptr(9)},
+	)
+
+	assert.Greater(t, smallMiss.Score, largeMiss.Score)
+	assert.InDelta(t, 50, largeMiss.Score, 0.001, "price far beyond tolerance earns no credit")
+}
+
+func TestCompareProperty_HardFailureZeroesScore(t *testing.T) {
+	criteria := &models.BuyingBoxCriteria{
+		MinCapRate:   float64Ptr(6),
+		MinYearBuilt: intPtr(1980),
+		ScoringRules: models.CriterionRules{
+			"min_year_built": {Hard: true},
+		},
+	}
+	property := &models.Property{PurchasePrice: 200000, YearBuilt: intPtr(1979)}
+	metrics := &models.FinancialMetrics{CapRate: float64Ptr(9)}
+
+	comparison := criteria.CompareProperty(property, metrics)
+
+	assert.True(t, comparison.HardFailure)
+	assert.Equal(t, 0.0, comparison.Score)
+	assert.Contains(t, comparison.FailureReasons, "Property too old")
+}
+
+func TestCompareProperty_CustomTolerance(t *testing.T) {
+	criteria := &models.BuyingBoxCriteria{
+		MaxPurchasePrice: float64Ptr(200000),
+		ScoringRules: models.CriterionRules{
+			"purchase_price": {Tolerance: float64Ptr(40000)},
+		},
+	}
+
+	comparison := criteria.CompareProperty(&models.Property{PurchasePrice: 210000}, nil)
+
+	assert.InDelta(t, 75, comparison.Score, 0.001)
+}
+
+func TestCompareProperty_LocationPreferences(t *testing.T) {
+	criteria := &models.BuyingBoxCriteria{
+		LocationPreferences: models.LocationPreferences{
+			States:        []string{"TX"},
+			ExcludeCities: []string{"Houston"},
+		},
+	}
+
+	inArea := &models.Property{Address: "1 Main St, Austin, tx 78701"}
+	inArea.FillAddressComponents()
+	excluded := &models.Property{Address: "2 Main St, Houston, TX 77002"}
+	excluded.FillAddressComponents()
+	outOfState := &models.Property{Address: "3 Main St, Tulsa, OK 74103"}
+	outOfState.FillAddressComponents()
+
+	assert.True(t, criteria.CompareProperty(inArea, nil).Matches["location"])
+	assert.False(t, criteria.CompareProperty(excluded, nil).Matches["location"])
+	assert.False(t, criteria.CompareProperty(outOfState, nil).Matches["location"])
+	assert.Equal(t, 0.0, criteria.CompareProperty(outOfState, nil).Score)
+}
+
+func TestCompareProperty_LocationRadius(t *testing.T) {
+	criteria := &models.BuyingBoxCriteria{
+		LocationPreferences: models.LocationPreferences{
+			// Downtown Austin
+			Radius: &models.RadiusPreference{Latitude: 30.2672, Longitude: -97.7431, Miles: 25},
+		},
+	}
+
+	// Round Rock is ~18 miles away, San Antonio ~75 miles away
+	roundRock := &models.Property{Latitude: float64Ptr(30.5083), Longitude: float64Ptr(-97.6789)}
+	sanAntonio := &models.Property{Latitude: float64Ptr(29.4241), Longitude: float64Ptr(-98.4936)}
+
+	assert.True(t, criteria.CompareProperty(roundRock, nil).Matches["location_radius"])
+	assert.False(t, criteria.CompareProperty(sanAntonio, nil).Matches["location_radius"])
+}
+
+func TestCompareProperty_PropertyTypePreferences(t *testing.T) {
+	criteria := &models.BuyingBoxCriteria{
+		PropertyTypePreferences: models.PropertyTypePreferences{
+			Types: []string{models.PropertyTypeSFR, models.PropertyTypeDuplex},
+		},
+		ScoringRules: models.CriterionRules{
+			"property_type": {Hard: true},
+		},
+	}
+
+	duplex := &models.Property{PropertyType: stringPtr(models.PropertyTypeDuplex)}
+	condo := &models.Property{PropertyType: stringPtr(models.PropertyTypeCondo)}
+
+	assert.Equal(t, 100.0, criteria.CompareProperty(duplex, nil).Score)
+	comparison := criteria.CompareProperty(condo, nil)
+	assert.True(t, comparison.HardFailure)
+	assert.Contains(t, comparison.FailureReasons, "Property type not preferred")
+}
+
+func TestCompareProperty_MissingDataIsUnknown(t *testing.T) {
+	criteria := &models.BuyingBoxCriteria{
+		MinCapRate:       float64Ptr(6),
+		MaxPurchasePrice: float64Ptr(300000),
+		MinYearBuilt:     intPtr(1980),
+	}
+	property := &models.Property{PurchasePrice: 250000}
+
+	comparison := criteria.CompareProperty(property, nil)
+
+	assert.Equal(t, models.MissingDataFlag, comparison.MissingDataPolicy)
+	assert.ElementsMatch(t, []string{"cap_rate", "min_year_built"}, comparison.UnknownCriteria)
+	assert.NotContains(t, comparison.Matches, "cap_rate")
+	assert.InDelta(t, 100.0/3, comparison.Completeness, 0.001)
+	require.Len(t, comparison.Contributions, 3)
+	assert.Equal(t, models.OutcomeUnknown, comparison.Contributions[0].Outcome)
+	assert.Equal(t, models.OutcomeMet, comparison.Contributions[1].Outcome)
+
+	// The only known criterion is met, but the deal is incomplete
+	assert.Equal(t, 100.0, comparison.Score)
+	assert.True(t, comparison.NeedsReview)
+	assert.False(t, comparison.MeetsCriteria)
+	assert.Contains(t, comparison.FailureReasons, "Missing financial metrics")
+}
+
+func TestCompareProperty_MissingDataPolicies(t *testing.T) {
+	property := &models.Property{PurchasePrice: 250000}
+	metrics := &models.FinancialMetrics{CapRate: float64Ptr(7)}
+	newCriteria := func(policy string) *models.BuyingBoxCriteria {
+		return &models.BuyingBoxCriteria{
+			MinCapRate:        float64Ptr(6),
+			MinCashOnCash:     float64Ptr(8),
+			MissingDataPolicy: policy,
+		}
+	}
+
+	fail := newCriteria(models.MissingDataFail).CompareProperty(property, metrics)
+	assert.InDelta(t, 50, fail.Score, 0.001, "unknown cash-on-cash earns no credit")
+	assert.False(t, fail.NeedsReview)
+	assert.False(t, fail.MeetsCriteria)
+
+	ignore := newCriteria(models.MissingDataIgnore).CompareProperty(property, metrics)
+	assert.Equal(t, 100.0, ignore.Score)
+	assert.False(t, ignore.NeedsReview)
+	assert.Empty(t, ignore.FailureReasons)
+	assert.False(t, ignore.MeetsCriteria, "incomplete deals never meet criteria outright")
+	assert.Equal(t, 50.0, ignore.Completeness)
+
+	flag := newCriteria(models.MissingDataFlag).CompareProperty(property, metrics)
+	assert.Equal(t, 100.0, flag.Score)
+	assert.True(t, flag.NeedsReview)
+	assert.False(t, flag.MeetsCriteria)
+}
+
+func TestCompareProperty_UnknownHardCriterionUnderFailPolicy(t *testing.T) {
+	criteria := &models.BuyingBoxCriteria{
+		MaxPurchasePrice:  float64Ptr(300000),
+		MissingDataPolicy: models.MissingDataFail,
+		PropertyTypePreferences: models.PropertyTypePreferences{
+			Types: []string{models.PropertyTypeSFR},
+		},
+		ScoringRules: models.CriterionRules{
+			"property_type": {Hard: true},
+		},
+	}
+
+	comparison := criteria.CompareProperty(&models.Property{PurchasePrice: 200000}, nil)
+
+	assert.True(t, comparison.HardFailure)
+	assert.Equal(t, 0.0, comparison.Score)
+	assert.Equal(t, []string{"property_type"}, comparison.UnknownCriteria)
+}
+
+func TestCompareProperty_NothingVerifiableScoresZero(t *testing.T) {
+	criteria := &models.BuyingBoxCriteria{MinCapRate: float64Ptr(6)}
+
+	comparison := criteria.CompareProperty(&models.Property{PurchasePrice: 200000}, nil)
+
+	assert.Equal(t, 0.0, comparison.Score)
+	assert.Equal(t, 0.0, comparison.Completeness)
+	assert.False(t, comparison.MeetsCriteria)
+}
+
+func TestCompareProperty_CompleteMatch(t *testing.T) {
+	criteria := &models.BuyingBoxCriteria{MinCapRate: float64Ptr(6), MaxPurchasePrice: float64Ptr(300000)}
+
+	comparison := criteria.CompareProperty(
+		&models.Property{PurchasePrice: 200000},
+		&models.FinancialMetrics{CapRate: float64Ptr(7)},
+	)
+
+	assert.Equal(t, 100.0, comparison.Score)
+	assert.Equal(t, 100.0, comparison.Completeness)
+	assert.True(t, comparison.MeetsCriteria)
+	assert.Empty(t, comparison.UnknownCriteria)
+}
+
+func TestFillAddressComponents(t *testing.T) {
+	property := &models.Property{Address: "123 Main St, Anytown, ST 12345-6789"}
+	property.FillAddressComponents()
+
+	require.NotNil(t, property.City)
+	assert.Equal(t, "Anytown", *property.City)
+	assert.Equal(t, "ST", *property.State)
+	assert.Equal(t, "12345", *property.ZipCode)
+}
@@ -0,0 +1,68 @@
+package unit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"rental-property-mgmt/internal/models"
+)
+
+func TestBuildCommentTrees(t *testing.T) {
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	newComment := func(content string, minute int, parent *models.Comment) models.Comment {
+		comment := models.Comment{ID: uuid.New(), Content: content, CreatedAt: start.Add(time.Duration(minute) * time.Minute)}
+		comment.ThreadID = comment.ID
+		if parent != nil {
+			comment.ReplyTo(parent)
+		}
+		return comment
+	}
+
+	first := newComment("first thread", 0, nil)
+	second := newComment("second thread", 5, nil)
+	lateReply := newComment("late reply", 9, &first)
+	earlyReply := newComment("early reply", 1, &first)
+	nested := newComment("nested", 2, &earlyReply)
+	tooDeep := newComment("too deep", 3, &nested)
+
+	// Deliberately out of order
+	trees := models.BuildCommentTrees([]models.Comment{tooDeep, second, lateReply, nested, first, earlyReply}, 2)
+
+	require.Len(t, trees, 2)
+	assert.Equal(t, "first thread", trees[0].Content)
+	assert.Equal(t, "second thread", trees[1].Content)
+	assert.Equal(t, 0, trees[1].ReplyCount)
+	assert.NotNil(t, trees[1].Replies)
+
+	replies := trees[0].Replies
+	require.Len(t, replies, 2)
+	assert.Equal(t, 2, trees[0].ReplyCount)
+	assert.Equal(t, "early reply", replies[0].Content)
+	assert.Equal(t, "late reply", replies[1].Content)
+
+	require.Len(t, replies[0].Replies, 1)
+	deepest := replies[0].Replies[0]
+	assert.Equal(t, "nested", deepest.Content)
+	assert.Equal(t, 2, deepest.Depth)
+	assert.Equal(t, first.ID, deepest.ThreadID)
+	assert.Equal(t, 1, deepest.ReplyCount, "replies beyond the depth limit are still counted")
+	assert.Empty(t, deepest.Replies)
+}
+
+func TestNewCommentRevision(t *testing.T) {
+	created := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+	comment := &models.Comment{ID: uuid.New(), Content: "First draft", CreatedAt: created}
+
+	revision := models.NewCommentRevision(comment)
+	assert.Equal(t, comment.ID, revision.CommentID)
+	assert.Equal(t, "First draft", revision.Content)
+	assert.Equal(t, created, revision.WrittenAt, "never-edited comments were written at creation")
+
+	edited := created.Add(time.Hour)
+	comment.UpdatedAt = edited
+	assert.Equal(t, edited, models.NewCommentRevision(comment).WrittenAt)
+}
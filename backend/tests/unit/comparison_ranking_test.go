@@ -0,0 +1,75 @@
+package unit
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"rental-property-mgmt/internal/models"
+	"rental-property-mgmt/internal/services"
+)
+
+func TestRankComparisons_SortsAndPaginates(t *testing.T) {
+	criteria := &models.BuyingBoxCriteria{
+		MinCapRate:       float64Ptr(6),
+		MaxPurchasePrice: float64Ptr(300000),
+	}
+
+	compare := func(address string, price float64, capRate *float64) *models.PropertyComparison {
+		var metrics *models.FinancialMetrics
+		if capRate != nil {
+			metrics = &models.FinancialMetrics{CapRate: capRate}
+		}
+		return criteria.CompareProperty(&models.Property{Address: address, PurchasePrice: price}, metrics)
+	}
+
+	comparisons := []*models.PropertyComparison{
+		compare("4 Over Budget", 400000, float64Ptr(7)),
+		compare("1 Perfect", 250000, float64Ptr(8)),
+		compare("3 Unknown Metrics", 250000, nil),
+		compare("2 Low Cap", 250000, float64Ptr(5)),
+	}
+
+	ranking := services.RankComparisons(comparisons, 2, 0)
+
+	assert.Equal(t, 4, ranking.Total)
+	require.Len(t, ranking.Results, 2)
+	// Both score 100, but only the complete comparison meets the criteria
+	assert.Equal(t, "1 Perfect", ranking.Results[0].Property.Address)
+	assert.Equal(t, "3 Unknown Metrics", ranking.Results[1].Property.Address)
+
+	page := services.RankComparisons(comparisons, 2, 2)
+	require.Len(t, page.Results, 2)
+	assert.Equal(t, "2 Low Cap", page.Results[0].Property.Address)
+	assert.Equal(t, "4 Over Budget", page.Results[1].Property.Address)
+
+	assert.Empty(t, services.RankComparisons(comparisons, 2, 10).Results)
+}
+
+func TestRankComparisons_FailureSummary(t *testing.T) {
+	criteria := &models.BuyingBoxCriteria{
+		MinCapRate:       float64Ptr(6),
+		MaxPurchasePrice: float64Ptr(300000),
+	}
+
+	comparisons := []*models.PropertyComparison{
+		criteria.CompareProperty(&models.Property{PurchasePrice: 350000}, &models.FinancialMetrics{CapRate: float64Ptr(5)}),
+		criteria.CompareProperty(&models.Property{PurchasePrice: 320000}, &models.FinancialMetrics{CapRate: float64Ptr(7)}),
+		criteria.CompareProperty(&models.Property{PurchasePrice: 200000}, nil),
+		criteria.CompareProperty(&models.Property{PurchasePrice: 200000}, &models.FinancialMetrics{CapRate: float64Ptr(9)}),
+	}
+
+	summary := services.RankComparisons(comparisons, 20, 0).Summary
+
+	assert.Equal(t, 4, summary.Evaluated)
+	assert.Equal(t, 1, summary.MeetsCriteria)
+	assert.Equal(t, 1, summary.NeedsReview)
+	require.Len(t, summary.FailingCriteria, 2)
+	assert.Equal(t, "purchase_price", summary.FailingCriteria[0].Criterion)
+	assert.Equal(t, 2, summary.FailingCriteria[0].Failures)
+	assert.InDelta(t, 50, summary.FailingCriteria[0].FailureRate, 0.001)
+	assert.Equal(t, "cap_rate", summary.FailingCriteria[1].Criterion)
+	assert.Equal(t, 1, summary.FailingCriteria[1].Failures)
+	assert.Equal(t, 1, summary.FailingCriteria[1].Unknown)
+}
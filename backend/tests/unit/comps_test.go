@@ -0,0 +1,97 @@
+package unit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"rental-property-mgmt/internal/models"
+)
+
+func TestAdjustComps(t *testing.T) {
+	asOf := time.Date(2024, 6, 30, 0, 0, 0, 0, time.UTC)
+	subject := &models.Property{
+		BuildingAreaSqft: intPtr(1500),
+		YearBuilt:        intPtr(2000),
+		Bedrooms:         intPtr(3),
+		Bathrooms:        float64Ptr(2),
+	}
+	comps := []models.Comp{
+		{
+			Address: "1 Smaller St", SalePrice: 300000, SaleDate: asOf,
+			BuildingAreaSqft: intPtr(1400), YearBuilt: intPtr(1990), Bedrooms: intPtr(2), Bathrooms: float64Ptr(2),
+		},
+		{
+			Address: "2 Older Sale Ave", SalePrice: 250000, SaleDate: asOf.Add(-730*24*time.Hour - 12*time.Hour),
+			Bedrooms: intPtr(3), Bathrooms: float64Ptr(2.5),
+		},
+		{Address: "3 Twin Ct", SalePrice: 280000, SaleDate: asOf, Bedrooms: intPtr(3), Bathrooms: float64Ptr(2)},
+	}
+
+	valuation := models.AdjustComps(subject, comps, models.DefaultCompAdjustments(), asOf)
+	require.Len(t, valuation.Comps, 3)
+	assert.Equal(t, 3, valuation.Count)
+
+	smaller := valuation.Comps[0]
+	require.Len(t, smaller.Adjustments, 5)
+	assert.Equal(t, models.CompFeatureTime, smaller.Adjustments[0].Feature)
+	assert.Equal(t, 0.0, smaller.Adjustments[0].Amount)
+	assert.Equal(t, 21428.57, smaller.Adjustments[1].Amount, "size is priced at the comp's own price per sqft")
+	assert.Equal(t, 5000.0, smaller.Adjustments[2].Amount, "ten years newer")
+	assert.Equal(t, 5000.0, smaller.Adjustments[3].Amount, "one more bedroom")
+	assert.Equal(t, 0.0, smaller.Adjustments[4].Amount)
+	assert.Equal(t, 331428.57, smaller.AdjustedValue)
+	assert.Equal(t, 10.48, smaller.NetAdjustmentPercent)
+
+	older := valuation.Comps[1]
+	assert.Equal(t, 15225.0, older.Adjustments[0].Amount, "two years at 3% compounded")
+	assert.Equal(t, "comp square footage unknown", older.Adjustments[1].Note)
+	assert.Equal(t, "comp year built unknown", older.Adjustments[2].Note)
+	assert.Equal(t, -3750.0, older.Adjustments[4].Amount, "half a bathroom fewer")
+	assert.Equal(t, 261475.0, older.AdjustedValue)
+	assert.Equal(t, 4.59, older.NetAdjustmentPercent)
+	assert.Equal(t, 7.59, older.GrossAdjustmentPercent)
+
+	assert.Equal(t, 280000.0, valuation.Comps[2].AdjustedValue)
+	assert.Equal(t, 261475.0, valuation.Low)
+	assert.Equal(t, 331428.57, valuation.High)
+	assert.Equal(t, 280000.0, valuation.Median)
+	assert.Equal(t, 290967.86, valuation.Mean)
+
+	stored, err := valuation.ToValuation()
+	require.NoError(t, err)
+	assert.Equal(t, models.ValuationSourceComps, stored.Source)
+	assert.Equal(t, models.ValuationTypeMarketValue, stored.ValuationType)
+	assert.Equal(t, 280000.0, stored.Value)
+	assert.Equal(t, 261475.0, *stored.ValueLow)
+	assert.Equal(t, 331428.57, *stored.ValueHigh)
+	assert.Equal(t, asOf, stored.ValuationDate)
+
+	// A flat size rate overrides the comp's own price per sqft
+	settings := models.DefaultCompAdjustments()
+	settings.PricePerSqft = float64Ptr(100)
+	adjusted := models.AdjustComp(subject, comps[0], settings, asOf)
+	assert.Equal(t, 10000.0, adjusted.Adjustments[1].Amount)
+
+	_, err = models.AdjustComps(subject, nil, settings, asOf).ToValuation()
+	assert.Error(t, err)
+}
+
+func TestSortCompsByDistance(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2024, 6, d, 0, 0, 0, 0, time.UTC) }
+	comps := []models.Comp{
+		{Address: "unknown", SaleDate: day(1)},
+		{Address: "far", DistanceMiles: float64Ptr(2), SaleDate: day(1)},
+		{Address: "near old", DistanceMiles: float64Ptr(0.5), SaleDate: day(1)},
+		{Address: "near recent", DistanceMiles: float64Ptr(0.5), SaleDate: day(20)},
+	}
+
+	models.SortCompsByDistance(comps)
+	var order []string
+	for _, comp := range comps {
+		order = append(order, comp.Address)
+	}
+	assert.Equal(t, []string{"near recent", "near old", "far", "unknown"}, order)
+}
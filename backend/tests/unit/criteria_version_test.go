@@ -0,0 +1,55 @@
+package unit
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"rental-property-mgmt/internal/models"
+)
+
+func TestSameDefinition_IgnoresActivationAndMetadata(t *testing.T) {
+	original := &models.BuyingBoxCriteria{
+		ID:               uuid.New(),
+		Name:             "Cash flow",
+		MinCapRate:       float64Ptr(8),
+		IsActive:         true,
+		Version:          3,
+		ScoringRules:     models.CriterionRules{},
+		MaxPurchasePrice: float64Ptr(300000),
+	}
+	toggled := *original
+	toggled.Name = "Cash flow under 300k"
+	toggled.IsActive = false
+	toggled.Version = 4
+	toggled.ScoringRules = nil
+	toggled.MinCapRate = float64Ptr(8)
+
+	assert.True(t, original.SameDefinition(&toggled))
+
+	edited := *original
+	edited.MinCapRate = float64Ptr(7.5)
+	assert.False(t, original.SameDefinition(&edited))
+}
+
+func TestNewCriteriaVersion_SnapshotsDefinition(t *testing.T) {
+	criteria := &models.BuyingBoxCriteria{
+		ID:         uuid.New(),
+		Name:       "Cash flow",
+		MinCapRate: float64Ptr(8),
+		Version:    2,
+	}
+	userID := uuid.New()
+
+	version := models.NewCriteriaVersion(criteria, userID)
+
+	assert.Equal(t, criteria.ID, version.CriteriaID)
+	assert.Equal(t, 2, version.Version)
+	assert.Equal(t, userID, version.CreatedBy)
+	assert.Equal(t, 8.0, *version.Definition.MinCapRate)
+	assert.Equal(t, models.MissingDataFlag, version.Definition.MissingDataPolicy)
+
+	comparison := criteria.CompareProperty(&models.Property{PurchasePrice: 100000}, nil)
+	assert.Equal(t, 2, comparison.CriteriaVersion)
+}
@@ -0,0 +1,81 @@
+package unit
+
+import (
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"rental-property-mgmt/internal/models"
+	"rental-property-mgmt/pkg/markdown"
+)
+
+func TestMarkdownToHTML(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		want   string
+	}{
+		{"emphasis", "**Roof** is _old_", "<p><strong>Roof</strong> is <em>old</em></p>"},
+		{"code", "Check `cap_rate`", "<p>Check <code>cap_rate</code></p>"},
+		{"list", "- roof\n- hvac", "<ul>\n<li>roof</li>\n<li>hvac</li>\n</ul>"},
+		{
+			"link",
+			"[listing](https://example.com/listing)",
+			`<p><a href="https://example.com/listing" rel="nofollow noreferrer noopener" target="_blank">listing</a></p>`,
+		},
+		{"raw HTML is dropped", `<img src=x onerror="alert(1)">hi`, "<p>hi</p>"},
+		{"HTML blocks are dropped", "<script>alert(1)</script>", ""},
+		{"unsafe link scheme", "[click](javascript:alert(1))", "<p>click</p>"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, markdown.ToHTML(tt.source))
+		})
+	}
+}
+
+func TestMarkdownPlainText(t *testing.T) {
+	assert.Equal(t, "Roof is old see listing", markdown.PlainText("**Roof** is _old_\n\nsee [listing](https://example.com)"))
+	assert.Equal(t, "one two", markdown.PlainText("- one\n- two"))
+}
+
+func TestMarkdownPreviewIsRuneSafe(t *testing.T) {
+	preview := markdown.Preview("Café près de la gare, très calme", 12)
+	assert.True(t, utf8.ValidString(preview))
+	assert.Equal(t, "Café près de...", preview)
+
+	assert.Equal(t, "日本語の...", markdown.Preview("日本語のコメントです", 4))
+	assert.Equal(t, "short", markdown.Preview("*short*", 10))
+}
+
+func TestCommentContentPreview(t *testing.T) {
+	comment := &models.Comment{Content: "**Über** cool ünïcode comment"}
+	preview := comment.GetContentPreview(10)
+	assert.True(t, utf8.ValidString(preview))
+	assert.Equal(t, "Über cool...", preview)
+}
+
+func TestCommentLinkedPropertyIDs(t *testing.T) {
+	first, second := uuid.New(), uuid.New()
+	comment := &models.Comment{Content: "Compare with [this one](/properties/" + first.String() + ") and " +
+		"https://app.example.com/api/v1/properties/" + second.String() + "?tab=metrics, or again " +
+		"[the first](https://app.example.com/properties/" + first.String() + "). " +
+		"Not a property: https://example.com/properties/" + second.String() + "/comments"}
+
+	assert.Equal(t, []uuid.UUID{first, second}, comment.LinkedPropertyIDs())
+
+	comment.RenderContent()
+	assert.Contains(t, comment.ContentHTML, `href="/properties/`+first.String()+`"`)
+	assert.Empty(t, comment.PropertyCards)
+}
+
+func TestDeletedCommentRendersPlaceholder(t *testing.T) {
+	comment := &models.Comment{Content: models.DeletedCommentPlaceholder, DeletedAt: &[]time.Time{time.Now()}[0]}
+	comment.RenderContent()
+	assert.Equal(t, "<p>[deleted]</p>", comment.ContentHTML)
+	assert.Nil(t, comment.LinkedPropertyIDs())
+}
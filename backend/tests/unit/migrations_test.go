@@ -0,0 +1,241 @@
+package unit
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"rental-property-mgmt/internal/models"
+	"rental-property-mgmt/internal/repository"
+	"rental-property-mgmt/internal/services"
+	"rental-property-mgmt/pkg/database"
+	"rental-property-mgmt/tests/testutil"
+)
+
+var migrationDrivers = []string{database.DriverPostgres, database.DriverSQLite}
+
+var schemaTables = []string{
+	"users", "assumption_profiles", "properties", "property_valuations",
+	"financial_metrics", "comments", "comment_revisions", "buying_box_criteria",
+	"buying_box_criteria_versions", "scenarios", "criteria_matches",
+	"comment_mentions", "notifications", "comment_reactions", "property_votes",
+	"comps", "rent_comps",
+}
+
+// autoMigratedTables are the tables AutoMigrate created before migrations
+// were versioned, which the PostgreSQL baseline migration holds
+var autoMigratedTables = []string{
+	"users", "properties", "property_valuations", "financial_metrics",
+	"comments", "buying_box_criteria",
+}
+
+func TestMigrationsAreNumberedInOrder(t *testing.T) {
+	for _, driver := range migrationDrivers {
+		migrations, err := database.Migrations(driver)
+		require.NoError(t, err, driver)
+		require.NotEmpty(t, migrations, driver)
+
+		for i, migration := range migrations {
+			assert.Equal(t, i+1, migration.Version, "%s migration versions have no gaps", driver)
+			assert.NotEmpty(t, strings.TrimSpace(migration.Up), "%s %d_%s up", driver, migration.Version, migration.Name)
+			assert.NotEmpty(t, strings.TrimSpace(migration.Down), "%s %d_%s down", driver, migration.Version, migration.Name)
+		}
+	}
+}
+
+func TestMigrationsCreateEveryTable(t *testing.T) {
+	for _, driver := range migrationDrivers {
+		migrations, err := database.Migrations(driver)
+		require.NoError(t, err, driver)
+
+		for _, table := range schemaTables {
+			created := false
+			for _, migration := range migrations {
+				if strings.Contains(migration.Up, "CREATE TABLE "+table+" (") {
+					created = true
+					assert.Contains(t, migration.Down, "DROP TABLE IF EXISTS "+table+";", "%s %d_%s", driver, migration.Version, migration.Name)
+				}
+			}
+			assert.True(t, created, "%s creates %s", driver, table)
+		}
+	}
+}
+
+func TestPostgresBaselineHoldsOnlyAutoMigratedTables(t *testing.T) {
+	migrations, err := database.Migrations(database.DriverPostgres)
+	require.NoError(t, err)
+	require.NotEmpty(t, migrations)
+
+	baseline := migrations[0]
+	for _, table := range autoMigratedTables {
+		assert.Contains(t, baseline.Up, "CREATE TABLE "+table+" (", table)
+	}
+	assert.Equal(t, len(autoMigratedTables), strings.Count(baseline.Up, "CREATE TABLE "))
+}
+
+func TestMigrationsUnknownDriver(t *testing.T) {
+	_, err := database.Migrations("mysql")
+	assert.Error(t, err)
+}
+
+func TestSQLiteMigrationsRunUpAndDown(t *testing.T) {
+	db, err := database.Open(database.Config{Driver: database.DriverSQLite, Path: ":memory:"})
+	require.NoError(t, err)
+	defer database.Close(db)
+
+	applied, err := database.MigrateUp(db)
+	require.NoError(t, err)
+	require.NotEmpty(t, applied)
+	for _, table := range schemaTables {
+		assert.True(t, db.Migrator().HasTable(table), table)
+	}
+
+	require.NoError(t, database.EnsureSchema(db, false), "nothing left to apply")
+
+	rolledBack, err := database.MigrateDown(db, len(applied))
+	require.NoError(t, err)
+	assert.Len(t, rolledBack, len(applied))
+	assert.False(t, db.Migrator().HasTable("users"))
+}
+
+func TestSQLiteUnifiedInboxKeepsAlerts(t *testing.T) {
+	db, err := database.Open(database.Config{Driver: database.DriverSQLite, Path: ":memory:"})
+	require.NoError(t, err)
+	defer database.Close(db)
+	_, err = database.MigrateUp(db)
+	require.NoError(t, err)
+	store := repository.NewGormStore(db)
+
+	user := &models.User{Email: "ana@example.com", PasswordHash: "x", FirstName: "Ana", LastName: "Lee"}
+	require.NoError(t, store.Users.Create(user))
+	maxPrice := 300000.0
+	require.NoError(t, store.Criteria.Create(&models.BuyingBoxCriteria{
+		UserID: user.ID, Name: "Under 300k", IsActive: true, MaxPurchasePrice: &maxPrice,
+	}))
+	property := &models.Property{UserID: user.ID, Address: "12 Maple Street", PurchasePrice: 210000}
+	require.NoError(t, store.Properties.Create(property))
+	matches, err := services.NewAlertService(store).RecordMatches(property)
+	require.NoError(t, err)
+	require.Len(t, matches, 1)
+
+	// Before the inbox was unified, the match held the alert's read state
+	_, err = database.MigrateDown(db, 1)
+	require.NoError(t, err)
+	var inbox int64
+	require.NoError(t, db.Table("notifications").Count(&inbox).Error)
+	assert.Zero(t, inbox, "match notifications are dropped on the way down")
+	require.NoError(t, db.Exec("UPDATE criteria_matches SET read_at = ?", time.Now()).Error)
+
+	_, err = database.MigrateUp(db)
+	require.NoError(t, err)
+	var notification models.Notification
+	require.NoError(t, db.Where("match_id = ?", matches[0].ID).First(&notification).Error)
+	assert.Equal(t, models.NotificationMatch, notification.Type)
+	assert.Equal(t, user.ID, notification.UserID)
+	assert.NotNil(t, notification.ReadAt, "read alerts stay read")
+}
+
+func TestPostgresMigrationsRunUpAndDown(t *testing.T) {
+	db, err := database.Open(testutil.PostgresSchema(t))
+	require.NoError(t, err)
+	defer database.Close(db)
+
+	applied, err := database.MigrateUp(db)
+	require.NoError(t, err)
+	require.NotEmpty(t, applied)
+	for _, table := range schemaTables {
+		assert.True(t, db.Migrator().HasTable(table), table)
+	}
+
+	rolledBack, err := database.MigrateDown(db, len(applied))
+	require.NoError(t, err)
+	assert.Len(t, rolledBack, len(applied))
+	assert.False(t, db.Migrator().HasTable("users"))
+}
+
+// autoMigratedSchema opens a fresh PostgreSQL schema holding the tables
+// AutoMigrate created without any schema_migrations, as it left databases
+func autoMigratedSchema(t *testing.T) *gorm.DB {
+	db, err := database.Open(testutil.PostgresSchema(t))
+	require.NoError(t, err)
+	t.Cleanup(func() { database.Close(db) })
+
+	migrations, err := database.Migrations(database.DriverPostgres)
+	require.NoError(t, err)
+	require.NoError(t, db.Exec(migrations[0].Up).Error, migrations[0].Name)
+	return db
+}
+
+func TestPostgresAdoptsAutoMigratedSchema(t *testing.T) {
+	db := autoMigratedSchema(t)
+
+	applied, err := database.MigrateUp(db)
+	require.NoError(t, err)
+	require.NotEmpty(t, applied)
+	assert.Equal(t, 2, applied[0].Version, "the baseline is adopted, not re-run")
+	for _, table := range schemaTables {
+		assert.True(t, db.Migrator().HasTable(table), table)
+	}
+
+	statuses, err := database.MigrationStatuses(db)
+	require.NoError(t, err)
+	for _, status := range statuses {
+		assert.NotNil(t, status.AppliedAt, "%d_%s", status.Version, status.Name)
+	}
+}
+
+func TestPostgresUpgradesAutoMigratedData(t *testing.T) {
+	db := autoMigratedSchema(t)
+
+	var userID, propertyID, rootID, replyID, nestedID, criteriaID string
+	require.NoError(t, db.Raw(`INSERT INTO users (email, password_hash, first_name, last_name)
+		VALUES ('ana@example.com', 'x', 'Ana', 'Lee') RETURNING id`).Scan(&userID).Error)
+	require.NoError(t, db.Raw(`INSERT INTO properties (user_id, address, purchase_price)
+		VALUES (?, '12 Maple Street', 210000) RETURNING id`, userID).Scan(&propertyID).Error)
+	require.NoError(t, db.Raw(`INSERT INTO comments (property_id, user_id, content)
+		VALUES (?, ?, 'Roof?') RETURNING id`, propertyID, userID).Scan(&rootID).Error)
+	require.NoError(t, db.Raw(`INSERT INTO comments (property_id, user_id, content, parent_id)
+		VALUES (?, ?, 'Inspected last year', ?) RETURNING id`, propertyID, userID, rootID).Scan(&replyID).Error)
+	require.NoError(t, db.Raw(`INSERT INTO comments (property_id, user_id, content, parent_id)
+		VALUES (?, ?, 'Thanks', ?) RETURNING id`, propertyID, userID, replyID).Scan(&nestedID).Error)
+	require.NoError(t, db.Raw(`INSERT INTO buying_box_criteria (user_id, name, max_purchase_price)
+		VALUES (?, 'Under 300k', 300000) RETURNING id`, userID).Scan(&criteriaID).Error)
+
+	_, err := database.MigrateUp(db)
+	require.NoError(t, err)
+	store := repository.NewGormStore(db)
+
+	for id, depth := range map[string]int{rootID: 0, replyID: 1, nestedID: 2} {
+		comment, err := store.Comments.Get(uuid.MustParse(id))
+		require.NoError(t, err)
+		assert.Equal(t, rootID, comment.ThreadID.String(), "replies join the thread of their root")
+		assert.Equal(t, depth, comment.Depth)
+	}
+
+	criteria, err := store.Criteria.GetForUser(uuid.MustParse(userID), uuid.MustParse(criteriaID))
+	require.NoError(t, err)
+	assert.Equal(t, 1, criteria.Version)
+	version, err := store.Criteria.GetVersion(criteria.ID, 1)
+	require.NoError(t, err)
+	assert.Equal(t, criteria.Definition(), version.Definition, "version 1 records the existing definition")
+}
+
+func TestPostgresRefusesIncompleteSchema(t *testing.T) {
+	db := autoMigratedSchema(t)
+	require.NoError(t, db.Exec("ALTER TABLE comments DROP CONSTRAINT chk_comments_content").Error)
+	require.NoError(t, db.Exec("DROP INDEX idx_properties_user_id").Error)
+
+	_, err := database.MigrateUp(db)
+	require.ErrorIs(t, err, database.ErrBaselineMismatch)
+	assert.Contains(t, err.Error(), "constraint chk_comments_content on comments")
+	assert.Contains(t, err.Error(), "index idx_properties_user_id on properties")
+
+	var adopted int64
+	require.NoError(t, db.Table("schema_migrations").Count(&adopted).Error)
+	assert.Zero(t, adopted, "nothing is recorded as applied")
+}
@@ -0,0 +1,191 @@
+package unit
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"rental-property-mgmt/internal/models"
+	"rental-property-mgmt/internal/notifications"
+	"rental-property-mgmt/internal/services"
+)
+
+// smtpStandIn is a minimal local SMTP server that records one message
+type smtpStandIn struct {
+	listener net.Listener
+	from     string
+	to       []string
+	data     string
+	done     chan struct{}
+}
+
+func startSMTPStandIn(t *testing.T) *smtpStandIn {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	server := &smtpStandIn{listener: listener, done: make(chan struct{})}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		defer close(server.done)
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		reader := bufio.NewReader(conn)
+		reply := func(line string) { conn.Write([]byte(line + "\r\n")) }
+		reply("220 localhost ESMTP stand-in")
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				return
+			}
+			command := strings.ToUpper(strings.TrimSpace(line))
+			switch {
+			case strings.HasPrefix(command, "EHLO"), strings.HasPrefix(command, "HELO"):
+				reply("250 localhost")
+			case strings.HasPrefix(command, "MAIL FROM:"):
+				server.from = strings.Trim(strings.TrimSpace(line)[10:], "<>")
+				reply("250 OK")
+			case strings.HasPrefix(command, "RCPT TO:"):
+				server.to = append(server.to, strings.Trim(strings.TrimSpace(line)[8:], "<>"))
+				reply("250 OK")
+			case command == "DATA":
+				reply("354 End data with <CR><LF>.<CR><LF>")
+				var data strings.Builder
+				for {
+					dataLine, err := reader.ReadString('\n')
+					if err != nil || dataLine == ".\r\n" {
+						break
+					}
+					data.WriteString(dataLine)
+				}
+				server.data = data.String()
+				reply("250 OK queued")
+			case command == "QUIT":
+				reply("221 Bye")
+				return
+			default:
+				reply("250 OK")
+			}
+		}
+	}()
+	return server
+}
+
+func TestSMTPNotifier_DeliversToLocalStandIn(t *testing.T) {
+	server := startSMTPStandIn(t)
+	addr := server.listener.Addr().(*net.TCPAddr)
+
+	notifier := &notifications.SMTPNotifier{Host: "127.0.0.1", Port: addr.Port, From: "alerts@example.com"}
+	err := notifier.Send(context.Background(), notifications.Message{
+		To:      []string{"investor@example.com"},
+		Subject: "New property match:\r\nBcc: attacker@example.com",
+		Body:    "123 Main St matches \"Cash flow\".\nScore: 100",
+	})
+	require.NoError(t, err)
+	<-server.done
+
+	assert.Equal(t, "alerts@example.com", server.from)
+	assert.Equal(t, []string{"investor@example.com"}, server.to)
+	assert.Contains(t, server.data, "To: investor@example.com\r\n")
+	assert.Contains(t, server.data, "Subject: New property match:  Bcc: attacker@example.com\r\n", "line breaks cannot inject headers")
+	assert.Contains(t, server.data, "123 Main St matches \"Cash flow\".\r\nScore: 100\r\n")
+}
+
+func TestWebhookNotifier(t *testing.T) {
+	var received map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	err := notifications.NewWebhookNotifier(server.URL).Send(context.Background(), notifications.Message{
+		To:      []string{"investor@example.com"},
+		Subject: "New property match",
+		Event:   services.EventCriteriaMatch,
+		Data:    map[string]interface{}{"score": 92.5},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "criteria.match", received["event"])
+	assert.Equal(t, 92.5, received["data"].(map[string]interface{})["score"])
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer failing.Close()
+	err = notifications.NewWebhookNotifier(failing.URL).Send(context.Background(), notifications.Message{})
+	assert.EqualError(t, err, "webhook returned 502 Bad Gateway")
+}
+
+func TestMatchMessage(t *testing.T) {
+	property := &models.Property{
+		ID:               uuid.New(),
+		Address:          "123 Main St, Austin, TX 78701",
+		PurchasePrice:    250000,
+		FinancialMetrics: &models.FinancialMetrics{CapRate: float64Ptr(8.25)},
+	}
+	match := models.CriteriaMatch{
+		ID:         uuid.New(),
+		PropertyID: property.ID,
+		Score:      100,
+		Criteria:   &models.BuyingBoxCriteria{Name: "Austin cash flow"},
+	}
+
+	msg := services.MatchMessage(property, match, "investor@example.com")
+
+	assert.Equal(t, []string{"investor@example.com"}, msg.To)
+	assert.Equal(t, "New property match: 123 Main St, Austin, TX 78701", msg.Subject)
+	assert.Contains(t, msg.Body, `matches "Austin cash flow"`)
+	assert.Contains(t, msg.Body, "Cap rate: 8.25%")
+	assert.Equal(t, services.EventCriteriaMatch, msg.Event)
+}
+
+func TestParseMentions(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []string
+	}{
+		{"no mentions", "Roof needs work", nil},
+		{"local part", "@jane can you look?", []string{"jane"}},
+		{"full email", "cc @Jane.Doe@Example.com.", []string{"jane.doe@example.com"}},
+		{"distinct in order", "@bob and @amy, then @BOB again", []string{"bob", "amy"}},
+		{"plain email is not a mention", "email jane@example.com", nil},
+		{"after punctuation", "(@amy) thoughts?", []string{"amy"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, models.ParseMentions(tt.content))
+		})
+	}
+}
+
+func TestCommentMessage(t *testing.T) {
+	comment := &models.Comment{ID: uuid.New(), PropertyID: uuid.New(), Content: "Looks good", UserName: "Ann Author"}
+	property := &models.Property{Address: "1 Main St"}
+
+	mention := services.CommentMessage(models.Notification{Type: models.NotificationMention}, comment, property, "tom@example.com")
+	assert.Equal(t, "Ann Author mentioned you on 1 Main St", mention.Subject)
+	assert.Equal(t, []string{"tom@example.com"}, mention.To)
+	assert.Equal(t, services.EventCommentMention, mention.Event)
+	assert.Contains(t, mention.Body, "Looks good")
+
+	reply := services.CommentMessage(models.Notification{Type: models.NotificationReply}, comment, property, "")
+	assert.Equal(t, "Ann Author replied to your comment on 1 Main St", reply.Subject)
+	assert.Empty(t, reply.To)
+	assert.Equal(t, services.EventCommentReply, reply.Event)
+}
@@ -0,0 +1,118 @@
+package unit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"rental-property-mgmt/internal/models"
+)
+
+func TestEstimateRent(t *testing.T) {
+	asOf := time.Date(2024, 6, 30, 0, 0, 0, 0, time.UTC)
+	rentComp := func(rent float64, sqft, beds int, baths float64) models.RentComp {
+		return models.RentComp{
+			MonthlyRent: rent, ListingDate: asOf,
+			BuildingAreaSqft: intPtr(sqft), Bedrooms: intPtr(beds), Bathrooms: float64Ptr(baths),
+		}
+	}
+	// Rents follow 1000 + 300 per bedroom + 200 per bathroom at $2/sqft
+	comps := []models.RentComp{
+		rentComp(1800, 900, 2, 1),
+		rentComp(2300, 1150, 3, 2),
+		rentComp(2100, 1050, 3, 1),
+		rentComp(2600, 1300, 4, 2),
+	}
+	subject := &models.Property{
+		BuildingAreaSqft: intPtr(1200),
+		Bedrooms:         intPtr(3),
+		Bathrooms:        float64Ptr(2),
+		IntendedRent:     float64Ptr(2500),
+	}
+
+	estimate := models.EstimateRent(subject, comps, 0, asOf)
+	assert.Equal(t, 4, estimate.Count)
+	assert.Equal(t, 2200.0, estimate.MedianRent)
+
+	require.NotNil(t, estimate.PerSqft)
+	assert.Equal(t, 2.0, estimate.PerSqft.MedianRentPerSqft)
+	assert.Equal(t, 2400.0, estimate.PerSqft.Rent)
+
+	regression := estimate.Regression
+	require.NotNil(t, regression)
+	assert.Equal(t, 4, regression.Observations)
+	assert.Equal(t, 1000.0, regression.Intercept)
+	assert.Equal(t, 300.0, *regression.PerBedroom)
+	assert.Equal(t, 200.0, *regression.PerBathroom)
+	assert.Equal(t, 1.0, regression.RSquared)
+	assert.Equal(t, 2300.0, regression.Rent)
+
+	require.NotNil(t, estimate.SuggestedRent)
+	assert.Equal(t, 2350.0, *estimate.SuggestedRent, "the methods are averaged")
+	assert.Equal(t, 300.0, *estimate.IntendedDeviation)
+	assert.Equal(t, 13.64, *estimate.IntendedDeviationPercent)
+
+	valuation, err := estimate.ToValuation()
+	require.NoError(t, err)
+	assert.Equal(t, models.ValuationSourceComps, valuation.Source)
+	assert.Equal(t, models.ValuationTypeRentalEstimate, valuation.ValuationType)
+	assert.Equal(t, 2350.0, valuation.Value)
+	assert.Equal(t, 2300.0, *valuation.ValueLow)
+	assert.Equal(t, 2400.0, *valuation.ValueHigh)
+}
+
+func TestEstimateRentFallbacks(t *testing.T) {
+	asOf := time.Date(2024, 6, 30, 0, 0, 0, 0, time.UTC)
+	yearAgo := asOf.Add(-365*24*time.Hour - 6*time.Hour)
+
+	// Every comp has two bathrooms, so only bedrooms are fitted
+	comps := []models.RentComp{
+		{MonthlyRent: 1600, ListingDate: asOf, Bedrooms: intPtr(2), Bathrooms: float64Ptr(2)},
+		{MonthlyRent: 1900, ListingDate: asOf, Bedrooms: intPtr(3), Bathrooms: float64Ptr(2)},
+		{MonthlyRent: 2200, ListingDate: asOf, Bedrooms: intPtr(4), Bathrooms: float64Ptr(2)},
+		{MonthlyRent: 1000, ListingDate: yearAgo},
+	}
+	subject := &models.Property{Bedrooms: intPtr(5), Bathrooms: float64Ptr(1)}
+
+	estimate := models.EstimateRent(subject, comps, 3, asOf)
+	assert.Equal(t, 1030.0, estimate.Comps[3].AdjustedRent, "a year of 3% rent growth")
+	assert.Nil(t, estimate.Comps[3].AdjustedRentPerSqft)
+	assert.Nil(t, estimate.PerSqft, "the subject's size is unknown")
+	assert.Nil(t, estimate.IntendedDeviation)
+
+	require.NotNil(t, estimate.Regression)
+	assert.Equal(t, 3, estimate.Regression.Observations, "comps without room counts are left out")
+	assert.Equal(t, 300.0, *estimate.Regression.PerBedroom)
+	assert.Nil(t, estimate.Regression.PerBathroom)
+	assert.Equal(t, 2500.0, estimate.Regression.Rent)
+	assert.Equal(t, 2500.0, *estimate.SuggestedRent)
+
+	valuation, err := estimate.ToValuation()
+	require.NoError(t, err)
+	assert.Nil(t, valuation.ValueLow, "one method gives no range")
+
+	// Two comps cannot support a fit, and without sizes there is no estimate
+	estimate = models.EstimateRent(subject, comps[:2], 3, asOf)
+	assert.Nil(t, estimate.Regression)
+	assert.Nil(t, estimate.SuggestedRent)
+	_, err = estimate.ToValuation()
+	assert.Error(t, err)
+}
+
+func TestEstimateRentMedianUsesAdjustedRents(t *testing.T) {
+	asOf := time.Date(2024, 6, 30, 0, 0, 0, 0, time.UTC)
+	yearAgo := asOf.Add(-365*24*time.Hour - 6*time.Hour)
+	comps := []models.RentComp{
+		{MonthlyRent: 1000, ListingDate: asOf},
+		{MonthlyRent: 2000, ListingDate: yearAgo},
+		{MonthlyRent: 3000, ListingDate: asOf},
+	}
+	subject := &models.Property{IntendedRent: float64Ptr(2163)}
+
+	estimate := models.EstimateRent(subject, comps, 3, asOf)
+	assert.Equal(t, 2060.0, estimate.MedianRent, "the stale listing is brought forward a year")
+	assert.Equal(t, 103.0, *estimate.IntendedDeviation)
+	assert.Equal(t, 5.0, *estimate.IntendedDeviationPercent)
+}
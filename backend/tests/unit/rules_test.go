@@ -0,0 +1,121 @@
+package unit
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"rental-property-mgmt/internal/models"
+	"rental-property-mgmt/pkg/rules"
+)
+
+func TestRulesParse_SyntaxErrors(t *testing.T) {
+	cases := map[string]string{
+		"":                          "column 1: rule is empty",
+		"cap_rate >=":               "column 12: expected a number, field or ( but found end of rule",
+		"cap_rate >= 8 and":         "column 18: expected a number, field or ( but found end of rule",
+		"(cap_rate >= 8":            "column 15: expected ) but found end of rule",
+		"cap_rate":                  "column 1: rule must be a comparison such as cap_rate >= 8",
+		"cap_rate >= 8 8":           `column 15: unexpected "8"; expected and, or, or end of rule`,
+		"1 < cap_rate < 9":          "column 14: comparisons cannot be chained; combine them with and",
+		"cap_rate >= 8 # comment":   `column 15: unexpected character '#'`,
+		"cap_rate + (dscr > 1) > 2": "column 10: + needs numbers on both sides",
+	}
+	for source, want := range cases {
+		_, err := rules.Parse(source, models.RuleFields)
+		require.Error(t, err, source)
+		assert.Equal(t, want, err.Error(), source)
+
+		var syntaxErr *rules.SyntaxError
+		assert.ErrorAs(t, err, &syntaxErr)
+	}
+}
+
+func TestRulesParse_UnknownField(t *testing.T) {
+	_, err := rules.Parse("price per foot < 150", models.RuleFields)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `column 1: unknown field "price_per_foot"`)
+	assert.Contains(t, err.Error(), "price_per_sqft")
+}
+
+func TestRulesEvaluate(t *testing.T) {
+	expression, err := rules.Parse("DSCR >= 1.25 OR cash_on_cash >= 10", models.RuleFields)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"cash_on_cash", "dscr"}, expression.Fields())
+
+	assert.Equal(t, rules.True, expression.Evaluate(rules.Values{"dscr": float64Ptr(1.3)}), "unknown or true is true")
+	assert.Equal(t, rules.Unknown, expression.Evaluate(rules.Values{"dscr": float64Ptr(1.1)}))
+	assert.Equal(t, rules.False, expression.Evaluate(rules.Values{"dscr": float64Ptr(1.1), "cash_on_cash": float64Ptr(8)}))
+
+	arithmetic, err := rules.Parse("not (purchase_price / 1_000 > 250) and -cap_rate <= -6", models.RuleFields)
+	require.NoError(t, err)
+	assert.Equal(t, rules.True, arithmetic.Evaluate(rules.Values{"purchase_price": float64Ptr(200000), "cap_rate": float64Ptr(7)}))
+	assert.Equal(t, rules.False, arithmetic.Evaluate(rules.Values{"purchase_price": float64Ptr(300000), "cap_rate": float64Ptr(7)}))
+}
+
+func TestCompareProperty_CustomRules(t *testing.T) {
+	criteria := &models.BuyingBoxCriteria{
+		CustomRules: models.CustomRules{
+			{Name: "cheap per foot", Expression: "price per sqft < 150"},
+			{Name: "debt coverage", Expression: "dscr >= 1.25 or cash_on_cash >= 10"},
+		},
+		ScoringRules: models.CriterionRules{
+			"rule:cheap per foot": {Hard: true},
+		},
+	}
+	require.NoError(t, criteria.CustomRules.Validate())
+
+	property := &models.Property{PurchasePrice: 200000, BuildingAreaSqft: intPtr(1600)}
+	metrics := &models.FinancialMetrics{
+		NetOperatingIncome:     float64Ptr(18000),
+		MonthlyMortgagePayment: float64Ptr(1000),
+		CashOnCashReturn:       float64Ptr(6),
+	}
+
+	comparison := criteria.CompareProperty(property, metrics)
+
+	// $125/sqft and DSCR 1.5
+	assert.True(t, comparison.Matches["rule:cheap per foot"])
+	assert.True(t, comparison.Matches["rule:debt coverage"])
+	assert.True(t, comparison.MeetsCriteria)
+	assert.Contains(t, comparison.Contributions[0].Explanation, "price_per_sqft = 125")
+
+	expensive := criteria.CompareProperty(&models.Property{PurchasePrice: 300000, BuildingAreaSqft: intPtr(1600)}, metrics)
+	assert.True(t, expensive.HardFailure)
+	assert.Contains(t, expensive.FailureReasons, `Custom rule "cheap per foot" not met`)
+
+	noArea := criteria.CompareProperty(&models.Property{PurchasePrice: 200000}, nil)
+	assert.ElementsMatch(t, []string{"rule:cheap per foot", "rule:debt coverage"}, noArea.UnknownCriteria)
+	assert.Contains(t, noArea.FailureReasons, "Missing price_per_sqft")
+}
+
+func TestCompareProperty_CustomRuleThatCannotBeEvaluated(t *testing.T) {
+	criteria := &models.BuyingBoxCriteria{
+		CustomRules: models.CustomRules{
+			{Name: "rent over premium", Expression: "intended_rent / (purchase_price - 200000) > 0.01"},
+		},
+	}
+
+	// Every field is known, but the rule divides by zero
+	comparison := criteria.CompareProperty(&models.Property{PurchasePrice: 200000, IntendedRent: float64Ptr(1800)}, nil)
+
+	assert.False(t, comparison.Matches["rule:rent over premium"])
+	assert.Empty(t, comparison.UnknownCriteria, "no field is missing")
+	require.Len(t, comparison.Contributions, 1)
+	assert.Equal(t,
+		"intended_rent / (purchase_price - 200000) > 0.01 (intended_rent = 1800, purchase_price = 200000): could not be evaluated: no credit",
+		comparison.Contributions[0].Explanation)
+}
+
+func TestCustomRulesValidate(t *testing.T) {
+	err := models.CustomRules{{Name: "too pricey", Expression: "purchase_price <"}}.Validate()
+	require.Error(t, err)
+	assert.Equal(t, `custom rule "too pricey": column 17: expected a number, field or ( but found end of rule`, err.Error())
+
+	err = models.CustomRules{
+		{Name: "dup", Expression: "cap_rate > 5"},
+		{Name: "DUP", Expression: "cap_rate > 6"},
+	}.Validate()
+	assert.EqualError(t, err, `custom rule "DUP" is defined more than once`)
+}
@@ -0,0 +1,130 @@
+package unit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"rental-property-mgmt/internal/models"
+	"rental-property-mgmt/internal/repository"
+	"rental-property-mgmt/internal/services"
+	"rental-property-mgmt/pkg/database"
+	"rental-property-mgmt/tests/testutil"
+)
+
+func TestHighlightSnippet(t *testing.T) {
+	assert.Equal(t,
+		"Cracked <mark>foundation</mark> along the east wall",
+		services.HighlightSnippet("Cracked foundation along the east wall"))
+
+	assert.Equal(t,
+		"&lt;script&gt;<mark>foundation</mark>&lt;/script&gt; &amp; more",
+		services.HighlightSnippet("<script>foundation</script> & more"),
+		"stored text is escaped, only highlight markers become tags")
+
+	assert.Equal(t, "no match", services.HighlightSnippet("  no match \n"))
+}
+
+// searchFixture holds two properties with comments, one of them deleted
+type searchFixture struct {
+	store *repository.Store
+	user  *models.User
+	maple *models.Property
+	oak   *models.Property
+}
+
+func newSearchFixture(t *testing.T, db *gorm.DB) *searchFixture {
+	_, err := database.MigrateUp(db)
+	require.NoError(t, err)
+	f := &searchFixture{store: repository.NewGormStore(db)}
+
+	f.user = &models.User{Email: "ana@example.com", PasswordHash: "x", FirstName: "Ana", LastName: "Lee"}
+	require.NoError(t, f.store.Users.Create(f.user))
+	f.maple = &models.Property{
+		UserID: f.user.ID, Address: "12 Maple Street", PurchasePrice: 210000,
+		LocalContext: models.JSONB{"notes": "Quiet street near the park"},
+	}
+	f.oak = &models.Property{UserID: f.user.ID, Address: "98 Oak Avenue", PurchasePrice: 185000}
+	require.NoError(t, f.store.Properties.Create(f.maple))
+	require.NoError(t, f.store.Properties.Create(f.oak))
+
+	f.comment(t, f.oak, "Inspector found a foundation issue in the 50% finished basement")
+	deleted := f.comment(t, f.maple, "Foundation looked fine to me")
+	now := time.Now()
+	deleted.DeletedAt = &now
+	require.NoError(t, db.Save(deleted).Error)
+	return f
+}
+
+func (f *searchFixture) comment(t *testing.T, property *models.Property, content string) *models.Comment {
+	c := &models.Comment{PropertyID: property.ID, UserID: f.user.ID, ThreadID: uuid.New(), Content: content}
+	require.NoError(t, f.store.Comments.Create(c))
+	return c
+}
+
+func (f *searchFixture) search(t *testing.T, text string, ownerID *uuid.UUID) []repository.SearchHit {
+	hits, total, err := f.store.Search.Search(repository.SearchQuery{
+		Text: text, Properties: true, Comments: true, OwnerID: ownerID, Limit: 10,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int64(len(hits)), total)
+	return hits
+}
+
+// assertSearchMatchesWords checks the search behaviour both drivers share
+func assertSearchMatchesWords(t *testing.T, f *searchFixture) {
+	hits := f.search(t, "foundation issues", &f.user.ID)
+	require.Len(t, hits, 1, "words are stemmed and deleted comments are skipped")
+	assert.Equal(t, repository.SearchTypeComment, hits[0].Type)
+	assert.Equal(t, f.oak.ID, hits[0].PropertyID)
+	assert.Contains(t, hits[0].Snippet, repository.HighlightStart+"issue"+repository.HighlightStop)
+
+	hits = f.search(t, "park", &f.user.ID)
+	require.Len(t, hits, 1, "local context notes are searched")
+	assert.Equal(t, f.maple.ID, hits[0].ID)
+
+	assert.Len(t, f.search(t, "maple or oak", &f.user.ID), 2)
+	assert.Len(t, f.search(t, "street -quiet", &f.user.ID), 0)
+	assert.Len(t, f.search(t, `"maple street"`, &f.user.ID), 1)
+}
+
+func TestSQLiteSearchMatchesWords(t *testing.T) {
+	db, err := database.Open(database.Config{Driver: database.DriverSQLite, Path: ":memory:"})
+	require.NoError(t, err)
+	defer database.Close(db)
+	f := newSearchFixture(t, db)
+
+	assertSearchMatchesWords(t, f)
+	assert.Len(t, f.search(t, "50%", &f.user.ID), 1, "LIKE wildcards in the query are literal")
+	assert.Len(t, f.search(t, "5_%", &f.user.ID), 0)
+}
+
+func TestPostgresSearchMatchesWords(t *testing.T) {
+	db, err := database.Open(testutil.PostgresSchema(t))
+	require.NoError(t, err)
+	defer database.Close(db)
+	f := newSearchFixture(t, db)
+
+	assertSearchMatchesWords(t, f)
+
+	// Addresses weigh more than local context notes
+	lane := &models.Property{UserID: f.user.ID, Address: "7 Park Lane", PurchasePrice: 150000}
+	require.NoError(t, f.store.Properties.Create(lane))
+	hits := f.search(t, "park", &f.user.ID)
+	require.Len(t, hits, 2)
+	assert.Equal(t, lane.ID, hits[0].ID)
+	assert.Greater(t, hits[0].Rank, hits[1].Rank)
+
+	// OwnerID keeps to one user's properties and their comments
+	other := &models.User{Email: "ben@example.com", PasswordHash: "x", FirstName: "Ben", LastName: "Ng"}
+	require.NoError(t, f.store.Users.Create(other))
+	require.NoError(t, f.store.Properties.Create(&models.Property{
+		UserID: other.ID, Address: "3 Park Road", PurchasePrice: 120000,
+	}))
+	assert.Len(t, f.search(t, "park", &f.user.ID), 2)
+	assert.Len(t, f.search(t, "park", nil), 3)
+}
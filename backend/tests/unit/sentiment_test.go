@@ -0,0 +1,81 @@
+package unit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"rental-property-mgmt/internal/models"
+)
+
+func TestNewVoteTally(t *testing.T) {
+	tests := []struct {
+		name                string
+		pass, maybe, pursue int
+		leading             *string
+	}{
+		{"no votes", 0, 0, 0, nil},
+		{"single vote", 0, 0, 1, stringPtr(models.DecisionPursue)},
+		{"clear plurality", 1, 1, 3, stringPtr(models.DecisionPursue)},
+		{"tie at the top", 2, 0, 2, nil},
+		{"tie below the leader", 1, 3, 1, stringPtr(models.DecisionMaybe)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tally := models.NewVoteTally(tt.pass, tt.maybe, tt.pursue)
+			assert.Equal(t, tt.pass+tt.maybe+tt.pursue, tally.Total)
+			assert.Equal(t, tt.leading, tally.Leading)
+		})
+	}
+}
+
+func TestTallyVotes(t *testing.T) {
+	me, teammate := uuid.New(), uuid.New()
+	votes := []models.PropertyVote{
+		{UserID: me, Decision: models.DecisionMaybe},
+		{UserID: teammate, Decision: models.DecisionPursue},
+		{UserID: uuid.New(), Decision: models.DecisionPursue},
+	}
+
+	tally := models.TallyVotes(votes, me)
+	assert.Equal(t, 1, tally.Maybe)
+	assert.Equal(t, 2, tally.Pursue)
+	assert.Equal(t, stringPtr(models.DecisionPursue), tally.Leading)
+	assert.Equal(t, stringPtr(models.DecisionMaybe), tally.MyVote)
+
+	assert.Nil(t, models.TallyVotes(votes, uuid.New()).MyVote)
+}
+
+func TestIsEmoji(t *testing.T) {
+	valid := []string{"👍", "🔥", "❤️", "👍🏽", "👩‍👩‍👧", "🇺🇸", "1️⃣", "#⃣", "✅", "🏴\U000e0067\U000e0062\U000e0073\U000e0063\U000e0074\U000e007f"}
+	for _, emoji := range valid {
+		assert.True(t, models.IsEmoji(emoji), "%q should be accepted", emoji)
+	}
+
+	invalid := []string{"", "a", "ok", "1", "👍 nice", "<b>", "👍👎🎉", "👍🏽👍", "🇺🇸🇨🇦", "🇺", "👩‍", "‍👩", "1️⃣2️⃣", "👍👍👍👍👍👍👍👍👍👍👍"}
+	for _, emoji := range invalid {
+		assert.False(t, models.IsEmoji(emoji), "%q should be rejected", emoji)
+	}
+}
+
+func TestSummarizeReactions(t *testing.T) {
+	start := time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC)
+	ann, bob := uuid.New(), uuid.New()
+	reactions := []models.CommentReaction{
+		{Emoji: "🔥", UserID: bob, CreatedAt: start.Add(2 * time.Minute)},
+		{Emoji: "👍", UserID: ann, CreatedAt: start},
+		{Emoji: "👍", UserID: bob, CreatedAt: start.Add(time.Minute)},
+	}
+
+	summaries := models.SummarizeReactions(reactions)
+	require.Len(t, summaries, 2)
+	assert.Equal(t, models.ReactionSummary{Emoji: "👍", Count: 2, UserIDs: []uuid.UUID{ann, bob}}, summaries[0])
+	assert.Equal(t, models.ReactionSummary{Emoji: "🔥", Count: 1, UserIDs: []uuid.UUID{bob}}, summaries[1])
+
+	assert.Empty(t, models.SummarizeReactions(nil))
+	assert.NotNil(t, models.SummarizeReactions(nil))
+}
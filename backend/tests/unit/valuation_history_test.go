@@ -0,0 +1,74 @@
+package unit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"rental-property-mgmt/internal/models"
+)
+
+func TestBuildValuationHistory(t *testing.T) {
+	day := func(month time.Month, d int) time.Time { return time.Date(2024, month, d, 0, 0, 0, 0, time.UTC) }
+	recorded := func(hour int) time.Time { return time.Date(2024, 6, 2, hour, 0, 0, 0, time.UTC) }
+	market := func(source string, value float64, date time.Time, createdAt time.Time) models.PropertyValuation {
+		return models.PropertyValuation{
+			Source: source, ValuationType: models.ValuationTypeMarketValue,
+			Value: value, ValuationDate: date, CreatedAt: createdAt,
+		}
+	}
+
+	valuations := []models.PropertyValuation{
+		market("Zillow", 300000, day(time.January, 1), recorded(1)),
+		market("Zillow", 312000, day(time.June, 1), recorded(3)),
+		market("Zillow", 306000, day(time.April, 1), recorded(1)),
+		market("Zillow", 999, day(time.June, 1), recorded(2)),
+		market("Redfin", 298000, day(time.January, 1), recorded(1)),
+		market("Redfin", 310000, day(time.June, 1), recorded(1)),
+		market("Rentimate", 450000, day(time.June, 1), recorded(1)),
+		{Source: "Zillow", ValuationType: models.ValuationTypeRentalEstimate, Value: 2000, ValuationDate: day(time.June, 1)},
+	}
+
+	histories := models.BuildValuationHistory(valuations, models.DefaultOutlierSigma, models.DefaultTrailingWindows)
+	require.Len(t, histories, 2)
+
+	history := histories[0]
+	assert.Equal(t, models.ValuationTypeMarketValue, history.ValuationType)
+	assert.Equal(t, 1, history.Outliers)
+	require.Len(t, history.Series, 3)
+	assert.Equal(t, "Redfin", history.Series[0].Source, "series are ordered by source")
+
+	rentimate := history.Series[1]
+	require.Len(t, rentimate.Points, 1)
+	assert.True(t, rentimate.Latest.Outlier, "a bogus estimate is flagged")
+	assert.Equal(t, 311000.0, *rentimate.Latest.PeerConsensus)
+	assert.Equal(t, 44.69, *rentimate.Latest.Deviation, "agreeing peers' deviation is floored at 1% of their consensus")
+	assert.Equal(t, 1, rentimate.Outliers)
+
+	zillow := history.Series[2]
+	require.Len(t, zillow.Points, 3, "a later record on the same date replaces the earlier one")
+	assert.Nil(t, zillow.Points[0].ChangePercent)
+	assert.Nil(t, zillow.Points[0].Deviation, "one peer is too few to judge")
+	assert.Equal(t, 2.0, *zillow.Points[1].ChangePercent)
+	assert.Equal(t, 1.96, *zillow.Points[2].ChangePercent)
+	assert.Equal(t, 312000.0, zillow.Latest.Value)
+	assert.False(t, zillow.Latest.Outlier)
+	assert.Equal(t, -0.97, *zillow.Latest.Deviation, "the outlier widens the peers' deviation")
+
+	require.Len(t, zillow.Trailing, 3)
+	assert.Equal(t, 30, zillow.Trailing[0].Days)
+	assert.Equal(t, day(time.April, 1), *zillow.Trailing[0].BaseDate)
+	assert.Equal(t, 1.96, *zillow.Trailing[0].ChangePercent)
+	assert.Equal(t, 300000.0, *zillow.Trailing[1].BaseValue)
+	assert.Equal(t, 4.0, *zillow.Trailing[1].ChangePercent)
+	assert.Nil(t, zillow.Trailing[2].ChangePercent, "no value a year before the latest")
+
+	// A looser threshold accepts the estimate
+	histories = models.BuildValuationHistory(valuations, 50, nil)
+	assert.Equal(t, 0, histories[0].Outliers)
+	assert.Empty(t, histories[0].Series[0].Trailing)
+
+	assert.Empty(t, models.BuildValuationHistory(nil, models.DefaultOutlierSigma, nil))
+}
@@ -0,0 +1,116 @@
+package unit
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"rental-property-mgmt/internal/models"
+	"rental-property-mgmt/internal/services"
+)
+
+func TestParseValuationCSV(t *testing.T) {
+	asOf := time.Date(2024, 6, 30, 0, 0, 0, 0, time.UTC)
+	csv := "\ufeffStreet Address,City,Zip,Zestimate,As Of\n" +
+		"12 Oak Street,Springfield,62701,\"$250,000\",05/01/2024\n" +
+		"\n" +
+		"40 Elm Ave,Springfield,62702,n/a,2024-05-01\n" +
+		"9 Pine Rd,Springfield,62703,199000,2024-07-01\n" +
+		",Springfield,62704,180000,\n" +
+		"3 Birch Ln,Springfield,62705,175000\n"
+
+	rows, err := services.ParseValuationCSV(strings.NewReader(csv), services.ValuationImportMapping{
+		AddressColumn: "street address",
+		CityColumn:    "City",
+		ZipColumn:     "ZIP",
+		ValueColumn:   "Zestimate",
+		DateColumn:    "as of",
+		Source:        "Zillow",
+		ValuationType: models.ValuationTypeMarketValue,
+	}, asOf)
+	require.NoError(t, err)
+	require.Len(t, rows, 5, "blank lines are skipped")
+
+	first := rows[0]
+	assert.Equal(t, 2, first.Line)
+	assert.Equal(t, "12 Oak Street, Springfield, 62701", first.Address)
+	assert.Empty(t, first.Status, "accepted rows await matching")
+	require.NotNil(t, first.Valuation)
+	assert.Equal(t, 250000.0, first.Valuation.Value)
+	assert.Equal(t, "Zillow", first.Valuation.Source)
+	assert.Equal(t, time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC), first.Valuation.ValuationDate)
+
+	assert.Equal(t, 4, rows[1].Line, "line numbers count the skipped blank line")
+	for i, reason := range map[int]string{1: "positive amount", 2: "future", 3: "address is empty"} {
+		assert.Equal(t, services.ImportRowRejected, rows[i].Status)
+		assert.Contains(t, rows[i].Reason, reason)
+		assert.Nil(t, rows[i].Valuation)
+	}
+
+	require.NotNil(t, rows[4].Valuation, "short rows are padded")
+	assert.Equal(t, asOf, rows[4].Valuation.ValuationDate, "a missing date means today")
+}
+
+func TestParseValuationCSVColumns(t *testing.T) {
+	asOf := time.Date(2024, 6, 30, 0, 0, 0, 0, time.UTC)
+	csv := "address,value,source,type\n" +
+		"12 Oak St,1900,Redfin,Rental Estimate\n" +
+		"12 Oak St,250000,,market-value\n" +
+		"12 Oak St,250000,Redfin,appraisal\n"
+
+	rows, err := services.ParseValuationCSV(strings.NewReader(csv), services.ValuationImportMapping{
+		SourceColumn: "source",
+		TypeColumn:   "type",
+		Source:       "Zillow",
+	}, asOf)
+	require.NoError(t, err, "address and value columns are found by default")
+	require.Len(t, rows, 3)
+	assert.Equal(t, "Redfin", rows[0].Valuation.Source)
+	assert.Equal(t, models.ValuationTypeRentalEstimate, rows[0].Valuation.ValuationType)
+	assert.Equal(t, "Zillow", rows[1].Valuation.Source, "an empty cell falls back to the given source")
+	assert.Equal(t, models.ValuationTypeMarketValue, rows[1].Valuation.ValuationType)
+	assert.Equal(t, services.ImportRowRejected, rows[2].Status)
+
+	for name, tt := range map[string]struct {
+		csv     string
+		mapping services.ValuationImportMapping
+	}{
+		"empty file":         {"", services.ValuationImportMapping{Source: "Zillow", ValuationType: "market_value"}},
+		"no address column":  {"street,value\n", services.ValuationImportMapping{Source: "Zillow", ValuationType: "market_value"}},
+		"missing mapped col": {"address,value\n", services.ValuationImportMapping{DateColumn: "date", Source: "Zillow", ValuationType: "market_value"}},
+		"no source":          {"address,value\n", services.ValuationImportMapping{ValuationType: "market_value"}},
+		"no type":            {"address,value\n", services.ValuationImportMapping{Source: "Zillow"}},
+	} {
+		_, err := services.ParseValuationCSV(strings.NewReader(tt.csv), tt.mapping, asOf)
+		assert.ErrorIs(t, err, services.ErrInvalidInput, name)
+	}
+}
+
+func TestAddressIndex(t *testing.T) {
+	city, state, zip := "Springfield", "IL", "62701"
+	oak := models.Property{ID: uuid.New(), Address: "12 Oak Street, Springfield, IL 62701"}
+	elm := models.Property{ID: uuid.New(), Address: "40 Elm Ave", City: &city, State: &state, ZipCode: &zip}
+	elmElsewhere := models.Property{ID: uuid.New(), Address: "40 Elm Ave, Shelbyville, IL 62565"}
+	index := services.NewAddressIndex([]models.Property{oak, elm, elmElsewhere})
+
+	id, reason := index.Match("12 OAK ST., SPRINGFIELD IL 62701-0001")
+	assert.Equal(t, oak.ID, id, reason)
+
+	id, _ = index.Match("12 Oak St")
+	assert.Equal(t, oak.ID, id, "a unique street address matches on its own")
+
+	id, _ = index.Match("40 Elm Avenue, Springfield, IL 62701")
+	assert.Equal(t, elm.ID, id, "street plus address components match")
+
+	id, reason = index.Match("40 Elm Ave")
+	assert.Equal(t, uuid.Nil, id)
+	assert.Contains(t, reason, "more than one")
+
+	id, reason = index.Match("1 Nowhere Rd")
+	assert.Equal(t, uuid.Nil, id)
+	assert.Contains(t, reason, "no property")
+}
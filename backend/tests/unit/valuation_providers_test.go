@@ -0,0 +1,111 @@
+package unit
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"rental-property-mgmt/internal/models"
+	"rental-property-mgmt/internal/valuations"
+)
+
+const valuationFixture = `{
+  "source": "Fixture",
+  "as_of": "2024-05-01",
+  "estimates": [
+    {"address": "12 Oak Street, Springfield, IL 62701", "market_value": 250000, "rent_estimate": 1900},
+    {"address": "40 Elm Ave, Springfield, IL 62702", "rent_estimate": 1500, "as_of": "2024-05-20"}
+  ]
+}`
+
+func TestNormalizeAddress(t *testing.T) {
+	tests := []struct {
+		address  string
+		expected string
+	}{
+		{"12 Oak Street, Springfield, IL 62701", "12 oak st springfield il 62701"},
+		{"12 oak st.  springfield il 62701-1234", "12 oak st springfield il 62701"},
+		{"500 North Main Avenue, Apartment 4B", "500 n main ave apt 4b"},
+		{"  ", ""},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, models.NormalizeAddress(tt.address), tt.address)
+	}
+}
+
+func TestValuationRegistry(t *testing.T) {
+	fixture, err := valuations.ParseFixture([]byte(valuationFixture))
+	require.NoError(t, err)
+
+	registry := valuations.NewRegistry(valuations.ManualProvider("Zillow"), valuations.ManualProvider("Redfin"), fixture)
+	assert.Equal(t, []string{"Fixture", "Redfin", "Zillow"}, registry.Sources())
+	assert.True(t, registry.Has("Zillow"))
+	assert.False(t, registry.Has("Zestimate"))
+
+	fetchers := registry.Fetchers()
+	require.Len(t, fetchers, 1, "manual sources never fetch")
+	assert.Equal(t, "Fixture", fetchers[0].Name())
+
+	// A provider registered under an existing name replaces it
+	zillow, err := valuations.ParseFixture([]byte(`{"source": "Zillow", "estimates": []}`))
+	require.NoError(t, err)
+	registry.Register(zillow)
+	assert.Len(t, registry.Sources(), 3)
+	assert.Len(t, registry.Fetchers(), 2)
+}
+
+func TestFileProvider(t *testing.T) {
+	provider, err := valuations.ParseFixture([]byte(valuationFixture))
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	quote, err := provider.Fetch(ctx, "12 oak st, springfield, il 62701")
+	require.NoError(t, err, "addresses match after normalization")
+	require.NotNil(t, quote.MarketValue)
+	assert.Equal(t, 250000.0, *quote.MarketValue)
+	assert.Equal(t, 1900.0, *quote.RentEstimate)
+	assert.Equal(t, time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC), quote.AsOf)
+
+	quote, err = provider.Fetch(ctx, "40 Elm Avenue, Springfield, IL 62702")
+	require.NoError(t, err)
+	assert.Nil(t, quote.MarketValue)
+	assert.Equal(t, time.Date(2024, 5, 20, 0, 0, 0, 0, time.UTC), quote.AsOf, "an estimate's own date wins")
+
+	_, err = provider.Fetch(ctx, "1 Unknown Rd")
+	assert.ErrorIs(t, err, valuations.ErrNoEstimate)
+}
+
+func TestParseFixtureErrors(t *testing.T) {
+	for name, fixture := range map[string]string{
+		"not json":       `{`,
+		"no source":      `{"estimates": []}`,
+		"bad date":       `{"source": "X", "as_of": "05/01/2024"}`,
+		"no address":     `{"source": "X", "estimates": [{"market_value": 1}]}`,
+		"negative value": `{"source": "X", "estimates": [{"address": "1 A St", "rent_estimate": -5}]}`,
+		"comps source":   `{"source": "Comps", "estimates": []}`,
+	} {
+		_, err := valuations.ParseFixture([]byte(fixture))
+		assert.Error(t, err, name)
+	}
+}
+
+func TestValuationRegistryFromEnv(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "zillow.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"source": "Zillow", "estimates": []}`), 0o600))
+	t.Setenv("VALUATION_FIXTURES", path)
+
+	registry, err := valuations.FromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Redfin", "Rentimate", "Zillow"}, registry.Sources())
+	require.Len(t, registry.Fetchers(), 1, "the fixture stands in for Zillow")
+
+	t.Setenv("VALUATION_FIXTURES", filepath.Join(t.TempDir(), "missing.json"))
+	_, err = valuations.FromEnv()
+	assert.Error(t, err)
+}
@@ -0,0 +1,71 @@
+package unit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"rental-property-mgmt/internal/models"
+)
+
+func TestSummarizeValuations(t *testing.T) {
+	asOf := time.Date(2024, 6, 30, 0, 0, 0, 0, time.UTC)
+	day := func(month time.Month, d int) time.Time { return time.Date(2024, month, d, 0, 0, 0, 0, time.UTC) }
+
+	valuations := []models.PropertyValuation{
+		{Source: "Zillow", ValuationType: models.ValuationTypeMarketValue, Value: 300000, ValuationDate: day(time.January, 15)},
+		{Source: "Zillow", ValuationType: models.ValuationTypeMarketValue, Value: 310000, ValuationDate: day(time.June, 1)},
+		{Source: "Redfin", ValuationType: models.ValuationTypeMarketValue, Value: 290000, ValuationDate: day(time.May, 1)},
+		{Source: "Rentimate", ValuationType: models.ValuationTypeMarketValue, Value: 320000, ValuationDate: day(time.June, 20)},
+		{Source: "Rentimate", ValuationType: models.ValuationTypeRentalEstimate, Value: 2100, ValuationDate: day(time.June, 30)},
+		{Source: "Zillow", ValuationType: models.ValuationTypeRentalEstimate, Value: 2300, ValuationDate: day(time.June, 10)},
+	}
+
+	summaries := models.SummarizeValuations(valuations, asOf)
+	require.Len(t, summaries, 2)
+
+	market := summaries[0]
+	assert.Equal(t, models.ValuationTypeMarketValue, market.ValuationType)
+	assert.Equal(t, 3, market.Sources, "only the latest valuation per source counts")
+	require.Len(t, market.Latest, 3)
+	assert.Equal(t, "Redfin", market.Latest[0].Source, "latest values are ordered by source")
+	assert.Equal(t, 310000.0, market.Latest[2].Value)
+	assert.Equal(t, 306666.67, market.Mean)
+	assert.Equal(t, 310000.0, market.Median)
+	assert.Equal(t, 290000.0, market.Min)
+	assert.Equal(t, 320000.0, market.Max)
+	assert.Equal(t, 30000.0, market.Spread)
+	assert.Equal(t, 9.78, market.SpreadPercent)
+	assert.Equal(t, day(time.June, 20), market.NewestDate)
+	assert.Equal(t, day(time.May, 1), market.OldestDate)
+	assert.Equal(t, 10, market.NewestAgeDays)
+	assert.Equal(t, 60, market.OldestAgeDays)
+
+	rent := models.FindValuationSummary(summaries, models.ValuationTypeRentalEstimate)
+	require.NotNil(t, rent)
+	assert.Equal(t, 2200.0, rent.Median, "an even number of sources averages the middle two")
+	assert.Equal(t, 2200.0, rent.Consensus())
+	assert.Equal(t, 0, rent.NewestAgeDays)
+}
+
+func TestSummarizeValuationsBreaksDateTiesByRecordTime(t *testing.T) {
+	date := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	valuations := []models.PropertyValuation{
+		{Source: "Zillow", ValuationType: models.ValuationTypeMarketValue, Value: 200000, ValuationDate: date, CreatedAt: date.Add(time.Hour)},
+		{Source: "Zillow", ValuationType: models.ValuationTypeMarketValue, Value: 205000, ValuationDate: date, CreatedAt: date.Add(2 * time.Hour)},
+	}
+
+	summaries := models.SummarizeValuations(valuations, date)
+	require.Len(t, summaries, 1)
+	assert.Equal(t, 205000.0, summaries[0].Mean)
+	assert.Equal(t, 0.0, summaries[0].Spread)
+	assert.Nil(t, models.FindValuationSummary(summaries, models.ValuationTypeRentalEstimate))
+}
+
+func TestSummarizeValuationsEmpty(t *testing.T) {
+	summaries := models.SummarizeValuations(nil, time.Now())
+	assert.NotNil(t, summaries)
+	assert.Empty(t, summaries)
+}
@@ -184,8 +184,8 @@ class RentalPropertyApp {
 
   async loadProperties() {
     try {
-      const properties = await apiClient.getProperties();
-      this.displayProperties(properties);
+      const response = await apiClient.getProperties();
+      this.displayProperties(response.properties);
     } catch (error) {
       console.error('Failed to load properties:', error);
     }